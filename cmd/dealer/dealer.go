@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/dealer"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/keystore"
+)
+
+const maxN = 100
+
+func usage() {
+	cmd := filepath.Base(os.Args[0])
+	fmt.Printf("usage: %v t n seedfile [directory]\nwhere 0 < t < n < %v, seedfile contains a 32 byte Ed25519 seed encoded in hex\n(use - to read it from stdin), and the key share files are written to directory (default: current directory)\n", cmd, maxN)
+}
+
+// readSeed reads the hex encoded seed from the file at path, or from stdin if path is "-".
+// The seed is not accepted as an argument, since it would be visible to other users and stored in the shell history.
+// The file must not be accessible to other users.
+func readSeed(path string) ([]byte, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		var info os.FileInfo
+		if info, err = os.Stat(path); err != nil {
+			return nil, err
+		}
+		if info.Mode().Perm()&0077 != 0 {
+			return nil, fmt.Errorf("seed file %v is accessible by other users (mode %v), it should have mode 0600", path, info.Mode().Perm())
+		}
+		data, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return hex.DecodeString(strings.TrimSpace(string(data)))
+}
+
+func main() {
+	if len(os.Args) != 4 && len(os.Args) != 5 {
+		usage()
+		return
+	}
+
+	var err error
+	var t int
+	var n int
+
+	t, err = strconv.Atoi(os.Args[1])
+	if err != nil {
+		fmt.Println(err)
+		usage()
+		return
+	}
+	n, err = strconv.Atoi(os.Args[2])
+	if err != nil {
+		fmt.Println(err)
+		usage()
+		return
+	}
+	if (n > maxN) || (t >= n) {
+		usage()
+		return
+	}
+	seed, err := readSeed(os.Args[3])
+	if err != nil {
+		fmt.Println(err)
+		usage()
+		return
+	}
+
+	partyIDs := helpers.GenerateSet(party.ID(n))
+
+	output, err := dealer.SplitSeed(seed, partyIDs, party.Size(t))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println("Group Key:")
+	fmt.Printf("  %x\n\n", output.Public.GroupKey.ToEd25519())
+
+	dir := "."
+	if len(os.Args) == 5 {
+		dir = os.Args[4]
+	}
+	store, err := keystore.Open(dir, nil)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	for _, id := range partyIDs {
+		keyFile, err := output.KeyFile(id)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if err = keyFile.Verify(); err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		keyShare, err := keystore.NewKeyShare(keyFile.Secret, keyFile.Public)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if err = store.Save(keyShare); err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Printf("Party %d: key share written to %v\n", id, dir)
+	}
+}
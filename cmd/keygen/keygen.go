@@ -1,18 +1,16 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strconv"
 
-	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
 	"github.com/taurusgroup/frost-ed25519/pkg/frost"
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/keygen"
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
 	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/keystore"
 	"github.com/taurusgroup/frost-ed25519/pkg/state"
 )
 
@@ -20,11 +18,11 @@ const maxN = 100
 
 func usage() {
 	cmd := filepath.Base(os.Args[0])
-	fmt.Printf("usage: %v t n\nwhere 0 < t < n < %v\n", cmd, maxN)
+	fmt.Printf("usage: %v t n [directory]\nwhere 0 < t < n < %v, and the key share files are written to directory (default: current directory)\n", cmd, maxN)
 }
 
 func main() {
-	if len(os.Args) != 3 {
+	if len(os.Args) != 3 && len(os.Args) != 4 {
 		usage()
 		return
 	}
@@ -94,6 +92,16 @@ func main() {
 		}
 	}
 
+	dir := "."
+	if len(os.Args) == 4 {
+		dir = os.Args[3]
+	}
+	store, err := keystore.Open(dir, nil)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
 	// Get the public data
 	fmt.Println("Group Key:")
 	id0 := partyIDs[0]
@@ -102,7 +110,6 @@ func main() {
 		return
 	}
 	public := outputs[id0].Public
-	secrets := make(map[party.ID]*eddsa.SecretShare, n)
 	groupKey := public.GroupKey
 	fmt.Printf("  %x\n\n", groupKey.ToEd25519())
 
@@ -113,31 +120,18 @@ func main() {
 		}
 		shareSecret := outputs[id].SecretKey
 		sharePublic := public.Shares[id]
-		secrets[id] = shareSecret
 		fmt.Printf("Party %d:\n  secret: %x\n  public: %x\n", id, shareSecret.Secret.Bytes(), sharePublic.Bytes())
-	}
-
-	// TODO: write JSON file, to take as input by CLI signer
-	type KeyGenOutput struct {
-		Secrets map[party.ID]*eddsa.SecretShare
-		Shares  *eddsa.Public
-	}
 
-	kgOutput := KeyGenOutput{
-		Secrets: secrets,
-		Shares:  public,
-	}
-
-	var jsonData []byte
-	jsonData, err = json.MarshalIndent(kgOutput, "", " ")
-	if err != nil {
-		fmt.Println(err)
-		return
+		keyShare, err := keystore.NewKeyShare(shareSecret, public)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if err = store.Save(keyShare); err != nil {
+			fmt.Println(err)
+			return
+		}
 	}
 
-	filename := "keygenout.json"
-
-	_ = ioutil.WriteFile(filename, jsonData, 0644)
-
-	fmt.Printf("Success: output written to %v\n", filename)
+	fmt.Printf("Success: key shares of key %v written to %v\n", keystore.KeyID(groupKey), dir)
 }
@@ -2,7 +2,6 @@ package main
 
 import (
 	"crypto/ed25519"
-	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -13,58 +12,66 @@ import (
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/sign"
 	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/keystore"
 	"github.com/taurusgroup/frost-ed25519/pkg/state"
 )
 
 func usage() {
 	cmd := filepath.Base(os.Args[0])
-	fmt.Printf("usage: %v <JSON file> message\n", cmd)
+	fmt.Printf("usage: %v message <key share file>...\nwhere the files contain the shares of at least t+1 parties for the same key\n", cmd)
 }
 
 func main() {
 
-	if len(os.Args) != 3 {
+	if len(os.Args) < 3 {
 		usage()
 		return
 	}
 
-	filename := os.Args[1]
-	message := []byte(os.Args[2])
+	message := []byte(os.Args[1])
 
-	var err error
-
-	type KeyGenOutput struct {
-		Secrets map[party.ID]*eddsa.SecretShare
-		Shares  *eddsa.Public
+	var publicShares *eddsa.Public
+	secretShares := map[party.ID]*eddsa.SecretShare{}
+	for _, filename := range os.Args[2:] {
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		keyShare, err := keystore.ParseKeyShare(data, 0)
+		if err != nil {
+			fmt.Printf("%v: %v\n", filename, err)
+			return
+		}
+		if publicShares == nil {
+			publicShares = keyShare.Public
+		} else if !publicShares.Equal(keyShare.Public) {
+			fmt.Printf("%v: share of another key\n", filename)
+			return
+		}
+		secretShares[keyShare.Secret.ID] = keyShare.Secret
 	}
 
-	var kgOutput KeyGenOutput
+	// get n and t from the key shares
+	var n party.Size
+	var t party.Size
 
-	var jsonData []byte
-	jsonData, err = ioutil.ReadFile(filename)
-	if err != nil {
-		fmt.Println(err)
-		return
-	}
+	n = party.Size(len(secretShares))
+	t = publicShares.Threshold
 
-	err = json.Unmarshal(jsonData, &kgOutput)
-	if err != nil {
-		fmt.Println(err)
+	fmt.Printf("(t, n) = (%v, %v)\n", t, publicShares.PartyIDs.N())
+	if n < t+1 {
+		fmt.Printf("%v shares are required to sign, got %v\n", t+1, n)
 		return
 	}
 
-	// get n and t from the keygen output
-	var n party.Size
-	var t party.Size
-
-	n = kgOutput.Shares.PartyIDs.N()
-	t = kgOutput.Shares.Threshold
-
-	fmt.Printf("(t, n) = (%v, %v)\n", t, n)
+	partyIDs := make(party.IDSlice, 0, n)
+	for id := range secretShares {
+		partyIDs = append(partyIDs, id)
+	}
+	partyIDs = party.NewIDSlice(partyIDs)
 
-	partyIDs := helpers.GenerateSet(n)
-	secretShares := kgOutput.Secrets
-	publicShares := kgOutput.Shares
+	var err error
 
 	// structure holding parties' state and output
 	states := map[party.ID]*state.State{}
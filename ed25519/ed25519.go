@@ -10,8 +10,13 @@ import (
 	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
 	"github.com/taurusgroup/frost-ed25519/pkg/frost"
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/sign"
 	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/keystore"
 	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+	"github.com/taurusgroup/frost-ed25519/pkg/seal"
+	"os"
+	"path/filepath"
 	"strings"
 )
 
@@ -112,8 +117,7 @@ func SliceKeygen(t int, n int) string {
 		}
 	}
 
-	// Get the public data
-	fmt.Println("Group Key:")
+	// Get the public data, the group key can be obtained from each slice with GetGroupkeyFromSlice
 	id0 := partyIDs[0]
 	if err = states[id0].WaitForError(); err != nil {
 		fmt.Println(err)
@@ -121,71 +125,31 @@ func SliceKeygen(t int, n int) string {
 	}
 	public := outputs[id0].Public
 	secrets := make(map[party.ID]*eddsa.SecretShare, n)
-	groupKey := public.GroupKey
-	fmt.Printf("  %x\n\n", groupKey.ToEd25519())
 
 	for _, id := range partyIDs {
 		if err := states[id].WaitForError(); err != nil {
 			fmt.Println(err)
 			return ""
 		}
-		shareSecret := outputs[id].SecretKey
-		sharePublic := public.Shares[id]
-		secrets[id] = shareSecret
-		fmt.Printf("Party %d:\n  secret: %x\n  public: %x\n", id, shareSecret.Secret.Bytes(), sharePublic.Bytes())
-	}
-
-	// TODO: write JSON file, to take as input by CLI signer
-	type KeyGenOutput struct {
-		Secrets map[party.ID]*eddsa.SecretShare
-		Shares  *eddsa.Public
+		secrets[id] = outputs[id].SecretKey
 	}
 
+	// 每个分片是一个 keystore 密钥分片文件
 	var slices [][]byte
 	for _, id := range partyIDs {
-
-		// 创建一个新的 map 用于存储过滤后的 secretShare
-		filteredSecrets := make(map[party.ID]*eddsa.SecretShare)
-
-		// 遍历原始的 secrets map
-		for nid, secret := range secrets {
-			// 如果ID不等于'2'，则将其添加到新的 map 中
-			if nid == id {
-				filteredSecrets[id] = secret
-			}
-		}
-
-		filteredShares := make(map[party.ID]*ristretto.Element)
-		filteredShares[id] = public.Shares[id]
-
-		filteredPubs := &eddsa.Public{
-			partyIDs,
-			party.Size(t),
-			filteredShares,
-			public.GroupKey,
-		}
-
-		kgOutput := KeyGenOutput{
-			Secrets: filteredSecrets,
-			Shares:  filteredPubs,
+		keyShare, err := keystore.NewKeyShare(secrets[id], public)
+		if err != nil {
+			fmt.Println(err)
+			return ""
 		}
-		var jsonData []byte
-		jsonData, err = json.MarshalIndent(kgOutput, "", " ")
+		jsonData, err := json.Marshal(keyShare)
 		if err != nil {
 			fmt.Println(err)
 			return ""
 		}
-
 		slices = append(slices, jsonData)
-
-		gk := kgOutput.Shares.GroupKey.ToEd25519()
-		fmt.Printf("groupkey____: %v\n", base64.StdEncoding.EncodeToString(gk))
-
 	}
 
-	fmt.Println("生成分片：-----------------------")
-	fmt.Println(slices)
-
 	var encodedKeys = encode2String(slices)
 	return encodedKeys
 }
@@ -831,7 +795,7 @@ func SliceKeyGenRound2(index int, outStateData []byte, yMessage string) ([]byte,
 	return stateData2, err
 }
 
-// DKGSlice 生成最终密钥分片分片
+// DKGSlice 生成最终密钥分片，返回 base64 编码的 keystore 密钥分片文件，组公钥可通过 GetGroupkeyFromSlice 获取
 func DKGSlice(n int, outStateData []byte) (string, error) {
 
 	var outState helpers.KeyGenOutState
@@ -840,55 +804,29 @@ func DKGSlice(n int, outStateData []byte) (string, error) {
 		return "", err
 	}
 
-	// Get the public data
 	estate := outState.State
 	output := outState.Output
-	partyID := outState.PartyID
 	if err := estate.WaitForError(); err != nil {
 		fmt.Println(err)
 		return "", err
 	}
-	public := outState.Output.Public
-	secrets := make(map[party.ID]*eddsa.SecretShare, n)
-	groupKey := public.GroupKey
-	fmt.Printf("  %x\n\n", groupKey.ToEd25519())
-
-	shareSecret := output.SecretKey
-	sharePublic := public.Shares[partyID]
-	secrets[partyID] = shareSecret
-	fmt.Printf("Party %d:\n  secret: %x\n  public: %x\n", partyID, shareSecret.Secret.Bytes(), sharePublic.Bytes())
-
-	type KeyGenOutput struct {
-		Secrets map[party.ID]*eddsa.SecretShare
-		Shares  *eddsa.Public
-	}
-
-	partyIDs := helpers.GenerateSet(party.ID(n))
-	filteredPubs := &eddsa.Public{
-		partyIDs,
-		party.Size(n - 1),
-		public.Shares,
-		public.GroupKey,
+	public := output.Public
+	if public.PartyIDs.N() != party.Size(n) {
+		return "", fmt.Errorf("keygen output contains %d parties instead of %d", public.PartyIDs.N(), n)
 	}
 
-	kgOutput := KeyGenOutput{
-		Secrets: secrets,
-		Shares:  filteredPubs,
+	keyShare, err := keystore.NewKeyShare(output.SecretKey, public)
+	if err != nil {
+		fmt.Println(err)
+		return "", err
 	}
-	var jsonData []byte
-	jsonData, err = json.MarshalIndent(kgOutput, "", " ")
+	jsonData, err := json.Marshal(keyShare)
 	if err != nil {
 		fmt.Println(err)
 		return "", err
 	}
 
-	gk := kgOutput.Shares.GroupKey.ToEd25519()
-	fmt.Printf("groupkey____: %v\n", base64.StdEncoding.EncodeToString(gk))
-
-	slice := base64.StdEncoding.EncodeToString(jsonData)
-	fmt.Println("生成分片：-----------------------\n------------------------", string(jsonData))
-	return slice, nil
-
+	return base64.StdEncoding.EncodeToString(jsonData), nil
 }
 
 // MPCPartSignRound0 MPC 签名第一阶段 生成 state & output
@@ -898,21 +836,14 @@ func MPCPartSignRound0(n int, index int, key string, message string) ([]byte, er
 
 	partyID := partyIDs[index]
 
-	jsonData, err := base64.StdEncoding.DecodeString(key)
-	if err != nil {
-		fmt.Println(err)
-		return nil, err
-	}
-
-	var kgp helpers.FKeyGenOutput
-	err = json.Unmarshal(jsonData, &kgp)
+	// key 可以是 DKGSlice 生成的分片，也可以是 keystore 格式的密钥分片文件
+	keyShare, err := keystore.ParseKeyShare([]byte(key), partyID)
 	if err != nil {
 		fmt.Println(err)
 		return nil, err
 	}
 
-	secretShares := kgp.Secrets
-	pshares := kgp.Shares
+	pshares := keyShare.Public
 
 	ps := map[party.ID]*ristretto.Element{}
 	for _, pid := range partyIDs {
@@ -924,10 +855,13 @@ func MPCPartSignRound0(n int, index int, key string, message string) ([]byte, er
 		party.Size(1),
 		ps,
 		pshares.GroupKey,
+		pshares.ChainCode,
 	}
 
 	messageB := []byte(message)
-	estate, output, err := frost.NewSignState(partyIDs, secretShares[partyID], &publicShares, messageB, 0)
+	// 创建 state 时设置 NonceStore，序列化后的 state 在 MPCPartSignRound1 中必须再次提供 NonceStore 才能生成签名分片
+	opts := &sign.Options{NonceStore: sign.NewMemoryNonceStore()}
+	estate, output, err := frost.NewSignStateWithOptions(partyIDs, keyShare.Secret, &publicShares, messageB, opts, 0)
 
 	if err != nil {
 		fmt.Println(err)
@@ -965,7 +899,35 @@ func MPCPartSignRound0(n int, index int, key string, message string) ([]byte, er
 	return statedata, err
 }
 
+// DefaultNonceStorePath 是 MPCPartSignRound1 记录已使用 nonce 的文件。
+// 使用同一密钥分片签名的所有进程必须使用同一个文件
+var DefaultNonceStorePath = defaultNonceStorePath()
+
+func defaultNonceStorePath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "frost-ed25519", "nonces")
+}
+
+// MPCPartSignRound1 MPC 签名第二阶段，在 DefaultNonceStorePath 文件中记录已使用的 nonce
 func MPCPartSignRound1(index int, inputStateData []byte, yMessage string) ([]byte, error) {
+	if DefaultNonceStorePath == "" {
+		return nil, fmt.Errorf("DefaultNonceStorePath is not set")
+	}
+	if err := os.MkdirAll(filepath.Dir(DefaultNonceStorePath), 0700); err != nil {
+		return nil, err
+	}
+	return MPCPartSignRound1WithNonceStore(index, inputStateData, yMessage, DefaultNonceStorePath)
+}
+
+// MPCPartSignRound1WithNonceStore 与 MPCPartSignRound1 相同，但在 nonceStorePath 文件中记录已使用的 nonce，
+// 拒绝使用同一个 state 生成第二个签名分片，防止私钥分片泄露
+func MPCPartSignRound1WithNonceStore(index int, inputStateData []byte, yMessage string, nonceStorePath string) ([]byte, error) {
+	if nonceStorePath == "" {
+		return nil, fmt.Errorf("nonceStorePath is empty")
+	}
 
 	var inputState helpers.MPCSignatureOutState
 	err := json.Unmarshal(inputStateData, &inputState)
@@ -974,6 +936,17 @@ func MPCPartSignRound1(index int, inputStateData []byte, yMessage string) ([]byt
 	}
 	estate := inputState.State
 
+	store, err := sign.OpenFileNonceStore(nonceStorePath)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+	round, ok := estate.GetRound().(interface{ SetNonceStore(sign.NonceStore) })
+	if !ok {
+		return nil, fmt.Errorf("state is not a signing state")
+	}
+	round.SetNonceStore(store)
+
 	if len(yMessage) == 0 {
 		return nil, fmt.Errorf("remoteMessage is empty")
 	}
@@ -1052,20 +1025,12 @@ func VerifySignature(groupKey []byte, message string, signature string) bool {
 
 // GetGroupkeyFromSlice 从分片中获取组公钥 GroupKey
 func GetGroupkeyFromSlice(slice string) ([]byte, error) {
-
-	sliceData, err := base64.StdEncoding.DecodeString(slice)
-	if err != nil {
-		fmt.Println(err)
-		return nil, err
-	}
-	var kg helpers.FKeyGenOutput
-	err = json.Unmarshal(sliceData, &kg)
+	keyShare, err := keystore.ParseKeyShare([]byte(slice), 0)
 	if err != nil {
 		fmt.Println(err)
 		return nil, err
 	}
-	return kg.Shares.GroupKey.ToEd25519(), nil
-
+	return keyShare.Public.GroupKey.ToEd25519(), nil
 }
 
 // GetMessageFromKeygenOutData  从 output 中获取 Message 信息
@@ -1102,6 +1067,80 @@ func GetMessageFromSignOutData(kgOutputData []byte, msgIndex int) (string, error
 	}
 }
 
+// KeyShareFromSlice 将 DKGSlice 生成的分片转换为未编码的 keystore 密钥分片文件，t 为密钥生成时的门限。
+// 旧版本 DKGSlice 生成的分片记录的门限总是 n-1，因此使用 t 替换并重新验证公钥分片；
+// 旧版本 SliceKeygen 生成的分片只包含自己的公钥分片，无法转换
+func KeyShareFromSlice(n int, t int, index int, slice string) (string, error) {
+	partyIDs := helpers.GenerateSet(party.Size(n))
+	if index < 0 || index >= len(partyIDs) {
+		return "", fmt.Errorf("index %d is out of range", index)
+	}
+	if t <= 0 || t >= n {
+		return "", fmt.Errorf("0<t<n")
+	}
+	keyShare, err := keystore.ParseKeyShareWithThreshold([]byte(slice), partyIDs[index], party.Size(t))
+	if err != nil {
+		return "", err
+	}
+	if !keyShare.Public.PartyIDs.Equal(partyIDs) {
+		return "", fmt.Errorf("slice contains %d parties instead of %d", keyShare.Public.PartyIDs.N(), n)
+	}
+	data, err := json.Marshal(keyShare)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// SealStateData 使用口令加密 SliceKeyGenRound* 和 MPCPartSignRound* 返回的 state 数据
+// counter 应来自单独持久化保存的计数器，每次加密时递增（不要使用轮次，从旧 state 恢复后轮次会重复），
+// OpenStateData 会拒绝 counter 小于 minCounter 的旧 state，防止 nonce 被重复使用
+func SealStateData(passphrase string, counter int, stateData []byte) ([]byte, error) {
+	if counter < 0 {
+		return nil, fmt.Errorf("counter is negative")
+	}
+	sealer := seal.NewPassphraseSealer([]byte(passphrase))
+	defer sealer.Reset()
+	return sealer.Seal(seal.KindState, uint64(counter), stateData)
+}
+
+// OpenStateData 解密 SealStateData 加密的 state 数据，数据被篡改或 counter 小于 minCounter 时返回错误
+func OpenStateData(passphrase string, minCounter int, sealedData []byte) ([]byte, error) {
+	if minCounter < 0 {
+		return nil, fmt.Errorf("counter is negative")
+	}
+	sealer := seal.NewPassphraseSealer([]byte(passphrase))
+	defer sealer.Reset()
+	stateData, _, err := sealer.Open(seal.KindState, sealedData, uint64(minCounter))
+	return stateData, err
+}
+
+// SealSlice 使用口令加密 DKGSlice 生成的分片，返回 base64 编码的密文
+func SealSlice(passphrase string, slice string) (string, error) {
+	sealer := seal.NewPassphraseSealer([]byte(passphrase))
+	defer sealer.Reset()
+	sealed, err := sealer.Seal(seal.KindSecretShare, 0, []byte(slice))
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// OpenSlice 解密 SealSlice 加密的分片
+func OpenSlice(passphrase string, sealedSlice string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(sealedSlice)
+	if err != nil {
+		return "", err
+	}
+	sealer := seal.NewPassphraseSealer([]byte(passphrase))
+	defer sealer.Reset()
+	slice, _, err := sealer.Open(seal.KindSecretShare, sealed, 0)
+	if err != nil {
+		return "", err
+	}
+	return string(slice), nil
+}
+
 // dpkTest 分布式分片生成
 func dpkTest() {
 	// client round0
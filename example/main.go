@@ -34,6 +34,12 @@ func MessageRoutine(msgsIn, msgsOut chan *messages.Message, s *state.State) {
 				msgsOut <- msgOut
 			}
 
+		case <-s.Proceed():
+			// The timeout of the round expired, but it can be completed without the missing messages.
+			for _, msgOut := range s.ProcessAll() {
+				msgsOut <- msgOut
+			}
+
 		case <-s.Done():
 			// s.Done() closes either when an abort has been called, or when the output has successfully been computed.
 			// If an error did occur, we can handle it here
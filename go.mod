@@ -5,9 +5,10 @@ go 1.14
 require (
 	//filippo.io/edwards25519 v1.0.0-rc.1
 	github.com/WorthyDD/edwards25519 v1.0.4
-	github.com/blocto/solana-go-sdk v1.30.0 // indirect
+	github.com/blocto/solana-go-sdk v1.30.0
 	github.com/davecgh/go-spew v1.1.1
 	github.com/gagliardetto/solana-go v1.10.0
 	github.com/stretchr/testify v1.7.0
+	golang.org/x/crypto v0.23.0
 	golang.org/x/mobile v0.0.0-20240506190922-a1a533f289d3 // indirect
 )
@@ -0,0 +1,71 @@
+package eddsa
+
+import (
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/scalar"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+)
+
+// BatchEntry is a signature to be checked by BatchVerify.
+type BatchEntry struct {
+	PublicKey *PublicKey
+	Message   []byte
+	Signature *Signature
+
+	// Options selects the Ed25519 variant of the signature, and may be nil for PureEdDSA.
+	Options *Options
+}
+
+// BatchVerify returns true if all signatures are valid, as if each was checked with PublicKey.VerifyWithOptions.
+//
+// Instead of checking [s]B = R + [c]A for each signature, a random linear combination of all equations is checked
+// with a single multi-scalar multiplication:
+//
+//	[∑ zᵢ • sᵢ] B = ∑ [zᵢ] Rᵢ + [zᵢ • cᵢ] Aᵢ
+//
+// Entries which share the same *PublicKey are combined, so that their key is only encoded and multiplied once.
+// If it returns false, at least one signature is invalid, and the culprits can be found by verifying them individually.
+func BatchVerify(entries []BatchEntry) bool {
+	n := len(entries)
+	if n == 1 {
+		entry := &entries[0]
+		return entry.PublicKey.VerifyWithOptions(entry.Message, entry.Signature, entry.Options)
+	}
+
+	scalars := make([]*ristretto.Scalar, 0, 2*n)
+	points := make([]*ristretto.Element, 0, 2*n)
+	S := ristretto.NewScalar()
+
+	// keys maps each public key to the index of its coefficient ∑ zᵢ • cᵢ in scalars, and to its encoding.
+	type keyIndex struct {
+		index   int
+		encoded []byte
+	}
+	keys := make(map[*PublicKey]keyIndex)
+
+	for i := range entries {
+		entry := &entries[i]
+		if entry.Options.Validate(entry.Message) != nil {
+			return false
+		}
+		key, ok := keys[entry.PublicKey]
+		if !ok {
+			key = keyIndex{index: len(scalars), encoded: entry.PublicKey.ToEd25519()}
+			keys[entry.PublicKey] = key
+			scalars = append(scalars, ristretto.NewScalar())
+			points = append(points, &entry.PublicKey.pk)
+		}
+		challenge := computeChallenge(&entry.Signature.R, key.encoded, entry.Message, entry.Options)
+
+		z := scalar.NewScalarRandom()
+		S.MultiplyAdd(z, &entry.Signature.S, S)
+		scalars[key.index].MultiplyAdd(z, challenge, scalars[key.index])
+
+		scalars = append(scalars, z)
+		points = append(points, &entry.Signature.R)
+	}
+
+	var lhs, rhs ristretto.Element
+	lhs.ScalarBaseMult(S)
+	rhs.VarTimeMultiScalarMult(scalars, points)
+	return lhs.Equal(&rhs) == 1
+}
@@ -0,0 +1,85 @@
+package eddsa
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func generateBatch(t testing.TB, n int) []BatchEntry {
+	entries := make([]BatchEntry, n)
+	for i := range entries {
+		_, skBytes, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err, "failed to generate key")
+		sk, pk := newKeyPair(skBytes)
+		message := []byte{byte(i), byte(i >> 8)}
+		var opts *Options
+		if i%2 == 1 {
+			opts = &Options{Variant: VariantCtx, Context: []byte("FROST batch")}
+		}
+		entries[i] = BatchEntry{
+			PublicKey: pk,
+			Message:   message,
+			Signature: NewSecretShare(0, sk).signWithOptions(message, opts),
+			Options:   opts,
+		}
+	}
+	return entries
+}
+
+func TestBatchVerify(t *testing.T) {
+	entries := generateBatch(t, 10)
+	assert.True(t, BatchVerify(entries))
+	assert.True(t, BatchVerify(entries[:1]))
+	assert.True(t, BatchVerify(nil))
+
+	// Signatures of different messages are swapped
+	swapped := append([]BatchEntry{}, entries...)
+	swapped[2].Signature, swapped[4].Signature = swapped[4].Signature, swapped[2].Signature
+	assert.False(t, BatchVerify(swapped))
+
+	// A single invalid signature is detected
+	for i := range entries {
+		invalid := append([]BatchEntry{}, entries...)
+		invalid[i].Message = []byte("other message")
+		assert.False(t, BatchVerify(invalid), "invalid signature %d was not detected", i)
+		assert.False(t, BatchVerify(invalid[i:i+1]))
+	}
+
+	// Signatures with the same key are combined
+	_, skBytes, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err, "failed to generate key")
+	sk, pk := newKeyPair(skBytes)
+	shared := append([]BatchEntry{}, entries...)
+	for i := range shared {
+		shared[i].PublicKey = pk
+		shared[i].Signature = NewSecretShare(0, sk).signWithOptions(shared[i].Message, shared[i].Options)
+	}
+	assert.True(t, BatchVerify(shared))
+	shared[3].Message = []byte("other message")
+	assert.False(t, BatchVerify(shared))
+
+	// The options must be valid
+	invalid := append([]BatchEntry{}, entries...)
+	invalid[0].Options = &Options{Variant: VariantPh}
+	assert.False(t, BatchVerify(invalid))
+}
+
+func BenchmarkBatchVerify(b *testing.B) {
+	entries := generateBatch(b, 64)
+	b.Run("batch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			BatchVerify(entries)
+		}
+	})
+	b.Run("single", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j := range entries {
+				entries[j].PublicKey.VerifyWithOptions(entries[j].Message, entries[j].Signature, entries[j].Options)
+			}
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package eddsa
+
+import (
+	"crypto/sha512"
+	"errors"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+)
+
+// Ciphersuite is one of the FROST ciphersuites of RFC 9591 which can be computed with ristretto.Element.
+//
+// Both ciphersuites use the same group and scalars, so that the same keys can be used with either of them.
+// They differ in the encoding of the group elements, and in the hash functions H1 to H5.
+type Ciphersuite uint8
+
+const (
+	// CiphersuiteEd25519 is FROST(Ed25519, SHA-512).
+	// Elements are encoded as Ed25519 points, and the signatures can be verified by ed25519.Verify.
+	CiphersuiteEd25519 Ciphersuite = iota
+
+	// CiphersuiteRistretto255 is FROST(ristretto255, SHA-512).
+	// Elements are encoded as ristretto255 elements, and a signature R ∥ z is a ristretto255 Schnorr signature,
+	// which is returned by Signature.MarshalBinary.
+	CiphersuiteRistretto255
+)
+
+// Validate returns an error if c is not a known ciphersuite.
+func (c Ciphersuite) Validate() error {
+	if c > CiphersuiteRistretto255 {
+		return errors.New("eddsa.Ciphersuite: unknown ciphersuite")
+	}
+	return nil
+}
+
+// ContextString returns the contextString of the ciphersuite, which is prepended to the inputs of the hash functions.
+func (c Ciphersuite) ContextString() string {
+	if c == CiphersuiteRistretto255 {
+		return "FROST-RISTRETTO255-SHA512-v1"
+	}
+	return "FROST-ED25519-SHA512-v1"
+}
+
+// EncodeElement returns the encoding of e, SerializeElement(e) in RFC 9591.
+func (c Ciphersuite) EncodeElement(e *ristretto.Element) []byte {
+	if c == CiphersuiteRistretto255 {
+		return e.Bytes()
+	}
+	return e.BytesEd25519()
+}
+
+// EncodePublicKey returns the encoding of pk.
+func (c Ciphersuite) EncodePublicKey(pk *PublicKey) []byte {
+	return c.EncodeElement(&pk.pk)
+}
+
+// EncodeSignature returns the encoding R ∥ z of sig.
+func (c Ciphersuite) EncodeSignature(sig *Signature) []byte {
+	if c == CiphersuiteRistretto255 {
+		out, _ := sig.MarshalBinary()
+		return out
+	}
+	return sig.ToEd25519()
+}
+
+// hash returns SHA-512(contextString ∥ tag ∥ data...).
+func (c Ciphersuite) hash(tag string, data ...[]byte) []byte {
+	h := sha512.New()
+	_, _ = h.Write([]byte(c.ContextString()))
+	_, _ = h.Write([]byte(tag))
+	for _, d := range data {
+		_, _ = h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+// hashToScalar returns hash(tag, data...) interpreted as a scalar.
+func (c Ciphersuite) hashToScalar(tag string, data ...[]byte) *ristretto.Scalar {
+	var s ristretto.Scalar
+	if _, err := s.SetUniformBytes(c.hash(tag, data...)); err != nil {
+		panic(err)
+	}
+	return &s
+}
+
+// H1 is used to compute the binding factors.
+func (c Ciphersuite) H1(data ...[]byte) *ristretto.Scalar {
+	return c.hashToScalar("rho", data...)
+}
+
+// H2 is used to compute the challenge.
+// For Ed25519, it is SHA-512 without context string, as in RFC 8032.
+func (c Ciphersuite) H2(data ...[]byte) *ristretto.Scalar {
+	if c == CiphersuiteRistretto255 {
+		return c.hashToScalar("chal", data...)
+	}
+	h := sha512.New()
+	for _, d := range data {
+		_, _ = h.Write(d)
+	}
+	var s ristretto.Scalar
+	if _, err := s.SetUniformBytes(h.Sum(nil)); err != nil {
+		panic(err)
+	}
+	return &s
+}
+
+// H3 is used to generate the nonces.
+func (c Ciphersuite) H3(data ...[]byte) *ristretto.Scalar {
+	return c.hashToScalar("nonce", data...)
+}
+
+// H4 is used to hash the message.
+func (c Ciphersuite) H4(data ...[]byte) []byte {
+	return c.hash("msg", data...)
+}
+
+// H5 is used to hash the commitment list.
+func (c Ciphersuite) H5(data ...[]byte) []byte {
+	return c.hash("com", data...)
+}
+
+// Challenge computes the value H2(R ∥ A ∥ M).
+// For Ed25519, it is the same as ComputeChallenge.
+func (c Ciphersuite) Challenge(R *ristretto.Element, groupKey *PublicKey, message []byte) *ristretto.Scalar {
+	return c.H2(c.EncodeElement(R), c.EncodePublicKey(groupKey), message)
+}
+
+// Verify checks a signature of message produced with the ciphersuite.
+// For Ed25519, it is the same as PublicKey.Verify.
+func (c Ciphersuite) Verify(pk *PublicKey, message []byte, sig *Signature) bool {
+	if c == CiphersuiteEd25519 {
+		return pk.Verify(message, sig)
+	}
+	if c.Validate() != nil {
+		return false
+	}
+	challenge := c.Challenge(&sig.R, pk, message)
+
+	var publicNeg, RPrime ristretto.Element
+	publicNeg.Negate(&pk.pk)
+	// RPrime = [c](-A) + [s]B
+	RPrime.VarTimeDoubleScalarBaseMult(challenge, &publicNeg, &sig.S)
+	return RPrime.Equal(&sig.R) == 1
+}
@@ -0,0 +1,56 @@
+package eddsa
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/scalar"
+)
+
+func (sk *SecretShare) signWithCiphersuite(message []byte, c Ciphersuite) *Signature {
+	var sig Signature
+
+	// R = [r] • B
+	r := scalar.NewScalarRandom()
+	sig.R.ScalarBaseMult(r)
+
+	pk := PublicKey{pk: sk.Public}
+
+	// C = H2(R, A, M)
+	challenge := c.Challenge(&sig.R, &pk, message)
+
+	// S = Secret * c + r
+	sig.S.MultiplyAdd(&sk.Secret, challenge, r)
+	return &sig
+}
+
+func TestCiphersuite(t *testing.T) {
+	_, skBytes, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err, "failed to generate key")
+	sk, pk := newKeyPair(skBytes)
+	skShare := NewSecretShare(0, sk)
+	message := []byte(sampleMessage)
+
+	// FROST(Ed25519, SHA-512) produces regular Ed25519 signatures
+	sig := skShare.signWithCiphersuite(message, CiphersuiteEd25519)
+	assert.Equal(t, ComputeChallenge(&sig.R, pk, message), CiphersuiteEd25519.Challenge(&sig.R, pk, message))
+	assert.True(t, CiphersuiteEd25519.Verify(pk, message, sig))
+	assert.True(t, ed25519.Verify(pk.ToEd25519(), message, CiphersuiteEd25519.EncodeSignature(sig)))
+	assert.False(t, CiphersuiteRistretto255.Verify(pk, message, sig))
+
+	// FROST(ristretto255, SHA-512) uses the ristretto255 encoding, and a different challenge
+	sig = skShare.signWithCiphersuite(message, CiphersuiteRistretto255)
+	assert.True(t, CiphersuiteRistretto255.Verify(pk, message, sig))
+	assert.False(t, CiphersuiteEd25519.Verify(pk, message, sig))
+	encoded, err := sig.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, encoded, CiphersuiteRistretto255.EncodeSignature(sig))
+	assert.Equal(t, pk.pk.Bytes(), CiphersuiteRistretto255.EncodePublicKey(pk))
+
+	assert.NoError(t, CiphersuiteRistretto255.Validate())
+	assert.Error(t, Ciphersuite(42).Validate())
+	assert.False(t, Ciphersuite(42).Verify(pk, message, sig))
+}
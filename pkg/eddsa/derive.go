@@ -0,0 +1,160 @@
+package eddsa
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+)
+
+// ChainCodeSize is the size of the chain code used for key derivation.
+const ChainCodeSize = 32
+
+// HardenedIndex is the first hardened child index.
+// Hardened derivation requires the full secret key, and is therefore not supported for threshold keys.
+const HardenedIndex uint32 = 1 << 31
+
+const (
+	// prefixTweak and prefixChainCode are prepended to the HMAC input, as in BIP32-Ed25519.
+	prefixTweak     = 0x02
+	prefixChainCode = 0x03
+)
+
+// DerivationPath is a list of non-hardened child indices.
+type DerivationPath []uint32
+
+// ParseDerivationPath parses a path of the form "m/0/1/2".
+// The leading "m" is optional, and hardened indices are rejected.
+func ParseDerivationPath(path string) (DerivationPath, error) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "m"), "/")
+	if path == "" {
+		return DerivationPath{}, nil
+	}
+	components := strings.Split(path, "/")
+	out := make(DerivationPath, 0, len(components))
+	for _, c := range components {
+		if strings.HasSuffix(c, "'") || strings.HasSuffix(c, "h") {
+			return nil, fmt.Errorf("eddsa.ParseDerivationPath: hardened index %s is not supported", c)
+		}
+		index, err := strconv.ParseUint(c, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("eddsa.ParseDerivationPath: %w", err)
+		}
+		out = append(out, uint32(index))
+	}
+	return out, out.Validate()
+}
+
+// Validate returns an error if the path contains a hardened index.
+func (p DerivationPath) Validate() error {
+	for _, index := range p {
+		if index >= HardenedIndex {
+			return fmt.Errorf("eddsa.DerivationPath: hardened index %d is not supported", index)
+		}
+	}
+	return nil
+}
+
+// String returns the path in the form "m/0/1/2".
+func (p DerivationPath) String() string {
+	var sb strings.Builder
+	sb.WriteString("m")
+	for _, index := range p {
+		sb.WriteString("/")
+		sb.WriteString(strconv.FormatUint(uint64(index), 10))
+	}
+	return sb.String()
+}
+
+// deriveChild computes the tweak t and chain code of the child key at index, given the parent key A.
+//
+//	t = HMAC-SHA512(chainCode, 0x02 ∥ A ∥ index) mod q
+//	chainCode' = HMAC-SHA512(chainCode, 0x03 ∥ A ∥ index)[:32]
+//
+// The child key is then A' = A + [t]•G.
+func deriveChild(groupKey *PublicKey, chainCode []byte, index uint32) (*ristretto.Scalar, []byte) {
+	data := make([]byte, 0, 1+32+4)
+	data = append(data, prefixTweak)
+	data = append(data, groupKey.ToEd25519()...)
+	data = append(data, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(data[1+32:], index)
+
+	mac := hmac.New(sha512.New, chainCode)
+	_, _ = mac.Write(data)
+	var tweak ristretto.Scalar
+	if _, err := tweak.SetUniformBytes(mac.Sum(nil)); err != nil {
+		panic(err)
+	}
+
+	data[0] = prefixChainCode
+	mac = hmac.New(sha512.New, chainCode)
+	_, _ = mac.Write(data)
+	childChainCode := mac.Sum(nil)[:ChainCodeSize]
+
+	return &tweak, childChainCode
+}
+
+// Derive returns the public information of the child key at path, along with the tweak t
+// which must be added to each SecretShare, so that the child group key is A' = A + [t]•G.
+//
+// Since the polynomial is shifted by a constant, every public share is also shifted by [t]•G.
+func (s *Public) Derive(path DerivationPath) (*Public, *ristretto.Scalar, error) {
+	if len(s.ChainCode) != ChainCodeSize {
+		return nil, nil, errors.New("eddsa.Public: key does not have a chain code")
+	}
+	if err := path.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	groupKey := NewPublicKeyFromPoint(&s.GroupKey.pk)
+	chainCode := append([]byte{}, s.ChainCode...)
+	totalTweak := ristretto.NewScalar()
+	for _, index := range path {
+		tweak, childChainCode := deriveChild(groupKey, chainCode, index)
+		totalTweak.Add(totalTweak, tweak)
+
+		var tweakPoint ristretto.Element
+		tweakPoint.ScalarBaseMult(tweak)
+		groupKey.pk.Add(&groupKey.pk, &tweakPoint)
+		chainCode = childChainCode
+	}
+
+	var totalTweakPoint ristretto.Element
+	totalTweakPoint.ScalarBaseMult(totalTweak)
+	shares := make(map[party.ID]*ristretto.Element, len(s.Shares))
+	for id, share := range s.Shares {
+		var childShare ristretto.Element
+		shares[id] = childShare.Add(share, &totalTweakPoint)
+	}
+
+	return &Public{
+		PartyIDs:  s.PartyIDs.Copy(),
+		Threshold: s.Threshold,
+		Shares:    shares,
+		GroupKey:  groupKey,
+		ChainCode: chainCode,
+	}, totalTweak, nil
+}
+
+// Derive returns the SecretShare of the child key at path, given the public information of the parent key.
+func (sk *SecretShare) Derive(public *Public, path DerivationPath) (*SecretShare, *Public, error) {
+	childPublic, tweak, err := public.Derive(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var secret ristretto.Scalar
+	secret.Add(&sk.Secret, tweak)
+	child := NewSecretShare(sk.ID, &secret)
+	secret.Set(ristretto.NewScalar())
+
+	if expected, ok := childPublic.Shares[sk.ID]; !ok || expected.Equal(&child.Public) != 1 {
+		return nil, nil, errors.New("eddsa.SecretShare: derived share does not match its public share")
+	}
+	return child, childPublic, nil
+}
@@ -0,0 +1,82 @@
+package eddsa
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/polynomial"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/scalar"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+)
+
+func TestPublic_Derive(t *testing.T) {
+	partyIDs := party.IDSlice{1, 2, 3}
+	secret := scalar.NewScalarRandom()
+	poly := polynomial.NewPolynomial(1, secret)
+
+	secrets := map[party.ID]*SecretShare{}
+	shares := map[party.ID]*ristretto.Element{}
+	for _, id := range partyIDs {
+		secrets[id] = NewSecretShare(id, poly.Evaluate(id.Scalar()))
+		shares[id] = &secrets[id].Public
+	}
+	public, err := NewPublic(shares, 1)
+	require.NoError(t, err)
+
+	_, _, err = public.Derive(DerivationPath{0})
+	assert.Error(t, err, "derivation requires a chain code")
+
+	public.ChainCode = make([]byte, ChainCodeSize)
+	_, _ = rand.Read(public.ChainCode)
+
+	path := DerivationPath{7, 42}
+	child, tweak, err := public.Derive(path)
+	require.NoError(t, err)
+	assert.False(t, child.GroupKey.Equal(public.GroupKey))
+
+	// A' = [s + t]•G
+	var expected ristretto.Scalar
+	expected.Add(secret, tweak)
+	assert.True(t, NewPublicKeyFromPoint(new(ristretto.Element).ScalarBaseMult(&expected)).Equal(child.GroupKey))
+
+	// The public shares interpolate to the child group key
+	assert.True(t, computeGroupKey(child.PartyIDs, child.Shares).Equal(child.GroupKey))
+
+	// Derivation is composable
+	intermediate, _, err := public.Derive(path[:1])
+	require.NoError(t, err)
+	child2, _, err := intermediate.Derive(path[1:])
+	require.NoError(t, err)
+	assert.True(t, child.Equal(child2))
+
+	for _, id := range partyIDs {
+		childSecret, childPublic, err := secrets[id].Derive(public, path)
+		require.NoError(t, err)
+		assert.True(t, childPublic.Equal(child))
+		assert.Equal(t, 1, childSecret.Public.Equal(child.Shares[id]))
+	}
+
+	_, _, err = public.Derive(DerivationPath{HardenedIndex})
+	assert.Error(t, err, "hardened derivation is not supported")
+}
+
+func TestParseDerivationPath(t *testing.T) {
+	path, err := ParseDerivationPath("m/0/1/2")
+	require.NoError(t, err)
+	assert.Equal(t, DerivationPath{0, 1, 2}, path)
+	assert.Equal(t, "m/0/1/2", path.String())
+
+	path, err = ParseDerivationPath("m")
+	require.NoError(t, err)
+	assert.Len(t, path, 0)
+
+	_, err = ParseDerivationPath("m/0'/1")
+	assert.Error(t, err)
+	_, err = ParseDerivationPath("m/2147483648")
+	assert.Error(t, err)
+	_, err = ParseDerivationPath("m/a")
+	assert.Error(t, err)
+}
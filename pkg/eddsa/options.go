@@ -0,0 +1,107 @@
+package eddsa
+
+import (
+	"crypto/sha512"
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+)
+
+// Variant is one of the Ed25519 signature schemes defined in RFC 8032.
+type Variant uint8
+
+const (
+	// VariantPure is PureEdDSA (Ed25519), where the message is signed directly.
+	VariantPure Variant = iota
+
+	// VariantCtx is ContextEdDSA (Ed25519ctx), where the message is signed along with a non-empty context string.
+	VariantCtx
+
+	// VariantPh is HashEdDSA (Ed25519ph), where the SHA-512 digest of the message is signed, along with an optional context.
+	// As with crypto/ed25519, the message given to the signing and verification functions must be the 64 byte digest.
+	VariantPh
+)
+
+// MaxContextSize is the maximum length of the context string of Ed25519ctx and Ed25519ph.
+const MaxContextSize = 255
+
+// dom2Prefix is the prefix of dom2(F, C) in RFC 8032.
+const dom2Prefix = "SigEd25519 no Ed25519 collisions"
+
+// Options selects the Ed25519 variant used for signing and verification.
+// A nil *Options is equivalent to PureEdDSA.
+type Options struct {
+	Variant Variant
+
+	// Context is the context string, of at most MaxContextSize bytes.
+	// It must be non-empty for Ed25519ctx, and empty for Ed25519.
+	Context []byte
+}
+
+// Validate returns an error if the options are not consistent,
+// or if the message does not have the right format for the variant.
+func (o *Options) Validate(message []byte) error {
+	if o == nil {
+		return nil
+	}
+	if len(o.Context) > MaxContextSize {
+		return fmt.Errorf("eddsa.Options: context should be at most %d bytes (got %d)", MaxContextSize, len(o.Context))
+	}
+	switch o.Variant {
+	case VariantPure:
+		if len(o.Context) != 0 {
+			return errors.New("eddsa.Options: Ed25519 does not support a context")
+		}
+	case VariantCtx:
+		if len(o.Context) == 0 {
+			return errors.New("eddsa.Options: Ed25519ctx requires a non-empty context")
+		}
+	case VariantPh:
+		if len(message) != sha512.Size {
+			return fmt.Errorf("eddsa.Options: Ed25519ph message should be a %d byte SHA-512 digest (got %d)", sha512.Size, len(message))
+		}
+	default:
+		return errors.New("eddsa.Options: unknown variant")
+	}
+	return nil
+}
+
+// Prefix returns the domain separation prefix dom2(F, C), which is prepended to all hashes of the message.
+// It is empty for PureEdDSA, so that the pure variant is unaffected.
+func (o *Options) Prefix() []byte {
+	if o == nil || o.Variant == VariantPure {
+		return nil
+	}
+	var phFlag byte
+	if o.Variant == VariantPh {
+		phFlag = 1
+	}
+	out := make([]byte, 0, len(dom2Prefix)+2+len(o.Context))
+	out = append(out, dom2Prefix...)
+	out = append(out, phFlag, byte(len(o.Context)))
+	out = append(out, o.Context...)
+	return out
+}
+
+// ComputeChallengeWithOptions computes the value H(dom2(F, C), R, A, M) for the variant defined by opts.
+func ComputeChallengeWithOptions(R *ristretto.Element, groupKey *PublicKey, message []byte, opts *Options) *ristretto.Scalar {
+	return computeChallenge(R, groupKey.ToEd25519(), message, opts)
+}
+
+// computeChallenge is ComputeChallengeWithOptions with the group key already encoded, which is costly.
+func computeChallenge(R *ristretto.Element, groupKey []byte, message []byte, opts *Options) *ristretto.Scalar {
+	var s ristretto.Scalar
+	dom := opts.Prefix()
+	data := make([]byte, 0, len(dom)+64+len(message))
+	data = append(data, dom...)
+	data = append(data, R.BytesEd25519()...)
+	data = append(data, groupKey...)
+	data = append(data, message...)
+	digest := sha512.Sum512(data)
+	_, err := s.SetUniformBytes(digest[:])
+	if err != nil {
+		panic(err)
+	}
+	return &s
+}
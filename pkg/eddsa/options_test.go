@@ -0,0 +1,147 @@
+package eddsa
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/scalar"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+)
+
+func (sk *SecretShare) signWithOptions(message []byte, opts *Options) *Signature {
+	var sig Signature
+
+	// R = [r] • B
+	r := scalar.NewScalarRandom()
+	sig.R.ScalarBaseMult(r)
+
+	pk := PublicKey{pk: sk.Public}
+
+	// C = H(dom2(F, C), R, A, M)
+	c := ComputeChallengeWithOptions(&sig.R, &pk, message, opts)
+
+	// S = Secret * c + r
+	sig.S.MultiplyAdd(&sk.Secret, c, r)
+	return &sig
+}
+
+// signRFC8032 returns the deterministic signature of message defined in RFC 8032, Section 5.1.6,
+// for the secret key derived from seed.
+func signRFC8032(seed []byte, message []byte, opts *Options) []byte {
+	sk, pk := newKeyPair(ed25519.PrivateKey(seed))
+	digest := sha512.Sum512(seed)
+
+	// dom2(F, C) = "SigEd25519 no Ed25519 collisions" || F || len(C) || C
+	var dom2 []byte
+	if opts != nil && opts.Variant != VariantPure {
+		flag := byte(0)
+		if opts.Variant == VariantPh {
+			flag = 1
+		}
+		dom2 = append([]byte("SigEd25519 no Ed25519 collisions"), flag, byte(len(opts.Context)))
+		dom2 = append(dom2, opts.Context...)
+	}
+
+	// r = SHA-512(dom2(F, C) || prefix || PH(M))
+	h := sha512.New()
+	h.Write(dom2)
+	h.Write(digest[32:])
+	h.Write(message)
+	var r ristretto.Scalar
+	_, _ = r.SetUniformBytes(h.Sum(nil))
+
+	var sig Signature
+	sig.R.ScalarBaseMult(&r)
+	c := ComputeChallengeWithOptions(&sig.R, pk, message, opts)
+	sig.S.MultiplyAdd(sk, c, &r)
+	return sig.ToEd25519()
+}
+
+func TestOptions_RFC8032(t *testing.T) {
+	// Test vectors from RFC 8032, Sections 7.2 and 7.3
+	abc := sha512.Sum512([]byte("abc"))
+	tests := []struct {
+		name      string
+		seed      string
+		publicKey string
+		message   []byte
+		opts      *Options
+		signature string
+	}{
+		{
+			"ctx foo",
+			"0305334e381af78f141cb666f6199f57bc3495335a256a95bd2a55bf546663f6",
+			"dfc9425e4f968f7f0c29f0259cf5f9aed6851c2bb4ad8bfb860cfee0ab248292",
+			[]byte{0xf7, 0x26, 0x93, 0x6d, 0x19, 0xc8, 0x00, 0x49, 0x4e, 0x3f, 0xda, 0xff, 0x20, 0xb2, 0x76, 0xa8},
+			&Options{Variant: VariantCtx, Context: []byte("foo")},
+			"55a4cc2f70a54e04288c5f4cd1e45a7bb520b36292911876cada7323198dd87a8b36950b95130022907a7fb7c4e9b2d5f6cca685a587b4b21f4b888e4e7edb0d",
+		},
+		{
+			"ph abc",
+			"833fe62409237b9d62ec77587520911e9a759cec1d19755b7da901b96dca3d42",
+			"ec172b93ad5e563bf4932c70e1245034c35467ef2efd4d64ebf819683467e2bf",
+			abc[:],
+			&Options{Variant: VariantPh},
+			"98a70222f0b8121aa9d30f813d683f809e462b469c7ff87639499bb94e6dae4131f85042463c2a355a2003d062adf5aaa10b8c61e636062aaad11c2a26083406",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seed, _ := hex.DecodeString(tt.seed)
+			_, pk := newKeyPair(ed25519.PrivateKey(seed))
+			assert.Equal(t, tt.publicKey, hex.EncodeToString(pk.ToEd25519()))
+			assert.Equal(t, tt.signature, hex.EncodeToString(signRFC8032(seed, tt.message, tt.opts)))
+		})
+	}
+}
+
+func TestOptions_Variants(t *testing.T) {
+	_, skBytes, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err, "failed to generate key")
+	sk, pk := newKeyPair(skBytes)
+	skShare := NewSecretShare(0, sk)
+
+	message := []byte(sampleMessage)
+	digest := sha512.Sum512(message)
+	context := "FROST context"
+
+	tests := []struct {
+		name    string
+		opts    *Options
+		message []byte
+	}{
+		{"pure", nil, message},
+		{"ctx", &Options{Variant: VariantCtx, Context: []byte(context)}, message},
+		{"ph", &Options{Variant: VariantPh}, digest[:]},
+		{"ph with context", &Options{Variant: VariantPh, Context: []byte(context)}, digest[:]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, tt.opts.Validate(tt.message))
+
+			sig := skShare.signWithOptions(tt.message, tt.opts)
+			assert.True(t, pk.VerifyWithOptions(tt.message, sig, tt.opts))
+		})
+	}
+
+	// Signatures are not valid across variants
+	sig := skShare.signWithOptions(message, &Options{Variant: VariantCtx, Context: []byte(context)})
+	assert.False(t, pk.Verify(message, sig))
+	assert.False(t, pk.VerifyWithOptions(message, sig, &Options{Variant: VariantCtx, Context: []byte("other")}))
+	assert.False(t, pk.VerifyWithOptions(message, sig, &Options{Variant: VariantPh, Context: []byte(context)}))
+}
+
+func TestOptions_Validate(t *testing.T) {
+	digest := sha512.Sum512([]byte(sampleMessage))
+	assert.Error(t, (&Options{Variant: VariantPure, Context: []byte("ctx")}).Validate(nil))
+	assert.Error(t, (&Options{Variant: VariantCtx}).Validate(nil))
+	assert.Error(t, (&Options{Variant: VariantCtx, Context: make([]byte, MaxContextSize+1)}).Validate(nil))
+	assert.Error(t, (&Options{Variant: VariantPh}).Validate([]byte(sampleMessage)))
+	assert.NoError(t, (&Options{Variant: VariantPh}).Validate(digest[:]))
+	assert.Error(t, (&Options{Variant: 42}).Validate(nil))
+}
@@ -1,6 +1,7 @@
 package eddsa
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 
@@ -23,6 +24,10 @@ type Public struct {
 	// GroupKey is the group's public key
 	// It is the result of interpolating the Shamir shares at 0
 	GroupKey *PublicKey
+
+	// ChainCode is used to derive child keys with Derive.
+	// It is nil if the key does not support derivation.
+	ChainCode []byte
 }
 
 // NewPublic creates a Public structure given a map of public key shares as ristretto.Element, the threshold used.
@@ -66,6 +71,7 @@ type sharesJSON struct {
 	Threshold int                             `json:"t"`
 	GroupKey  *PublicKey                      `json:"groupkey"`
 	Shares    map[party.ID]*ristretto.Element `json:"shares"`
+	ChainCode []byte                          `json:"chaincode,omitempty"`
 }
 
 // MarshalJSON implements the json.Marshaler interface.
@@ -74,6 +80,7 @@ func (s *Public) MarshalJSON() ([]byte, error) {
 		Threshold: int(s.Threshold),
 		Shares:    s.Shares,
 		GroupKey:  s.GroupKey,
+		ChainCode: s.ChainCode,
 	})
 }
 
@@ -94,6 +101,11 @@ func (s *Public) UnmarshalJSON(data []byte) error {
 		return errors.New("PublicShares: inconsistent group key")
 	}
 
+	if out.ChainCode != nil && len(out.ChainCode) != ChainCodeSize {
+		return errors.New("PublicShares: invalid chain code")
+	}
+	newS.ChainCode = out.ChainCode
+
 	*s = *newS
 
 	return nil
@@ -116,6 +128,10 @@ func (s *Public) Equal(s2 *Public) bool {
 		return false
 	}
 
+	if !bytes.Equal(s.ChainCode, s2.ChainCode) {
+		return false
+	}
+
 	for _, id := range s.PartyIDs {
 		p1 := s.Shares[id]
 		p2 := s2.Shares[id]
@@ -19,8 +19,18 @@ func NewPublicKeyFromPoint(public *ristretto.Element) *PublicKey {
 	return &pk
 }
 
+// Verify checks a PureEdDSA signature of message.
 func (pk *PublicKey) Verify(message []byte, sig *Signature) bool {
-	challenge := ComputeChallenge(&sig.R, pk, message)
+	return pk.VerifyWithOptions(message, sig, nil)
+}
+
+// VerifyWithOptions checks a signature of message for the Ed25519 variant defined by opts.
+// For Ed25519ph, message must be the SHA-512 digest of the signed data.
+func (pk *PublicKey) VerifyWithOptions(message []byte, sig *Signature, opts *Options) bool {
+	if opts.Validate(message) != nil {
+		return false
+	}
+	challenge := ComputeChallengeWithOptions(&sig.R, pk, message, opts)
 
 	// Verify the full signature here too.
 	var publicNeg, RPrime ristretto.Element
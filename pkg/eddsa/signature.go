@@ -1,7 +1,6 @@
 package eddsa
 
 import (
-	"crypto/sha512"
 	"errors"
 	"fmt"
 
@@ -30,17 +29,7 @@ func (sig *Signature) ToEd25519() []byte {
 
 // ComputeChallenge computes the value H(R, A, M), and assumes nothing about whether M is hashed.
 func ComputeChallenge(R *ristretto.Element, groupKey *PublicKey, message []byte) *ristretto.Scalar {
-	var s ristretto.Scalar
-	data := make([]byte, 0, 64+len(message))
-	data = append(data, R.BytesEd25519()...)
-	data = append(data, groupKey.ToEd25519()...)
-	data = append(data, message...)
-	digest := sha512.Sum512(data)
-	_, err := s.SetUniformBytes(digest[:])
-	if err != nil {
-		panic(err)
-	}
-	return &s
+	return ComputeChallengeWithOptions(R, groupKey, message, nil)
 }
 
 //
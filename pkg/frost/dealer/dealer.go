@@ -0,0 +1,108 @@
+// Package dealer implements trusted dealer key generation.
+//
+// Instead of running the distributed key generation protocol, a single trusted party splits a known secret key
+// into Shamir shares, and distributes them to the parties. This allows existing Ed25519 keys to be migrated to
+// threshold custody. The dealer learns the full secret, and should delete it once the shares have been distributed.
+package dealer
+
+import (
+	"crypto/ed25519"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/polynomial"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+)
+
+// Output contains the result of splitting a secret key.
+type Output struct {
+	// Public contains the public shares of all parties, and the group key.
+	Public *eddsa.Public
+
+	// Secrets contains the secret shares of all parties.
+	// Each share must be sent to its owner over a secure channel.
+	Secrets map[party.ID]*eddsa.SecretShare
+
+	// Commitments to the coefficients of the polynomial used for sharing.
+	// They allow each party to verify its share.
+	Commitments *polynomial.Exponent
+}
+
+// SplitSeed splits the Ed25519 private key derived from a 32 byte RFC 8032 seed.
+// The secret scalar s is obtained by clamping the first half of SHA-512(seed), so that the resulting group key
+// is the same as ed25519.NewKeyFromSeed(seed).Public().
+//
+// Since the second half of the hash is not used by FROST, threshold signatures are not deterministic.
+func SplitSeed(seed []byte, partyIDs party.IDSlice, threshold party.Size) (*Output, error) {
+	if l := len(seed); l != ed25519.SeedSize {
+		return nil, fmt.Errorf("dealer.SplitSeed: seed should be %d bytes (got %d)", ed25519.SeedSize, l)
+	}
+	digest := sha512.Sum512(seed)
+	defer func() {
+		for i := range digest {
+			digest[i] = 0
+		}
+	}()
+
+	var secret ristretto.Scalar
+	if _, err := secret.SetBytesWithClamping(digest[:32]); err != nil {
+		return nil, fmt.Errorf("dealer.SplitSeed: %w", err)
+	}
+	defer secret.Set(ristretto.NewScalar())
+
+	return Split(&secret, partyIDs, threshold)
+}
+
+// Split creates Shamir shares of secret for all parties in partyIDs, such that any threshold+1 of them can sign.
+func Split(secret *ristretto.Scalar, partyIDs party.IDSlice, threshold party.Size) (*Output, error) {
+	if threshold == 0 {
+		return nil, errors.New("dealer.Split: threshold must be at least 1, or a minimum of T+1=2 signers")
+	}
+	if threshold > partyIDs.N()-1 {
+		return nil, errors.New("dealer.Split: threshold must be at most N-1, or a maximum of T+1=N signers")
+	}
+	if partyIDs.Contains(0) {
+		return nil, errors.New("dealer.Split: partyIDs should not contain 0")
+	}
+
+	poly := polynomial.NewPolynomial(threshold, secret)
+	defer poly.Reset()
+
+	secrets := make(map[party.ID]*eddsa.SecretShare, partyIDs.N())
+	shares := make(map[party.ID]*ristretto.Element, partyIDs.N())
+	for _, id := range partyIDs {
+		secrets[id] = eddsa.NewSecretShare(id, poly.Evaluate(id.Scalar()))
+		shares[id] = &secrets[id].Public
+	}
+
+	public, err := eddsa.NewPublic(shares, threshold)
+	if err != nil {
+		return nil, fmt.Errorf("dealer.Split: %w", err)
+	}
+
+	return &Output{
+		Public:      public,
+		Secrets:     secrets,
+		Commitments: polynomial.NewPolynomialExponent(poly),
+	}, nil
+}
+
+// KeyFile returns the data which should be given to the party with the given ID.
+func (o *Output) KeyFile(id party.ID) (*KeyFile, error) {
+	secret, ok := o.Secrets[id]
+	if !ok {
+		return nil, fmt.Errorf("dealer.Output: no share for party %d", id)
+	}
+	commitments, err := o.Commitments.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &KeyFile{
+		Secret:      secret,
+		Public:      o.Public,
+		Commitments: commitments,
+	}, nil
+}
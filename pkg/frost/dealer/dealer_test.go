@@ -0,0 +1,72 @@
+package dealer
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+)
+
+func TestSplitSeed(t *testing.T) {
+	N := party.Size(5)
+	T := party.Size(2)
+	partyIDs := helpers.GenerateSet(N)
+
+	seed := make([]byte, ed25519.SeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := SplitSeed(seed, partyIDs, T)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey)
+	if !bytes.Equal(expected, output.Public.GroupKey.ToEd25519()) {
+		t.Error("group key differs from the Ed25519 public key")
+	}
+
+	for _, id := range partyIDs {
+		kf, err := output.KeyFile(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := json.Marshal(kf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var kf2 KeyFile
+		if err = json.Unmarshal(data, &kf2); err != nil {
+			t.Fatal(err)
+		}
+		if err = kf2.Verify(); err != nil {
+			t.Errorf("party %d: %v", id, err)
+		}
+		if !kf2.Secret.Equal(output.Secrets[id]) {
+			t.Errorf("party %d: secret share was not encoded correctly", id)
+		}
+	}
+
+	// A tampered share is detected
+	kf, _ := output.KeyFile(partyIDs[0])
+	kf.Secret = output.Secrets[partyIDs[1]]
+	kf.Secret.ID = partyIDs[0]
+	if err = kf.Verify(); err == nil {
+		t.Error("Verify should fail for a share which does not match the commitments")
+	}
+}
+
+func TestSplitSeed_Invalid(t *testing.T) {
+	partyIDs := helpers.GenerateSet(3)
+	if _, err := SplitSeed(make([]byte, 31), partyIDs, 1); err == nil {
+		t.Error("expected error for a short seed")
+	}
+	if _, err := SplitSeed(make([]byte, 32), partyIDs, 3); err == nil {
+		t.Error("expected error for a threshold too large")
+	}
+}
@@ -0,0 +1,53 @@
+package dealer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/polynomial"
+)
+
+// KeyFile contains everything a single party needs after a trusted dealer split the key.
+// It is JSON encoded, and must be kept secret since it contains the party's SecretShare.
+type KeyFile struct {
+	Secret *eddsa.SecretShare `json:"secret"`
+	Public *eddsa.Public      `json:"public"`
+
+	// Commitments is the marshalled polynomial.Exponent of the dealer's sharing polynomial.
+	Commitments []byte `json:"commitments"`
+}
+
+// Verify checks that the party's share is consistent with the dealer's commitments,
+// and that the public shares all lie on the same polynomial.
+func (kf *KeyFile) Verify() error {
+	if kf.Secret == nil || kf.Public == nil {
+		return errors.New("dealer.KeyFile: missing fields")
+	}
+
+	var commitments polynomial.Exponent
+	if err := commitments.UnmarshalBinary(kf.Commitments); err != nil {
+		return fmt.Errorf("dealer.KeyFile: %w", err)
+	}
+	if commitments.Degree() != kf.Public.Threshold {
+		return errors.New("dealer.KeyFile: commitments have the wrong degree")
+	}
+	if !eddsa.NewPublicKeyFromPoint(commitments.Constant()).Equal(kf.Public.GroupKey) {
+		return errors.New("dealer.KeyFile: commitments do not match the group key")
+	}
+
+	for _, id := range kf.Public.PartyIDs {
+		if commitments.Evaluate(id.Scalar()).Equal(kf.Public.Shares[id]) != 1 {
+			return fmt.Errorf("dealer.KeyFile: public share of party %d does not match the commitments", id)
+		}
+	}
+
+	ownPublic, ok := kf.Public.Shares[kf.Secret.ID]
+	if !ok {
+		return errors.New("dealer.KeyFile: owner of SecretShare is not contained in Public")
+	}
+	if ownPublic.Equal(&kf.Secret.Public) != 1 {
+		return errors.New("dealer.KeyFile: SecretShare does not match its public share")
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package dealer
+
+import (
+	"crypto/sha512"
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+)
+
+// ExpandedKeySize is the size of an expanded Ed25519 private key s || prefix.
+const ExpandedKeySize = 64
+
+// prefixDomain is used to derive the prefix of a reconstructed key, since the original seed can not be recovered.
+const prefixDomain = "FROST-Ed25519 reconstructed prefix"
+
+// PrivateKey is a full Ed25519 signing key, recovered from the shares of a threshold key.
+//
+// It should only be used in break-glass situations, since the key now exists in a single place,
+// and the security guarantees of the threshold scheme no longer hold.
+// Call Reset once the key is no longer needed.
+type PrivateKey struct {
+	// Secret is the signing scalar s, such that A = [s]•B
+	Secret ristretto.Scalar
+
+	// Prefix is used to derive the nonces for deterministic signatures.
+	// It is derived from Secret, and therefore differs from the prefix of the original seed, if any.
+	Prefix [32]byte
+
+	// Public is the group key
+	Public *eddsa.PublicKey
+}
+
+// Reconstruct recovers the full signing key from at least Threshold+1 secret shares.
+// Each share is checked against its public share in public, and the result is checked against the group key.
+func Reconstruct(shares []*eddsa.SecretShare, public *eddsa.Public) (*PrivateKey, error) {
+	if len(shares) <= int(public.Threshold) {
+		return nil, fmt.Errorf("dealer.Reconstruct: %d shares were given, but at least %d are required", len(shares), public.Threshold+1)
+	}
+
+	ids := make([]party.ID, 0, len(shares))
+	for _, share := range shares {
+		ownPublic, ok := public.Shares[share.ID]
+		if !ok {
+			return nil, fmt.Errorf("dealer.Reconstruct: party %d is not contained in public", share.ID)
+		}
+		var computed ristretto.Element
+		computed.ScalarBaseMult(&share.Secret)
+		if computed.Equal(ownPublic) != 1 {
+			return nil, fmt.Errorf("dealer.Reconstruct: share of party %d does not match its public share", share.ID)
+		}
+		ids = append(ids, share.ID)
+	}
+	partyIDs := party.NewIDSlice(ids)
+	if partyIDs.N() != party.Size(len(shares)) {
+		return nil, errors.New("dealer.Reconstruct: shares contain duplicate IDs")
+	}
+
+	// s = ∑ᵢ λᵢ⋅sᵢ
+	var sk PrivateKey
+	for _, share := range shares {
+		lagrange, err := share.ID.Lagrange(partyIDs)
+		if err != nil {
+			return nil, fmt.Errorf("dealer.Reconstruct: %w", err)
+		}
+		sk.Secret.MultiplyAdd(lagrange, &share.Secret, &sk.Secret)
+	}
+
+	var groupKey ristretto.Element
+	groupKey.ScalarBaseMult(&sk.Secret)
+	sk.Public = eddsa.NewPublicKeyFromPoint(&groupKey)
+	if !sk.Public.Equal(public.GroupKey) {
+		sk.Reset()
+		return nil, errors.New("dealer.Reconstruct: recovered key does not match the group key")
+	}
+
+	digest := sha512.Sum512(append([]byte(prefixDomain), sk.Secret.Bytes()...))
+	copy(sk.Prefix[:], digest[:32])
+	return &sk, nil
+}
+
+// Expanded returns the 64 byte expanded private key s || prefix, as used by other Ed25519 implementations
+// which accept an already hashed seed.
+func (sk *PrivateKey) Expanded() []byte {
+	out := make([]byte, 0, ExpandedKeySize)
+	out = append(out, sk.Secret.Bytes()...)
+	out = append(out, sk.Prefix[:]...)
+	return out
+}
+
+// Sign returns a deterministic Ed25519 signature of message, as defined in RFC 8032,
+// which can be verified with ed25519.Verify.
+func (sk *PrivateKey) Sign(message []byte) *eddsa.Signature {
+	var sig eddsa.Signature
+
+	// r = H(prefix || M)
+	h := sha512.New()
+	_, _ = h.Write(sk.Prefix[:])
+	_, _ = h.Write(message)
+	var r ristretto.Scalar
+	_, _ = r.SetUniformBytes(h.Sum(nil))
+	sig.R.ScalarBaseMult(&r)
+
+	// S = r + k⋅s
+	k := eddsa.ComputeChallenge(&sig.R, sk.Public, message)
+	sig.S.MultiplyAdd(k, &sk.Secret, &r)
+
+	r.Set(ristretto.NewScalar())
+	return &sig
+}
+
+// Reset erases the secret data of the key.
+func (sk *PrivateKey) Reset() {
+	sk.Secret.Set(ristretto.NewScalar())
+	for i := range sk.Prefix {
+		sk.Prefix[i] = 0
+	}
+}
@@ -0,0 +1,72 @@
+package dealer
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+)
+
+func TestReconstruct(t *testing.T) {
+	N := party.Size(5)
+	T := party.Size(2)
+	partyIDs := helpers.GenerateSet(N)
+
+	seed := make([]byte, ed25519.SeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		t.Fatal(err)
+	}
+	output, err := SplitSeed(seed, partyIDs, T)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shares := []*eddsa.SecretShare{
+		output.Secrets[partyIDs[4]],
+		output.Secrets[partyIDs[1]],
+		output.Secrets[partyIDs[2]],
+	}
+	sk, err := Reconstruct(shares, output.Public)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expanded := sk.Expanded()
+	if len(expanded) != ExpandedKeySize {
+		t.Fatal("wrong size for expanded key")
+	}
+	pk := ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey)
+	if !bytes.Equal(pk, sk.Public.ToEd25519()) {
+		t.Error("reconstructed key differs from the original")
+	}
+
+	message := []byte("break glass")
+	sig := sk.Sign(message)
+	if !ed25519.Verify(pk, message, sig.ToEd25519()) {
+		t.Error("signature with reconstructed key failed to verify")
+	}
+	if !bytes.Equal(sig.ToEd25519(), sk.Sign(message).ToEd25519()) {
+		t.Error("signatures should be deterministic")
+	}
+
+	// Not enough shares
+	if _, err = Reconstruct(shares[:2], output.Public); err == nil {
+		t.Error("expected error with only t shares")
+	}
+
+	// Duplicate shares
+	if _, err = Reconstruct([]*eddsa.SecretShare{shares[0], shares[1], shares[1]}, output.Public); err == nil {
+		t.Error("expected error with duplicate shares")
+	}
+
+	// Invalid share
+	bad := *shares[0]
+	bad.Secret.Add(&bad.Secret, &shares[1].Secret)
+	if _, err = Reconstruct([]*eddsa.SecretShare{&bad, shares[1], shares[2]}, output.Public); err == nil {
+		t.Error("expected error with an invalid share")
+	}
+}
@@ -1,12 +1,18 @@
 package frost
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/keygen"
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/refresh"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/repair"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/reshare"
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/sign"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
 	"github.com/taurusgroup/frost-ed25519/pkg/state"
 )
 
@@ -14,7 +20,13 @@ import (
 // The second parameter is the output of the protocol and will be filled with the output once the protocol has finished executing.
 // It is safe to use the output when State.WaitForError() returns nil.
 func NewKeygenState(selfID party.ID, partyIDs party.IDSlice, threshold party.Size, timeout time.Duration) (*state.State, *keygen.Output, error) {
-	round, output, err := keygen.NewRound(selfID, partyIDs, threshold)
+	return NewKeygenStateWithOptions(selfID, partyIDs, threshold, nil, timeout)
+}
+
+// NewKeygenStateWithOptions is like NewKeygenState, but runs the protocol with the given options.
+// All parties must use the same options.
+func NewKeygenStateWithOptions(selfID party.ID, partyIDs party.IDSlice, threshold party.Size, opts *keygen.Options, timeout time.Duration) (*state.State, *keygen.Output, error) {
+	round, output, err := keygen.NewRoundWithOptions(selfID, partyIDs, threshold, opts)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -39,3 +51,185 @@ func NewSignState(partyIDs party.IDSlice, secret *eddsa.SecretShare, shares *edd
 
 	return s, output, nil
 }
+
+// NewSignStateWithOptions is similar to NewSignState, but allows the signing session to be configured with opts.
+// In particular, opts.Signature selects the Ed25519ctx or Ed25519ph variants.
+func NewSignStateWithOptions(partyIDs party.IDSlice, secret *eddsa.SecretShare, shares *eddsa.Public, message []byte, opts *sign.Options, timeout time.Duration) (*state.State, *sign.Output, error) {
+	round, output, err := sign.NewRoundWithOptions(partyIDs, secret, shares, message, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	s, err := state.NewBaseState(round, timeout)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s, output, nil
+}
+
+// NewPreprocessedSignState is similar to NewSignStateWithOptions, but uses the nonce commitments published during preprocessing.
+// The resulting protocol only requires a single round of communication.
+// See sign.NewPreprocessedRound for a description of the additional parameters.
+func NewPreprocessedSignState(partyIDs party.IDSlice, secret *eddsa.SecretShare, shares *eddsa.Public, message []byte, opts *sign.Options, nonces *sign.Nonces, commitments *sign.Commitments, indices map[party.ID]uint32, timeout time.Duration) (*state.State, *sign.Output, error) {
+	round, output, err := sign.NewPreprocessedRound(partyIDs, secret, shares, message, opts, nonces, commitments, indices)
+	if err != nil {
+		return nil, nil, err
+	}
+	s, err := state.NewBaseState(round, timeout)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s, output, nil
+}
+
+// NewMultiSignState is similar to NewSignStateWithOptions, but signs all given messages in a single session.
+// The signers exchange only two messages each, whatever the number of messages.
+// Once the protocol has finished, output.Signatures[j] is the signature of msgs[j].
+func NewMultiSignState(partyIDs party.IDSlice, secret *eddsa.SecretShare, shares *eddsa.Public, msgs [][]byte, opts *sign.Options, timeout time.Duration) (*state.State, *sign.MultiOutput, error) {
+	round, output, err := sign.NewMultiRound(partyIDs, secret, shares, msgs, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	s, err := state.NewBaseState(round, timeout)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s, output, nil
+}
+
+// NewCoordinatorState returns a state.State for the coordinator of a signing session, which does not hold a secret share.
+// The signers must create their State with NewSignStateWithOptions, with opts.Coordinator set to coordinator.
+// Once the protocol has finished, the signature is only available in the output of the coordinator.
+func NewCoordinatorState(coordinator party.ID, signerIDs party.IDSlice, shares *eddsa.Public, message []byte, opts *sign.Options, timeout time.Duration) (*state.State, *sign.Output, error) {
+	round, output, err := sign.NewCoordinatorRound(coordinator, signerIDs, shares, message, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	s, err := state.NewBaseState(round, timeout)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s, output, nil
+}
+
+// NewPreprocessedCoordinatorState is similar to NewCoordinatorState, but uses the nonce commitments published during preprocessing.
+// The signers must create their State with NewPreprocessedSignState, with opts.Coordinator set to coordinator.
+func NewPreprocessedCoordinatorState(coordinator party.ID, signerIDs party.IDSlice, shares *eddsa.Public, message []byte, opts *sign.Options, commitments *sign.Commitments, indices map[party.ID]uint32, timeout time.Duration) (*state.State, *sign.Output, error) {
+	round, output, err := sign.NewPreprocessedCoordinatorRound(coordinator, signerIDs, shares, message, opts, commitments, indices)
+	if err != nil {
+		return nil, nil, err
+	}
+	s, err := state.NewBaseState(round, timeout)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s, output, nil
+}
+
+// RestartSignState creates a new signing State after an identifiable abort of a previous session with the same message.
+// The error err must be the *state.Blame returned by the aborted State, and partyIDs the signers of that session.
+// The options opts must be those of the aborted session, so that the same statement is signed.
+// The new session is identified by sessionID, which must differ from the one of the aborted session,
+// and must be the same for all remaining signers.
+// All blamed parties are excluded from the new session, which also runs in identifiable abort mode.
+// It returns an error if fewer than Threshold+1 honest signers remain, or if we were blamed ourselves.
+//
+// A party which sends different messages to different signers can cause the honest signers to blame different sets of parties.
+// They then disagree on the signers of the new session, which fails to complete.
+// The blame reports should be compared, for example through the coordinator, before restarting.
+func RestartSignState(err error, partyIDs party.IDSlice, secret *eddsa.SecretShare, shares *eddsa.Public, message []byte, opts *sign.Options, sessionID messages.SessionID, timeout time.Duration) (*state.State, *sign.Output, error) {
+	var blame *state.Blame
+	if !errors.As(err, &blame) {
+		return nil, nil, errors.New("frost.RestartSignState: error is not a blame report")
+	}
+	honest, err := blame.Honest(partyIDs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("frost.RestartSignState: %w", err)
+	}
+	if !honest.Contains(secret.ID) {
+		return nil, nil, errors.New("frost.RestartSignState: owner of SecretShare was blamed")
+	}
+	if honest.N() < shares.Threshold+1 {
+		return nil, nil, fmt.Errorf("frost.RestartSignState: only %d honest signers remain, %d are required", honest.N(), shares.Threshold+1)
+	}
+
+	s, output, err := NewSignStateWithOptions(honest, secret, shares, message, opts, timeout)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.SetSessionID(sessionID)
+	s.SetIdentifiableAbort(true)
+	return s, output, nil
+}
+
+// ResumeSignState resumes a signing State suspended with State.Suspend, and sets its NonceStore.
+// The store must be the one used when the State was created, so that nonces consumed since then are refused.
+// The returned output is filled once the protocol has finished executing.
+func ResumeSignState(data []byte, store sign.NonceStore) (*state.State, *sign.Output, error) {
+	s, err := state.Resume(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("frost.ResumeSignState: %w", err)
+	}
+	round, ok := s.GetRound().(interface{ SetNonceStore(sign.NonceStore) })
+	if !ok {
+		return nil, nil, errors.New("frost.ResumeSignState: not a signing State")
+	}
+	round.SetNonceStore(store)
+	output, _ := s.GetRound().GetOutput().(*sign.Output)
+	return s, output, nil
+}
+
+// NewRefreshState returns a state.State which coordinates the multiple rounds of the share refresh protocol.
+// All parties in public.PartyIDs must participate. The output contains new shares of the same group key,
+// after which the previous shares should be discarded.
+// It is safe to use the output when State.WaitForError() returns nil.
+func NewRefreshState(secret *eddsa.SecretShare, public *eddsa.Public, timeout time.Duration) (*state.State, *refresh.Output, error) {
+	round, output, err := refresh.NewRound(secret, public)
+	if err != nil {
+		return nil, nil, err
+	}
+	s, err := state.NewBaseState(round, timeout)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s, output, nil
+}
+
+// NewReshareState returns a state.State which coordinates the multiple rounds of the resharing protocol.
+// The key described by public is transferred from the parties in dealers to those in newPartyIDs, with a new threshold.
+// The parameter secret must be nil if selfID is not one of the dealers.
+// It is safe to use the output when State.WaitForError() returns nil.
+func NewReshareState(selfID party.ID, secret *eddsa.SecretShare, public *eddsa.Public, dealers, newPartyIDs party.IDSlice, newThreshold party.Size, timeout time.Duration) (*state.State, *reshare.Output, error) {
+	round, output, err := reshare.NewRound(selfID, secret, public, dealers, newPartyIDs, newThreshold)
+	if err != nil {
+		return nil, nil, err
+	}
+	s, err := state.NewBaseState(round, timeout)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s, output, nil
+}
+
+// NewRepairState returns a state.State which coordinates the multiple rounds of the share repair protocol.
+// The parties in helpers jointly recover the SecretShare of the party lost, which is checked against public.
+// The parameter secret must be nil if selfID is lost.
+// It is safe to use the output when State.WaitForError() returns nil.
+func NewRepairState(selfID party.ID, secret *eddsa.SecretShare, public *eddsa.Public, helpers party.IDSlice, lost party.ID, timeout time.Duration) (*state.State, *repair.Output, error) {
+	round, output, err := repair.NewRound(selfID, secret, public, helpers, lost)
+	if err != nil {
+		return nil, nil, err
+	}
+	s, err := state.NewBaseState(round, timeout)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s, output, nil
+}
@@ -31,9 +31,39 @@ type (
 		// CommitmentsSum is the sum of all commitments, we use it to compute public key shares
 		CommitmentsSum *polynomial.Exponent
 
-		// Commitments contains all other parties commitment polynomials
+		// Commitments contains all parties commitment polynomials, including our own
 		Commitments map[party.ID]*polynomial.Exponent
 
+		// ChainCodes contains all parties' contributions to the chain code, including our own
+		ChainCodes map[party.ID][]byte
+
+		// EncryptionSecret is our ephemeral X25519 private key, used to decrypt the shares we receive
+		EncryptionSecret []byte
+
+		// EncryptionKeys contains all parties' ephemeral X25519 public keys, including our own
+		EncryptionKeys map[party.ID][]byte
+
+		// Digests contains the digests of all KeyGen1 messages, including our own, which we echo to the other parties
+		Digests map[party.ID][]byte
+
+		Options Options
+
+		// Shares contains the valid shares we received, so that they can be removed if their sender is disqualified.
+		// It is only used when Options.Complaints is set.
+		Shares map[party.ID]*ristretto.Scalar
+
+		// Complaints maps each party to the parties it accused in the complaint phase
+		Complaints map[party.ID]party.IDSlice
+
+		// Disqualified contains the parties which failed to justify themselves after a complaint
+		Disqualified party.IDSlice
+
+		// ComplaintDigests contains the digests of all KeyGen3 messages, including our own, which we echo in KeyGen4
+		ComplaintDigests map[party.ID][]byte
+
+		// JustificationDigests contains the digests of the KeyGen4 messages of the accused parties, which we echo in KeyGen5
+		JustificationDigests map[party.ID][]byte
+
 		Output *Output
 	}
 	Round1 struct {
@@ -42,9 +72,38 @@ type (
 	Round2 struct {
 		*Round1
 	}
+	Round3 struct {
+		*Round2
+	}
+	Round4 struct {
+		*Round3
+	}
+	Round5 struct {
+		*Round4
+	}
 )
 
+func init() {
+	state.RegisterRound("keygen.Round0", func() state.Round { return new(Round0) })
+	state.RegisterRound("keygen.Round1", func() state.Round { return new(Round1) })
+	state.RegisterRound("keygen.Round2", func() state.Round { return new(Round2) })
+	state.RegisterRound("keygen.Round3", func() state.Round { return new(Round3) })
+	state.RegisterRound("keygen.Round4", func() state.Round { return new(Round4) })
+	state.RegisterRound("keygen.Round5", func() state.Round { return new(Round5) })
+
+	state.RegisterError("keygen.ErrVSS", ErrVSS)
+	state.RegisterError("keygen.ErrNotEnoughQualified", ErrNotEnoughQualified)
+	state.RegisterError("keygen.ErrEquivocation", ErrEquivocation)
+	state.RegisterError("keygen.ErrComplaintEquivocation", ErrComplaintEquivocation)
+	state.RegisterError("keygen.ErrDecryptShare", ErrDecryptShare)
+}
+
 func NewRound(selfID party.ID, partyIDs party.IDSlice, threshold party.Size) (state.Round, *Output, error) {
+	return NewRoundWithOptions(selfID, partyIDs, threshold, nil)
+}
+
+// NewRoundWithOptions is similar to NewRound, but allows the key generation to be configured with opts.
+func NewRoundWithOptions(selfID party.ID, partyIDs party.IDSlice, threshold party.Size, opts *Options) (state.Round, *Output, error) {
 	N := partyIDs.N()
 
 	if threshold == 0 {
@@ -60,10 +119,20 @@ func NewRound(selfID party.ID, partyIDs party.IDSlice, threshold party.Size) (st
 	}
 
 	r := Round0{
-		BaseRound:   baseRound,
-		Threshold:   threshold,
-		Commitments: make(map[party.ID]*polynomial.Exponent, N),
-		Output:      &Output{},
+		BaseRound:            baseRound,
+		Threshold:            threshold,
+		Commitments:          make(map[party.ID]*polynomial.Exponent, N),
+		ChainCodes:           make(map[party.ID][]byte, N),
+		EncryptionKeys:       make(map[party.ID][]byte, N),
+		Digests:              make(map[party.ID][]byte, N),
+		Shares:               make(map[party.ID]*ristretto.Scalar, N),
+		Complaints:           make(map[party.ID]party.IDSlice, N),
+		ComplaintDigests:     make(map[party.ID][]byte, N),
+		JustificationDigests: make(map[party.ID][]byte, N),
+		Output:               &Output{},
+	}
+	if opts != nil {
+		r.Options = *opts
 	}
 
 	return &r, r.Output, nil
@@ -71,11 +140,21 @@ func NewRound(selfID party.ID, partyIDs party.IDSlice, threshold party.Size) (st
 
 func (round *Round0) Reset() {
 	round.Secret.Set(ristretto.NewScalar())
-	round.Polynomial.Reset()
-	round.CommitmentsSum.Reset()
+	if round.Polynomial != nil {
+		round.Polynomial.Reset()
+	}
+	if round.CommitmentsSum != nil {
+		round.CommitmentsSum.Reset()
+	}
 	for _, p := range round.Commitments {
 		p.Reset()
 	}
+	for i := range round.EncryptionSecret {
+		round.EncryptionSecret[i] = 0
+	}
+	for _, s := range round.Shares {
+		s.Set(ristretto.NewScalar())
+	}
 	round.Output = nil
 }
 
@@ -84,6 +163,10 @@ func (round *Round0) Reset() {
 // ---
 
 func (round *Round0) AcceptedMessageTypes() []messages.MessageType {
+	if round.Options.Complaints {
+		return []messages.MessageType{messages.MessageTypeNone, messages.MessageTypeKeyGen1, messages.MessageTypeKeyGen2,
+			messages.MessageTypeKeyGen3, messages.MessageTypeKeyGen4, messages.MessageTypeKeyGen5}
+	}
 	return []messages.MessageType{messages.MessageTypeNone, messages.MessageTypeKeyGen1, messages.MessageTypeKeyGen2}
 }
 
@@ -101,6 +184,26 @@ type Round0JSON struct {
 	Commitments map[party.ID][]byte `json:"commitments,omitempty"`
 
 	Output *Output `json:"output,omitempty"`
+
+	ChainCodes map[party.ID][]byte `json:"chain_codes,omitempty"`
+
+	EncryptionSecret []byte `json:"encryption_secret,omitempty"`
+
+	EncryptionKeys map[party.ID][]byte `json:"encryption_keys,omitempty"`
+
+	Digests map[party.ID][]byte `json:"digests,omitempty"`
+
+	Options Options `json:"options"`
+
+	Shares map[party.ID][]byte `json:"shares,omitempty"`
+
+	Complaints map[party.ID]party.IDSlice `json:"complaints,omitempty"`
+
+	Disqualified party.IDSlice `json:"disqualified,omitempty"`
+
+	ComplaintDigests map[party.ID][]byte `json:"complaint_digests,omitempty"`
+
+	JustificationDigests map[party.ID][]byte `json:"justification_digests,omitempty"`
 }
 
 func (round *Round0) MarshalJSON() ([]byte, error) {
@@ -120,13 +223,21 @@ func (round *Round0) MarshalJSON() ([]byte, error) {
 		}
 	}
 
-	comdata, err := round.CommitmentsSum.MarshalBinary()
-	if err != nil {
-		fmt.Println(err)
+	// CommitmentsSum is only set once Round0 has generated its messages
+	var comdata []byte
+	if round.CommitmentsSum != nil {
+		if comdata, err = round.CommitmentsSum.MarshalBinary(); err != nil {
+			return nil, err
+		}
 	}
 
 	sec := round.Secret.Bytes()
 
+	var sharesData = make(map[party.ID][]byte, len(round.Shares))
+	for id, s := range round.Shares {
+		sharesData[id] = s.Bytes()
+	}
+
 	rawJson := Round0JSON{
 		baseBytes,
 		round.Threshold,
@@ -135,6 +246,16 @@ func (round *Round0) MarshalJSON() ([]byte, error) {
 		comdata,
 		commitmentsData,
 		round.Output,
+		round.ChainCodes,
+		round.EncryptionSecret,
+		round.EncryptionKeys,
+		round.Digests,
+		round.Options,
+		sharesData,
+		round.Complaints,
+		round.Disqualified,
+		round.ComplaintDigests,
+		round.JustificationDigests,
 	}
 	result, err := json.Marshal(rawJson)
 
@@ -157,9 +278,6 @@ func (round *Round0) MarshalJSON() ([]byte, error) {
 	//
 	//fmt.Println("r2----------------------------end")
 
-	fmt.Println("r-----------------------", round.SelfID())
-	fmt.Println(rawJson)
-	fmt.Println("r-----------------------end")
 	if err != nil {
 		return nil, err
 	}
@@ -187,23 +305,60 @@ func (round *Round0) UnmarshalJSON(data []byte) error {
 		commitments[id] = &exponent
 	}
 
-	var commitmentsSum polynomial.Exponent
-	err = commitmentsSum.UnmarshalBinary(rawJson.CommitmentsSum)
-	if err != nil {
-		fmt.Println(err)
+	var commitmentsSum *polynomial.Exponent
+	if rawJson.CommitmentsSum != nil {
+		commitmentsSum = new(polynomial.Exponent)
+		if err = commitmentsSum.UnmarshalBinary(rawJson.CommitmentsSum); err != nil {
+			return err
+		}
 	}
 
 	var sec = ristretto.NewScalar()
 	sec, err = sec.SetCanonicalBytes(rawJson.Secret)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	round.Threshold = rawJson.Threshold
 	round.Secret = *sec
 	round.Polynomial = rawJson.Polynomial
-	round.CommitmentsSum = &commitmentsSum
+	round.CommitmentsSum = commitmentsSum
 	round.Commitments = commitments
+	round.ChainCodes = rawJson.ChainCodes
+	if round.ChainCodes == nil {
+		round.ChainCodes = make(map[party.ID][]byte, len(commitments))
+	}
+	round.EncryptionSecret = rawJson.EncryptionSecret
+	round.EncryptionKeys = rawJson.EncryptionKeys
+	if round.EncryptionKeys == nil {
+		round.EncryptionKeys = make(map[party.ID][]byte, len(commitments))
+	}
+	round.Digests = rawJson.Digests
+	if round.Digests == nil {
+		round.Digests = make(map[party.ID][]byte, len(commitments))
+	}
+	round.Options = rawJson.Options
+	round.Shares = make(map[party.ID]*ristretto.Scalar, len(rawJson.Shares))
+	for id, v := range rawJson.Shares {
+		var s ristretto.Scalar
+		if _, err := s.SetCanonicalBytes(v); err != nil {
+			return err
+		}
+		round.Shares[id] = &s
+	}
+	round.Complaints = rawJson.Complaints
+	if round.Complaints == nil {
+		round.Complaints = make(map[party.ID]party.IDSlice, len(commitments))
+	}
+	round.Disqualified = rawJson.Disqualified
+	round.ComplaintDigests = rawJson.ComplaintDigests
+	if round.ComplaintDigests == nil {
+		round.ComplaintDigests = make(map[party.ID][]byte, len(commitments))
+	}
+	round.JustificationDigests = rawJson.JustificationDigests
+	if round.JustificationDigests == nil {
+		round.JustificationDigests = make(map[party.ID][]byte, len(commitments))
+	}
 	round.Output = rawJson.Output
 	round.BaseRound = &baseRound
 
@@ -243,3 +398,54 @@ func (round *Round2) UnmarshalJSON(data []byte) error {
 	round.Round1 = &round1
 	return nil
 }
+
+func (round *Round3) MarshalJSON() ([]byte, error) {
+
+	var round2 = round.Round2
+	data, err := json.Marshal(&round2)
+	return data, err
+}
+
+func (round *Round3) UnmarshalJSON(data []byte) error {
+	var round2 Round2
+	err := json.Unmarshal(data, &round2)
+	if err != nil {
+		return err
+	}
+	round.Round2 = &round2
+	return nil
+}
+
+func (round *Round4) MarshalJSON() ([]byte, error) {
+
+	var round3 = round.Round3
+	data, err := json.Marshal(&round3)
+	return data, err
+}
+
+func (round *Round4) UnmarshalJSON(data []byte) error {
+	var round3 Round3
+	err := json.Unmarshal(data, &round3)
+	if err != nil {
+		return err
+	}
+	round.Round3 = &round3
+	return nil
+}
+
+func (round *Round5) MarshalJSON() ([]byte, error) {
+
+	var round4 = round.Round4
+	data, err := json.Marshal(&round4)
+	return data, err
+}
+
+func (round *Round5) UnmarshalJSON(data []byte) error {
+	var round4 Round4
+	err := json.Unmarshal(data, &round4)
+	if err != nil {
+		return err
+	}
+	round.Round4 = &round4
+	return nil
+}
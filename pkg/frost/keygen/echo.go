@@ -0,0 +1,92 @@
+package keygen
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+// The KeyGen1 messages are supposed to be broadcast, but a malicious party can send different commitments
+// to different parties if the broadcast channel is really point-to-point.
+// To detect this, every party echoes the digests of all KeyGen1 messages it received in its KeyGen2 messages.
+// Before computing the output, each party checks that all other parties received the same KeyGen1 messages.
+//
+// The digest of a KeyGen1 message is
+//
+//	SHA-512("FROST-Ed25519 KeyGen1 echo" ∥ message)[:32]
+//
+// where message is the full marshalled message, including the header.
+//
+// Since the echo is included in the associated data of the encrypted share, it is authenticated by the sender's
+// ephemeral key. If the echo of party Pⱼ disagrees with our view of the message of Pₗ, then Pₗ is blamed.
+// Messages are not signed, so Pⱼ could also be lying about what it received.
+// The error returned contains the message of Pⱼ, so that this case can be investigated.
+//
+// The broadcasts of the complaint phase are checked in the same way, since the parties would otherwise disagree
+// on who is disqualified, and compute different keys.
+// KeyGen4 echoes the digests of all KeyGen3 messages, computed with "FROST-Ed25519 KeyGen3 echo".
+// KeyGen5 echoes the digests of the KeyGen4 messages of the accused parties, computed with "FROST-Ed25519 KeyGen4 echo".
+// A mismatch is blamed on the party whose message differs with ErrComplaintEquivocation.
+
+var (
+	echoDomainSeparation              = []byte("FROST-Ed25519 KeyGen1 echo")
+	complaintEchoDomainSeparation     = []byte("FROST-Ed25519 KeyGen3 echo")
+	justificationEchoDomainSeparation = []byte("FROST-Ed25519 KeyGen4 echo")
+)
+
+var (
+	// ErrEquivocation is returned when a party sent different KeyGen1 messages to different parties.
+	ErrEquivocation = errors.New("party sent different KeyGen1 messages to other parties")
+
+	// ErrComplaintEquivocation is returned when a party sent different KeyGen3 or KeyGen4 messages to different parties.
+	ErrComplaintEquivocation = errors.New("party sent different complaint phase messages to other parties")
+)
+
+// echoDigest returns the digest of msg, as included in the echo.
+// The SessionID of msg must be set, since it is part of the envelope.
+func echoDigest(domain []byte, msg *messages.Message) ([]byte, error) {
+	data, err := msg.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	h := sha512.New()
+	_, _ = h.Write(domain)
+	_, _ = h.Write(data)
+	return h.Sum(nil)[:messages.EchoDigestSize], nil
+}
+
+// digestOf returns the digest of the message of id, or zeros if we did not receive one.
+func digestOf(digests map[party.ID][]byte, id party.ID) []byte {
+	if digest, ok := digests[id]; ok {
+		return digest
+	}
+	return make([]byte, messages.EchoDigestSize)
+}
+
+// echoDigests returns the digests of the messages of the parties in ids, in the same order.
+func echoDigests(ids party.IDSlice, digests map[party.ID][]byte) [][]byte {
+	echo := make([][]byte, 0, len(ids))
+	for _, id := range ids {
+		echo = append(echo, digestOf(digests, id))
+	}
+	return echo
+}
+
+// checkEcho verifies that the party from received the same messages as us from the parties in ids.
+// Otherwise, the party whose message differs is blamed with errEquivocation.
+func checkEcho(from party.ID, echo [][]byte, ids party.IDSlice, digests map[party.ID][]byte, errEquivocation error) *state.Error {
+	if len(echo) != len(ids) {
+		return state.NewError(from, errors.New("echo has wrong length"))
+	}
+	for i, id := range ids {
+		if !bytes.Equal(echo[i], digestOf(digests, id)) {
+			return state.NewError(id, fmt.Errorf("%w (reported by party %d)", errEquivocation, from))
+		}
+	}
+	return nil
+}
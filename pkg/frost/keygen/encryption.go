@@ -0,0 +1,99 @@
+package keygen
+
+import (
+	"crypto/sha256"
+	"errors"
+	"io"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+)
+
+// The shares sent in KeyGen2 are encrypted with ChaCha20-Poly1305.
+// Every party samples an ephemeral X25519 key pair, and includes the public key in its KeyGen1 broadcast.
+// The key used by Pᵢ to encrypt the share for Pⱼ is
+//
+//	k = HKDF-SHA256(X25519(xᵢ, Xⱼ), info = "FROST-Ed25519 KeyGen2 share" ∥ i ∥ j ∥ Xᵢ ∥ Xⱼ)
+//
+// Since this key depends on the direction, and is only used once, the nonce is set to zero.
+// The IDs of the sender and receiver, and the sender's echo, are given as associated data.
+
+var shareEncryptionDomainSeparation = []byte("FROST-Ed25519 KeyGen2 share")
+
+// ErrDecryptShare is returned when a share sent in KeyGen2 could not be decrypted.
+var ErrDecryptShare = errors.New("failed to decrypt share")
+
+// shareKey returns the key with which from encrypts its share for to.
+// self must be either from or to, and the other party's key must have been received.
+func (round *Round0) shareKey(from, to party.ID) ([]byte, error) {
+	other := from
+	if other == round.SelfID() {
+		other = to
+	}
+	shared, err := curve25519.X25519(round.EncryptionSecret, round.EncryptionKeys[other])
+	if err != nil {
+		return nil, err
+	}
+
+	info := make([]byte, 0, len(shareEncryptionDomainSeparation)+2*party.IDByteSize+2*curve25519.PointSize)
+	info = append(info, shareEncryptionDomainSeparation...)
+	info = append(info, from.Bytes()...)
+	info = append(info, to.Bytes()...)
+	info = append(info, round.EncryptionKeys[from]...)
+	info = append(info, round.EncryptionKeys[to]...)
+
+	key := make([]byte, chacha20poly1305.KeySize)
+	if _, err = io.ReadFull(hkdf.New(sha256.New, shared, nil, info), key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func shareAssociatedData(from, to party.ID, echo []byte) []byte {
+	ad := make([]byte, 0, 2*party.IDByteSize+len(echo))
+	ad = append(ad, from.Bytes()...)
+	ad = append(ad, to.Bytes()...)
+	return append(ad, echo...)
+}
+
+// encryptShare encrypts the share sent by us to the party to, and authenticates our echo.
+func (round *Round0) encryptShare(to party.ID, share *ristretto.Scalar, echo []byte) ([]byte, error) {
+	key, err := round.shareKey(round.SelfID(), to)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, chacha20poly1305.NonceSize)
+	return aead.Seal(nil, nonce, share.Bytes(), shareAssociatedData(round.SelfID(), to, echo)), nil
+}
+
+// decryptShare decrypts the share sent to us by the party from, and checks the authenticity of its echo.
+func (round *Round0) decryptShare(from party.ID, ciphertext, echo []byte) (*ristretto.Scalar, error) {
+	key, err := round.shareKey(from, round.SelfID())
+	if err != nil {
+		return nil, err
+	}
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, chacha20poly1305.NonceSize)
+	plaintext, err := aead.Open(nil, nonce, ciphertext, shareAssociatedData(from, round.SelfID(), echo))
+	if err != nil {
+		return nil, ErrDecryptShare
+	}
+	var share ristretto.Scalar
+	if _, err = share.SetCanonicalBytes(plaintext); err != nil {
+		return nil, err
+	}
+	for i := range plaintext {
+		plaintext[i] = 0
+	}
+	return &share, nil
+}
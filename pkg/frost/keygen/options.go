@@ -0,0 +1,12 @@
+package keygen
+
+// Options configures a key generation.
+// A nil *Options is equivalent to the zero value.
+type Options struct {
+	// Complaints enables the complaint phase, in which parties which sent an invalid share can justify themselves.
+	// A party which fails to do so is disqualified, and the key generation continues without it,
+	// as long as at least threshold+1 parties remain qualified.
+	// It adds one round, or two if any party complains.
+	// All parties must use the same value.
+	Complaints bool `json:"complaints,omitempty"`
+}
@@ -3,16 +3,23 @@ package keygen
 import (
 	"encoding/json"
 	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
 )
 
 type Output struct {
 	Public    *eddsa.Public
 	SecretKey *eddsa.SecretShare
+
+	// Disqualified contains the parties which failed to justify themselves during the complaint phase.
+	// Their contributions were excluded from the key.
+	Disqualified party.IDSlice
 }
 
 type outputJson struct {
 	Public []byte `json:"public"`
 	Secret []byte `json:"secret"`
+
+	Disqualified party.IDSlice `json:"disqualified,omitempty"`
 }
 
 func (o *Output) MarshalJSON() ([]byte, error) {
@@ -29,6 +36,7 @@ func (o *Output) MarshalJSON() ([]byte, error) {
 	var jsonData = outputJson{
 		pdata,
 		sdata,
+		o.Disqualified,
 	}
 
 	return json.Marshal(jsonData)
@@ -56,6 +64,7 @@ func (o *Output) UnmarshalJSON(data []byte) error {
 
 	o.Public = pub
 	o.SecretKey = secret
+	o.Disqualified = jsonData.Disqualified
 	return err
 
 }
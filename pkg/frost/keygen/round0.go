@@ -1,11 +1,14 @@
 package keygen
 
 import (
+	"crypto/rand"
+
 	"github.com/taurusgroup/frost-ed25519/pkg/internal/polynomial"
 	"github.com/taurusgroup/frost-ed25519/pkg/internal/scalar"
 	"github.com/taurusgroup/frost-ed25519/pkg/internal/zk"
 	"github.com/taurusgroup/frost-ed25519/pkg/messages"
 	"github.com/taurusgroup/frost-ed25519/pkg/state"
+	"golang.org/x/crypto/curve25519"
 )
 
 func (round *Round0) ProcessMessage(*messages.Message) *state.Error {
@@ -23,9 +26,11 @@ func (round *Round0) GenerateMessages() ([]*messages.Message, *state.Error) {
 	// Generate all commitments [a_{i j}] B for j = 0, 1, ..., t
 	// CommitmentsSum holds the sum of all commitments, so we initialize it to our commitment
 	round.CommitmentsSum = polynomial.NewPolynomialExponent(round.Polynomial)
+	round.Commitments[round.SelfID()] = round.CommitmentsSum.Copy()
 
-	// TODO we can use custom contexts to prevent replay attacks
-	ctx := make([]byte, 32)
+	// The proof is bound to the session, so that it cannot be replayed in another one
+	sessionID := round.SessionID()
+	ctx := sessionID[:]
 	public := round.CommitmentsSum.Constant()
 	// Generate proof of knowledge of a_i,0 = f(0)
 	proof := zk.NewSchnorrProof(round.SelfID(), public, ctx, &round.Secret)
@@ -35,7 +40,30 @@ func (round *Round0) GenerateMessages() ([]*messages.Message, *state.Error) {
 	// Bonus, we overwrite the original secret which is no longer needed.
 	round.Secret.Set(round.Polynomial.Evaluate(round.SelfID().Scalar()))
 
-	msg := messages.NewKeyGen1(round.SelfID(), proof, round.CommitmentsSum)
+	// Sample our contribution to the chain code
+	chainCode := make([]byte, messages.ChainCodeSize)
+	if _, err := rand.Read(chainCode); err != nil {
+		return nil, state.NewError(0, err)
+	}
+	round.ChainCodes[round.SelfID()] = chainCode
+
+	// Sample the ephemeral key pair used to encrypt the shares sent to us
+	round.EncryptionSecret = make([]byte, curve25519.ScalarSize)
+	if _, err := rand.Read(round.EncryptionSecret); err != nil {
+		return nil, state.NewError(0, err)
+	}
+	encryptionKey, err := curve25519.X25519(round.EncryptionSecret, curve25519.Basepoint)
+	if err != nil {
+		return nil, state.NewError(0, err)
+	}
+	round.EncryptionKeys[round.SelfID()] = encryptionKey
+
+	msg := messages.NewKeyGen1(round.SelfID(), proof, chainCode, encryptionKey, round.CommitmentsSum)
+	// The digest covers the envelope, so it must include the session ID which State sets on the message
+	msg.SessionID = sessionID
+	if round.Digests[round.SelfID()], err = echoDigest(echoDomainSeparation, msg); err != nil {
+		return nil, state.NewError(0, err)
+	}
 
 	return []*messages.Message{msg}, nil
 }
@@ -4,12 +4,13 @@ import (
 	"errors"
 
 	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
 	"github.com/taurusgroup/frost-ed25519/pkg/state"
 )
 
 func (round *Round1) ProcessMessage(msg *messages.Message) *state.Error {
-	// TODO we can use custom contexts to prevent replay attacks
-	ctx := make([]byte, 32)
+	sessionID := round.SessionID()
+	ctx := sessionID[:]
 	from := msg.From
 
 	public := msg.KeyGen1.Commitments.Constant()
@@ -17,7 +18,20 @@ func (round *Round1) ProcessMessage(msg *messages.Message) *state.Error {
 		return state.NewError(from, errors.New("ZK Schnorr failed"))
 	}
 
+	digest, err := echoDigest(echoDomainSeparation, msg)
+	if err != nil {
+		return state.NewError(from, err)
+	}
+	round.Digests[from] = digest
+
 	round.Commitments[from] = msg.KeyGen1.Commitments
+	round.ChainCodes[from] = msg.KeyGen1.ChainCode
+
+	// Make sure we can derive the keys used to exchange shares with this party
+	round.EncryptionKeys[from] = msg.KeyGen1.EncryptionKey
+	if _, err := round.shareKey(round.SelfID(), from); err != nil {
+		return state.NewError(from, errors.New("invalid encryption key"))
+	}
 
 	// Add the commitments to our own, so that we can interpolate the final polynomial
 	_ = round.CommitmentsSum.Add(msg.KeyGen1.Commitments)
@@ -26,16 +40,27 @@ func (round *Round1) ProcessMessage(msg *messages.Message) *state.Error {
 
 func (round *Round1) GenerateMessages() ([]*messages.Message, *state.Error) {
 	msgsOut := make([]*messages.Message, 0, len(round.PartyIDs())-1)
+	echo := echoDigests(round.PartyIDs(), round.Digests)
+	echoBytes := (&messages.KeyGen2{Echo: echo}).EchoBytes()
 	for _, id := range round.PartyIDs() {
 		if id == round.SelfID() {
 			continue
 		}
-		msgsOut = append(msgsOut, messages.NewKeyGen2(round.SelfID(), id, round.Polynomial.Evaluate(id.Scalar())))
+		share := round.Polynomial.Evaluate(id.Scalar())
+		ciphertext, err := round.encryptShare(id, share, echoBytes)
+		share.Set(ristretto.NewScalar())
+		if err != nil {
+			return nil, state.NewError(id, err)
+		}
+		msgsOut = append(msgsOut, messages.NewKeyGen2(round.SelfID(), id, ciphertext, echo))
 	}
 
 	// Now that we have received the commitment from every one,
-	// we no longer require the original polynomial, so we reset it
-	round.Polynomial.Reset()
+	// we no longer require the original polynomial, so we reset it.
+	// With complaints, it is kept until we know whether we need to reveal some shares.
+	if !round.Options.Complaints {
+		round.Polynomial.Reset()
+	}
 
 	return msgsOut, nil
 }
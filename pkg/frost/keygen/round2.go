@@ -1,36 +1,105 @@
 package keygen
 
 import (
+	"crypto/sha512"
 	"errors"
-	"fmt"
 
 	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/polynomial"
 	"github.com/taurusgroup/frost-ed25519/pkg/messages"
 	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
 	"github.com/taurusgroup/frost-ed25519/pkg/state"
 )
 
+var chainCodeDomainSeparation = []byte("FROST-Ed25519 chain code")
+
+var (
+	// ErrVSS is returned when a share does not match the commitments of its sender.
+	ErrVSS = errors.New("VSS failed to validate")
+
+	// ErrNotEnoughQualified is returned when too many parties were disqualified in the complaint phase.
+	ErrNotEnoughQualified = errors.New("not enough qualified parties remain")
+)
+
 func (round *Round2) ProcessMessage(msg *messages.Message) *state.Error {
+	id := msg.From
+
+	share, err := round.decryptShare(id, msg.KeyGen2.Ciphertext, msg.KeyGen2.EchoBytes())
+	if err != nil {
+		if round.Options.Complaints {
+			round.complain(id)
+			return nil
+		}
+		return state.NewError(id, err)
+	}
+	// We can reset the decrypted share once it has been added to our secret
+	defer share.Set(ristretto.NewScalar())
+
+	// Make sure the sender received the same KeyGen1 messages as us
+	if stateErr := checkEcho(id, msg.KeyGen2.Echo, round.PartyIDs(), round.Digests, ErrEquivocation); stateErr != nil {
+		return stateErr
+	}
 	var computedShareExp ristretto.Element
-	computedShareExp.ScalarBaseMult(&msg.KeyGen2.Share)
+	computedShareExp.ScalarBaseMult(share)
 
-	id := msg.From
 	shareExp := round.Commitments[id].Evaluate(round.SelfID().Scalar())
 
-	fmt.Println("round2ProcessMessage...", id, msg.KeyGen2.Share, round.Commitments[id], round.SelfID())
 	if computedShareExp.Equal(shareExp) != 1 {
-		return state.NewError(id, errors.New("VSS failed to validate"))
+		if round.Options.Complaints {
+			round.complain(id)
+			return nil
+		}
+		return state.NewError(id, ErrVSS)
+	}
+	round.Secret.Add(&round.Secret, share)
+	if round.Options.Complaints {
+		round.Shares[id] = new(ristretto.Scalar).Set(share)
 	}
-	round.Secret.Add(&round.Secret, &msg.KeyGen2.Share)
-
-	// We can reset the share in the message now
-	msg.KeyGen2.Share.Set(ristretto.NewScalar())
 
 	return nil
 }
 
 func (round *Round2) GenerateMessages() ([]*messages.Message, *state.Error) {
+	if round.Options.Complaints {
+		msg := messages.NewKeyGen3(round.SelfID(), round.Complaints[round.SelfID()])
+		msg.SessionID = round.SessionID()
+		digest, err := echoDigest(complaintEchoDomainSeparation, msg)
+		if err != nil {
+			return nil, state.NewError(0, err)
+		}
+		round.ComplaintDigests[round.SelfID()] = digest
+		return []*messages.Message{msg}, nil
+	}
+	return nil, round.finalize()
+}
+
+// finalize computes the output from the contributions of all qualified parties.
+func (round *Round0) finalize() *state.Error {
+	if len(round.Disqualified) > 0 {
+		qualified := make([]*polynomial.Exponent, 0, len(round.PartyIDs()))
+		for _, id := range round.qualified() {
+			qualified = append(qualified, round.Commitments[id])
+		}
+		if len(qualified) <= int(round.Threshold) {
+			return state.NewError(0, ErrNotEnoughQualified)
+		}
+		sum, err := polynomial.Sum(qualified)
+		if err != nil {
+			return state.NewError(0, err)
+		}
+		round.CommitmentsSum = sum
+
+		for _, id := range round.Disqualified {
+			if share, ok := round.Shares[id]; ok {
+				round.Secret.Subtract(&round.Secret, share)
+			}
+		}
+	}
+	if round.Polynomial != nil {
+		round.Polynomial.Reset()
+	}
+
 	shares := make(map[party.ID]*ristretto.Element, round.PartyIDs().N())
 	for _, id := range round.PartyIDs() {
 		shares[id] = round.CommitmentsSum.Evaluate(id.Scalar())
@@ -40,12 +109,30 @@ func (round *Round2) GenerateMessages() ([]*messages.Message, *state.Error) {
 		Threshold: round.Threshold,
 		Shares:    shares,
 		GroupKey:  eddsa.NewPublicKeyFromPoint(round.CommitmentsSum.Constant()),
+		ChainCode: round.chainCode(),
 	}
 	round.Output.SecretKey = eddsa.NewSecretShare(round.SelfID(), &round.Secret)
-	return nil, nil
+	round.Output.Disqualified = round.Disqualified.Copy()
+	return nil
+}
+
+// chainCode combines the contributions of all parties into the group's chain code.
+//
+//	chainCode = SHA-512("FROST-Ed25519 chain code" ∥ ID₁ ∥ c₁ ∥ ... ∥ IDₙ ∥ cₙ)[:32]
+func (round *Round0) chainCode() []byte {
+	h := sha512.New()
+	_, _ = h.Write(chainCodeDomainSeparation)
+	for _, id := range round.PartyIDs() {
+		_, _ = h.Write(id.Bytes())
+		_, _ = h.Write(round.ChainCodes[id])
+	}
+	return h.Sum(nil)[:eddsa.ChainCodeSize]
 }
 
 func (round *Round2) NextRound() state.Round {
+	if round.Options.Complaints {
+		return &Round3{round}
+	}
 	return nil
 }
 
@@ -0,0 +1,112 @@
+package keygen
+
+import (
+	"errors"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+// The complaint phase is only run when Options.Complaints is set.
+//
+// Instead of aborting when the share of Pⱼ fails to decrypt or to validate, Pᵢ includes j in its KeyGen3 complaint,
+// which every party broadcasts (possibly empty).
+// Every party then broadcasts a KeyGen4 message echoing the complaints it received.
+// The KeyGen4 message of an accused party Pⱼ also reveals fⱼ(i) for every party Pᵢ which complained about it.
+// All parties check the revealed shares against the commitments of Pⱼ, and disqualify Pⱼ if any of them is missing or invalid.
+// Otherwise, the complaining parties use the revealed shares.
+// If some party was accused, every party finally broadcasts a KeyGen5 message echoing the justifications it received.
+//
+// The output is computed with the contributions of the qualified parties only.
+
+// complain records that the share received from id was invalid.
+func (round *Round0) complain(id party.ID) {
+	self := round.SelfID()
+	round.Complaints[self] = party.NewIDSlice(append(round.Complaints[self].Copy(), id))
+}
+
+// accused returns all parties which are the subject of a complaint.
+func (round *Round0) accused() party.IDSlice {
+	var accused []party.ID
+	for _, id := range round.PartyIDs() {
+		for _, complaint := range round.Complaints {
+			if complaint.Contains(id) {
+				accused = append(accused, id)
+				break
+			}
+		}
+	}
+	return party.NewIDSlice(accused)
+}
+
+// qualified returns the parties which were not disqualified.
+func (round *Round0) qualified() party.IDSlice {
+	qualified := make(party.IDSlice, 0, len(round.PartyIDs()))
+	for _, id := range round.PartyIDs() {
+		if !round.Disqualified.Contains(id) {
+			qualified = append(qualified, id)
+		}
+	}
+	return qualified
+}
+
+// disqualify excludes id from the parties contributing to the key.
+func (round *Round0) disqualify(id party.ID) {
+	if !round.Disqualified.Contains(id) {
+		round.Disqualified = party.NewIDSlice(append(round.Disqualified.Copy(), id))
+	}
+}
+
+func (round *Round3) ProcessMessage(msg *messages.Message) *state.Error {
+	from := msg.From
+	for _, id := range msg.KeyGen3.Accused {
+		if id == from || !round.PartyIDs().Contains(id) {
+			return state.NewError(from, errors.New("invalid complaint"))
+		}
+	}
+	digest, err := echoDigest(complaintEchoDomainSeparation, msg)
+	if err != nil {
+		return state.NewError(from, err)
+	}
+	round.ComplaintDigests[from] = digest
+	round.Complaints[from] = msg.KeyGen3.Accused
+	return nil
+}
+
+func (round *Round3) GenerateMessages() ([]*messages.Message, *state.Error) {
+	// Reveal the shares we sent to the parties which complained about us
+	var shares map[party.ID]*ristretto.Scalar
+	for id, complaint := range round.Complaints {
+		if complaint.Contains(round.SelfID()) {
+			if shares == nil {
+				shares = make(map[party.ID]*ristretto.Scalar)
+			}
+			shares[id] = round.Polynomial.Evaluate(id.Scalar())
+		}
+	}
+
+	msg := messages.NewKeyGen4(round.SelfID(), echoDigests(round.PartyIDs(), round.ComplaintDigests), shares)
+	if shares != nil {
+		msg.SessionID = round.SessionID()
+		digest, err := echoDigest(justificationEchoDomainSeparation, msg)
+		if err != nil {
+			return nil, state.NewError(0, err)
+		}
+		round.JustificationDigests[round.SelfID()] = digest
+	}
+	return []*messages.Message{msg}, nil
+}
+
+func (round *Round3) NextRound() state.Round {
+	return &Round4{round}
+}
+
+func (round *Round3) MessageType() messages.MessageType {
+	return messages.MessageTypeKeyGen3
+}
+
+func (round *Round3) GetOutput() interface{} {
+	return round.Output
+}
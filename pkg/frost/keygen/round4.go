@@ -0,0 +1,102 @@
+package keygen
+
+import (
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+// ExpectedSenders implements state.ExpectedSenders.
+// The accused parties which were disqualified after the timeout are no longer expected.
+func (round *Round4) ExpectedSenders() party.IDSlice {
+	return round.qualified()
+}
+
+// ProcessTimeout implements state.TimeoutHandler.
+// An accused party which does not justify itself in time is disqualified, so that the honest parties can continue without it.
+// If any other party is missing, we abort.
+func (round *Round4) ProcessTimeout(missing party.IDSlice) bool {
+	accused := round.accused()
+	for _, id := range missing {
+		if !accused.Contains(id) {
+			return false
+		}
+	}
+	for _, id := range missing {
+		round.disqualify(id)
+	}
+	return true
+}
+
+func (round *Round4) ProcessMessage(msg *messages.Message) *state.Error {
+	from := msg.From
+
+	// Make sure the sender received the same complaints as us
+	if stateErr := checkEcho(from, msg.KeyGen4.Echo, round.PartyIDs(), round.ComplaintDigests, ErrComplaintEquivocation); stateErr != nil {
+		return stateErr
+	}
+	if !round.accused().Contains(from) {
+		return nil
+	}
+
+	digest, err := echoDigest(justificationEchoDomainSeparation, msg)
+	if err != nil {
+		return state.NewError(from, err)
+	}
+	round.JustificationDigests[from] = digest
+
+	commitments := round.Commitments[from]
+	var revealed *ristretto.Scalar
+	for id, complaint := range round.Complaints {
+		if !complaint.Contains(from) {
+			continue
+		}
+		share, ok := msg.KeyGen4.Shares[id]
+		if !ok {
+			round.disqualify(from)
+			return nil
+		}
+		var shareExp ristretto.Element
+		shareExp.ScalarBaseMult(share)
+		if shareExp.Equal(commitments.Evaluate(id.Scalar())) != 1 {
+			round.disqualify(from)
+			return nil
+		}
+		if id == round.SelfID() {
+			revealed = share
+		}
+	}
+
+	// The justification is valid, so we use the revealed share instead of the one we complained about
+	if revealed != nil {
+		round.Secret.Add(&round.Secret, revealed)
+		round.Shares[from] = new(ristretto.Scalar).Set(revealed)
+	}
+	return nil
+}
+
+func (round *Round4) GenerateMessages() ([]*messages.Message, *state.Error) {
+	accused := round.accused()
+	if len(accused) == 0 {
+		return nil, round.finalize()
+	}
+
+	// Make sure all parties received the same justifications, and therefore disqualify the same parties
+	return []*messages.Message{messages.NewKeyGen5(round.SelfID(), echoDigests(accused, round.JustificationDigests))}, nil
+}
+
+func (round *Round4) NextRound() state.Round {
+	if len(round.accused()) == 0 {
+		return nil
+	}
+	return &Round5{round}
+}
+
+func (round *Round4) MessageType() messages.MessageType {
+	return messages.MessageTypeKeyGen4
+}
+
+func (round *Round4) GetOutput() interface{} {
+	return round.Output
+}
@@ -0,0 +1,34 @@
+package keygen
+
+import (
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+// ExpectedSenders implements state.ExpectedSenders.
+// The disqualified parties no longer contribute to the key, so we do not wait for them.
+func (round *Round5) ExpectedSenders() party.IDSlice {
+	return round.qualified()
+}
+
+func (round *Round5) ProcessMessage(msg *messages.Message) *state.Error {
+	// Make sure the sender received the same justifications as us
+	return checkEcho(msg.From, msg.KeyGen5.Echo, round.accused(), round.JustificationDigests, ErrComplaintEquivocation)
+}
+
+func (round *Round5) GenerateMessages() ([]*messages.Message, *state.Error) {
+	return nil, round.finalize()
+}
+
+func (round *Round5) NextRound() state.Round {
+	return nil
+}
+
+func (round *Round5) MessageType() messages.MessageType {
+	return messages.MessageTypeKeyGen5
+}
+
+func (round *Round5) GetOutput() interface{} {
+	return round.Output
+}
@@ -111,10 +111,22 @@ func (id *ID) UnmarshalText(text []byte) error {
 //
 // returns an error if id is not included in partyIDs
 func (id ID) Lagrange(partyIDs IDSlice) (*ristretto.Scalar, error) {
+	return id.LagrangeAt(0, partyIDs)
+}
+
+// LagrangeAt gives the Lagrange coefficient lⱼ(x) for x = at.
+// It can be used to interpolate the value of a polynomial at the point of a party not included in partyIDs.
+//
+//			( x  - x₀) ... ( x  - xₖ)
+// lⱼ(x) =	---------------------------
+//			(xⱼ - x₀) ... (xⱼ - xₖ)
+//
+// returns an error if id is not included in partyIDs
+func (id ID) LagrangeAt(at ID, partyIDs IDSlice) (*ristretto.Scalar, error) {
 	if id == 0 {
-		return nil, errors.New("party.ID: Lagrange: id was 0 (invalid)")
+		return nil, errors.New("party.ID: LagrangeAt: id was 0 (invalid)")
 	}
-	var one, num, denum, xM, xJ ristretto.Scalar
+	var one, num, denum, x, xM, xJ, tmp ristretto.Scalar
 
 	// we can't use scalar.NewScalarUInt32() since that would cause an import cycle
 	_, _ = one.SetCanonicalBytes([]byte{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0})
@@ -122,6 +134,7 @@ func (id ID) Lagrange(partyIDs IDSlice) (*ristretto.Scalar, error) {
 	num.Set(&one)
 	denum.Set(&one)
 
+	x = *at.Scalar()
 	xJ = *id.Scalar()
 
 	foundSelfInIDs := false
@@ -133,19 +146,20 @@ func (id ID) Lagrange(partyIDs IDSlice) (*ristretto.Scalar, error) {
 
 		xM = *partyID.Scalar()
 
-		// num = x₀ * ... * xₖ
-		num.Multiply(&num, &xM) // num * xM
+		// num = (x - x₀) ... (x - xₖ)
+		tmp.Subtract(&x, &xM)
+		num.Multiply(&num, &tmp)
 
-		// denum = (x₀ - xⱼ) ... (xₖ - xⱼ)
-		xM.Subtract(&xM, &xJ)       // = xM - xJ
-		denum.Multiply(&denum, &xM) // denum * (xm - xj)
+		// denum = (xⱼ - x₀) ... (xⱼ - xₖ)
+		tmp.Subtract(&xJ, &xM)
+		denum.Multiply(&denum, &tmp)
 	}
 	if !foundSelfInIDs {
-		return nil, errors.New("party.ID: Lagrange: partyIDs does not containd id")
+		return nil, errors.New("party.ID: LagrangeAt: partyIDs does not contain id")
 	}
 	// check against 0
 	if denum.Equal(ristretto.NewScalar()) == 1 {
-		return nil, errors.New("party.ID: Lagrange: denominator was 0")
+		return nil, errors.New("party.ID: LagrangeAt: denominator was 0")
 	}
 
 	denum.Invert(&denum)
@@ -213,3 +213,39 @@ func TestID_Lagrange(t *testing.T) {
 		})
 	}
 }
+
+func TestID_LagrangeAt(t *testing.T) {
+	partyIDs := IDSlice{2, 3, 5, 8}
+	at := ID(4)
+
+	// For f(x) = x, we should get ∑ⱼ lⱼ(x)⋅xⱼ = x, and the coefficients should sum to 1.
+	sum := ristretto.NewScalar()
+	interpolated := ristretto.NewScalar()
+	for _, id := range partyIDs {
+		coefficient, err := id.LagrangeAt(at, partyIDs)
+		if err != nil {
+			t.Fatalf("LagrangeAt(): unexpected error: %v", err)
+		}
+		sum.Add(sum, coefficient)
+		interpolated.MultiplyAdd(coefficient, id.Scalar(), interpolated)
+	}
+	if scalar.NewScalarUInt32(1).Equal(sum) != 1 {
+		t.Errorf("LagrangeAt(): expected sum of coefficients to be 1")
+	}
+	if at.Scalar().Equal(interpolated) != 1 {
+		t.Errorf("LagrangeAt(): failed to interpolate f(x) = x")
+	}
+
+	// At x = 0, the coefficients are the same as Lagrange
+	for _, id := range partyIDs {
+		l0, _ := id.Lagrange(partyIDs)
+		lAt, _ := id.LagrangeAt(0, partyIDs)
+		if l0.Equal(lAt) != 1 {
+			t.Errorf("LagrangeAt(): coefficient at 0 differs from Lagrange")
+		}
+	}
+
+	if _, err := ID(42).LagrangeAt(at, partyIDs); err == nil {
+		t.Errorf("LagrangeAt(): expected error for id not included")
+	}
+}
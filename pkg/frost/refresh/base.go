@@ -0,0 +1,205 @@
+package refresh
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/polynomial"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+// The refresh protocol re-randomizes the Shamir shares of an existing key, without changing the group key.
+//
+// Each party Pᵢ samples a random polynomial fᵢ of degree t with fᵢ(0) = 0, and sends fᵢ(j) to every party Pⱼ.
+// The new share of Pⱼ is sⱼ' = sⱼ + ∑ᵢ fᵢ(j). Since ∑ᵢ fᵢ(0) = 0, the shared secret and therefore the group key are unchanged.
+// The commitments to the polynomials fᵢ are used to verify the shares received, and to compute the new public shares.
+
+type (
+	Round0 struct {
+		*state.BaseRound
+
+		// Threshold is the degree of the polynomial used for Shamir.
+		// It is the same as the threshold of the shares being refreshed.
+		Threshold party.Size
+
+		// Secret is first set to the party's current share.
+		// Once all received shares are declared, they are added here to produce the party's
+		// new secret key share.
+		Secret ristretto.Scalar
+
+		// Previous holds the public shares of all parties before the refresh.
+		Previous *eddsa.Public
+
+		// Polynomial used to sample the zero shares
+		Polynomial *polynomial.Polynomial
+
+		// CommitmentsSum is the sum of all commitments, we use it to compute the new public key shares
+		CommitmentsSum *polynomial.Exponent
+
+		// Commitments contains all other parties commitment polynomials
+		Commitments map[party.ID]*polynomial.Exponent
+
+		Output *Output
+	}
+	Round1 struct {
+		*Round0
+	}
+	Round2 struct {
+		*Round1
+	}
+)
+
+func init() {
+	state.RegisterRound("refresh.Round0", func() state.Round { return new(Round0) })
+	state.RegisterRound("refresh.Round1", func() state.Round { return new(Round1) })
+	state.RegisterRound("refresh.Round2", func() state.Round { return new(Round2) })
+}
+
+// NewRound creates a round for refreshing the SecretShare of the party, given the public information of all parties.
+// All parties in public.PartyIDs must participate.
+func NewRound(secret *eddsa.SecretShare, public *eddsa.Public) (state.Round, *Output, error) {
+	ownPublic, ok := public.Shares[secret.ID]
+	if !ok {
+		return nil, nil, errors.New("refresh.NewRound: owner of SecretShare is not contained in public")
+	}
+	if ownPublic.Equal(&secret.Public) != 1 {
+		return nil, nil, errors.New("refresh.NewRound: SecretShare does not match its public share")
+	}
+
+	baseRound, err := state.NewBaseRound(secret.ID, public.PartyIDs.Copy())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r := Round0{
+		BaseRound:   baseRound,
+		Threshold:   public.Threshold,
+		Previous:    public,
+		Commitments: make(map[party.ID]*polynomial.Exponent, public.PartyIDs.N()),
+		Output:      &Output{},
+	}
+	r.Secret.Set(&secret.Secret)
+
+	return &r, r.Output, nil
+}
+
+func (round *Round0) Reset() {
+	round.Secret.Set(ristretto.NewScalar())
+	if round.Polynomial != nil {
+		round.Polynomial.Reset()
+	}
+	if round.CommitmentsSum != nil {
+		round.CommitmentsSum.Reset()
+	}
+	for _, p := range round.Commitments {
+		p.Reset()
+	}
+	round.Output = nil
+}
+
+// ---
+// Messages
+// ---
+
+func (round *Round0) AcceptedMessageTypes() []messages.MessageType {
+	return []messages.MessageType{messages.MessageTypeNone, messages.MessageTypeRefresh1, messages.MessageTypeRefresh2}
+}
+
+type Round0JSON struct {
+	Base           *state.BaseRound       `json:"base"`
+	Threshold      party.Size             `json:"threshold"`
+	Secret         []byte                 `json:"secret"`
+	Previous       *eddsa.Public          `json:"previous"`
+	Polynomial     *polynomial.Polynomial `json:"polynomial,omitempty"`
+	CommitmentsSum []byte                 `json:"commitments_sum,omitempty"`
+	Commitments    map[party.ID][]byte    `json:"commitments,omitempty"`
+	Public         *eddsa.Public          `json:"public,omitempty"`
+	SecretKey      *eddsa.SecretShare     `json:"secret_key,omitempty"`
+}
+
+func (round *Round0) MarshalJSON() ([]byte, error) {
+	var err error
+	rawJSON := Round0JSON{
+		Base:        round.BaseRound,
+		Threshold:   round.Threshold,
+		Secret:      round.Secret.Bytes(),
+		Previous:    round.Previous,
+		Polynomial:  round.Polynomial,
+		Commitments: make(map[party.ID][]byte, len(round.Commitments)),
+	}
+	if round.CommitmentsSum != nil {
+		if rawJSON.CommitmentsSum, err = round.CommitmentsSum.MarshalBinary(); err != nil {
+			return nil, err
+		}
+	}
+	for id, c := range round.Commitments {
+		if rawJSON.Commitments[id], err = c.MarshalBinary(); err != nil {
+			return nil, err
+		}
+	}
+	if round.Output != nil {
+		rawJSON.Public = round.Output.Public
+		rawJSON.SecretKey = round.Output.SecretKey
+	}
+	return json.Marshal(rawJSON)
+}
+
+func (round *Round0) UnmarshalJSON(data []byte) error {
+	var rawJSON Round0JSON
+	if err := json.Unmarshal(data, &rawJSON); err != nil {
+		return err
+	}
+	if rawJSON.Base == nil || rawJSON.Previous == nil {
+		return errors.New("refresh.Round0: missing fields")
+	}
+
+	if _, err := round.Secret.SetCanonicalBytes(rawJSON.Secret); err != nil {
+		return err
+	}
+	if rawJSON.CommitmentsSum != nil {
+		round.CommitmentsSum = &polynomial.Exponent{}
+		if err := round.CommitmentsSum.UnmarshalBinary(rawJSON.CommitmentsSum); err != nil {
+			return err
+		}
+	}
+	round.Commitments = make(map[party.ID]*polynomial.Exponent, len(rawJSON.Commitments))
+	for id, c := range rawJSON.Commitments {
+		var exponent polynomial.Exponent
+		if err := exponent.UnmarshalBinary(c); err != nil {
+			return err
+		}
+		round.Commitments[id] = &exponent
+	}
+
+	round.BaseRound = rawJSON.Base
+	round.Threshold = rawJSON.Threshold
+	round.Previous = rawJSON.Previous
+	round.Polynomial = rawJSON.Polynomial
+	round.Output = &Output{
+		Public:    rawJSON.Public,
+		SecretKey: rawJSON.SecretKey,
+	}
+	return nil
+}
+
+func (round *Round1) UnmarshalJSON(data []byte) error {
+	var round0 Round0
+	if err := json.Unmarshal(data, &round0); err != nil {
+		return err
+	}
+	round.Round0 = &round0
+	return nil
+}
+
+func (round *Round2) UnmarshalJSON(data []byte) error {
+	var round1 Round1
+	if err := json.Unmarshal(data, &round1); err != nil {
+		return err
+	}
+	round.Round1 = &round1
+	return nil
+}
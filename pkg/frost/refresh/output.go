@@ -0,0 +1,12 @@
+package refresh
+
+import "github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+
+type Output struct {
+	// Public contains the new public shares of all parties.
+	// Its GroupKey is the same as before the refresh.
+	Public *eddsa.Public
+
+	// SecretKey is the party's new share of the group's signing key.
+	SecretKey *eddsa.SecretShare
+}
@@ -0,0 +1,36 @@
+package refresh
+
+import (
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/polynomial"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+func (round *Round0) ProcessMessage(*messages.Message) *state.Error {
+	return nil
+}
+
+func (round *Round0) GenerateMessages() ([]*messages.Message, *state.Error) {
+	// Sample a polynomial of degree t with constant coefficient 0
+	round.Polynomial = polynomial.NewPolynomial(round.Threshold, ristretto.NewScalar())
+
+	// Generate all commitments [a_{i j}] B for j = 0, 1, ..., t
+	// CommitmentsSum holds the sum of all commitments, so we initialize it to our commitment
+	round.CommitmentsSum = polynomial.NewPolynomialExponent(round.Polynomial)
+
+	// Add the share we would send to ourselves to our current share.
+	round.Secret.Add(&round.Secret, round.Polynomial.Evaluate(round.SelfID().Scalar()))
+
+	msg := messages.NewRefresh1(round.SelfID(), round.CommitmentsSum.Copy())
+
+	return []*messages.Message{msg}, nil
+}
+
+func (round *Round0) NextRound() state.Round {
+	return &Round1{round}
+}
+
+func (round *Round0) GetOutput() interface{} {
+	return round.Output
+}
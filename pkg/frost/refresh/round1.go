@@ -0,0 +1,52 @@
+package refresh
+
+import (
+	"errors"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+func (round *Round1) ProcessMessage(msg *messages.Message) *state.Error {
+	from := msg.From
+	commitments := msg.Refresh1.Commitments
+
+	if commitments.Degree() != round.Threshold {
+		return state.NewError(from, errors.New("refresh polynomial has the wrong degree"))
+	}
+	// The constant coefficient must be 0, otherwise the group key would change
+	if commitments.Constant().Equal(ristretto.NewIdentityElement()) != 1 {
+		return state.NewError(from, errors.New("refresh polynomial does not share 0"))
+	}
+
+	round.Commitments[from] = commitments
+
+	// Add the commitments to our own, so that we can compute the new public shares
+	_ = round.CommitmentsSum.Add(commitments)
+	return nil
+}
+
+func (round *Round1) GenerateMessages() ([]*messages.Message, *state.Error) {
+	msgsOut := make([]*messages.Message, 0, len(round.PartyIDs())-1)
+	for _, id := range round.PartyIDs() {
+		if id == round.SelfID() {
+			continue
+		}
+		msgsOut = append(msgsOut, messages.NewRefresh2(round.SelfID(), id, round.Polynomial.Evaluate(id.Scalar())))
+	}
+
+	// Now that we have received the commitment from every one,
+	// we no longer require the original polynomial, so we reset it
+	round.Polynomial.Reset()
+
+	return msgsOut, nil
+}
+
+func (round *Round1) NextRound() state.Round {
+	return &Round2{round}
+}
+
+func (round *Round1) GetOutput() interface{} {
+	return round.Output
+}
@@ -0,0 +1,64 @@
+package refresh
+
+import (
+	"errors"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+func (round *Round2) ProcessMessage(msg *messages.Message) *state.Error {
+	var computedShareExp ristretto.Element
+	computedShareExp.ScalarBaseMult(&msg.Refresh2.Share)
+
+	id := msg.From
+	shareExp := round.Commitments[id].Evaluate(round.SelfID().Scalar())
+
+	if computedShareExp.Equal(shareExp) != 1 {
+		return state.NewError(id, errors.New("VSS failed to validate"))
+	}
+	round.Secret.Add(&round.Secret, &msg.Refresh2.Share)
+
+	// We can reset the share in the message now
+	msg.Refresh2.Share.Set(ristretto.NewScalar())
+
+	return nil
+}
+
+func (round *Round2) GenerateMessages() ([]*messages.Message, *state.Error) {
+	// Aⱼ' = Aⱼ + ∑ᵢ Fᵢ(j)
+	shares := make(map[party.ID]*ristretto.Element, round.PartyIDs().N())
+	for _, id := range round.PartyIDs() {
+		shares[id] = round.CommitmentsSum.Evaluate(id.Scalar())
+		shares[id].Add(shares[id], round.Previous.Shares[id])
+	}
+
+	public, err := eddsa.NewPublic(shares, round.Threshold)
+	if err != nil {
+		return nil, state.NewError(0, err)
+	}
+	if !public.GroupKey.Equal(round.Previous.GroupKey) {
+		return nil, state.NewError(0, errors.New("group key changed during refresh"))
+	}
+	public.ChainCode = round.Previous.ChainCode
+
+	secretKey := eddsa.NewSecretShare(round.SelfID(), &round.Secret)
+	if secretKey.Public.Equal(public.Shares[round.SelfID()]) != 1 {
+		return nil, state.NewError(0, errors.New("new secret share does not match its public share"))
+	}
+
+	round.Output.Public = public
+	round.Output.SecretKey = secretKey
+	return nil, nil
+}
+
+func (round *Round2) NextRound() state.Round {
+	return nil
+}
+
+func (round *Round2) GetOutput() interface{} {
+	return round.Output
+}
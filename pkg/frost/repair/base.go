@@ -0,0 +1,194 @@
+package repair
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+// The repair protocol lets a set of at least t+1 helpers recover the share sᵣ of a party Pᵣ which lost it.
+//
+// Each helper Pᵢ computes its contribution δᵢ = λᵢ(r)⋅sᵢ, where λᵢ(r) is the Lagrange coefficient at r, so that ∑ᵢ δᵢ = sᵣ.
+// It splits δᵢ into random additive pieces δᵢⱼ, one for every helper Pⱼ.
+// Each helper Pⱼ then sends σⱼ = ∑ᵢ δᵢⱼ to Pᵣ, who computes sᵣ = ∑ⱼ σⱼ.
+// No helper learns anything about sᵣ or the other helpers' shares.
+//
+// The recovered share is checked against the public share Aᵣ. Since the contributions are not verifiable individually,
+// a failure can not be attributed to a particular helper.
+
+type (
+	Round0 struct {
+		*state.BaseRound
+
+		// Helpers is the set of parties helping to recover the lost share
+		Helpers party.IDSlice
+
+		// Lost is the ID of the party whose share is being repaired
+		Lost party.ID
+
+		// Public holds the public shares of all parties, and is used to check the repaired share.
+		Public *eddsa.Public
+
+		// Secret is set to λᵢ(r)⋅sᵢ for a helper.
+		// It is then used to accumulate the pieces received by a helper,
+		// and finally to compute the repaired share.
+		Secret ristretto.Scalar
+
+		Output *Output
+	}
+	Round1 struct {
+		*Round0
+	}
+	Round2 struct {
+		*Round1
+	}
+)
+
+func init() {
+	state.RegisterRound("repair.Round0", func() state.Round { return new(Round0) })
+	state.RegisterRound("repair.Round1", func() state.Round { return new(Round1) })
+	state.RegisterRound("repair.Round2", func() state.Round { return new(Round2) })
+}
+
+// NewRound creates a round for repairing the share of the party lost, with the help of the parties in helpers.
+//
+// The parameter secret must be the party's SecretShare if selfID is one of the helpers, and nil if selfID is lost.
+// All parties in helpers, as well as lost, must participate.
+func NewRound(selfID party.ID, secret *eddsa.SecretShare, public *eddsa.Public, helpers party.IDSlice, lost party.ID) (state.Round, *Output, error) {
+	if !public.PartyIDs.Contains(lost) {
+		return nil, nil, errors.New("repair.NewRound: lost party is not contained in public")
+	}
+	if helpers.Contains(lost) {
+		return nil, nil, errors.New("repair.NewRound: lost party can not be a helper")
+	}
+	if !helpers.IsSubsetOf(public.PartyIDs) {
+		return nil, nil, errors.New("repair.NewRound: helpers must be a subset of public.PartyIDs")
+	}
+	if helpers.N() <= public.Threshold {
+		return nil, nil, errors.New("repair.NewRound: helpers must contain at least Threshold+1 parties")
+	}
+	if selfID == lost {
+		if secret != nil {
+			return nil, nil, errors.New("repair.NewRound: SecretShare was provided by the lost party")
+		}
+	} else if helpers.Contains(selfID) {
+		if secret == nil || secret.ID != selfID {
+			return nil, nil, errors.New("repair.NewRound: a helper must provide its SecretShare")
+		}
+		if public.Shares[selfID].Equal(&secret.Public) != 1 {
+			return nil, nil, errors.New("repair.NewRound: SecretShare does not match its public share")
+		}
+	} else {
+		return nil, nil, errors.New("repair.NewRound: party is neither a helper nor the lost party")
+	}
+
+	allIDs := append(helpers.Copy(), lost)
+	baseRound, err := state.NewBaseRound(selfID, party.NewIDSlice(allIDs))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r := Round0{
+		BaseRound: baseRound,
+		Helpers:   helpers.Copy(),
+		Lost:      lost,
+		Public:    public,
+		Output:    &Output{},
+	}
+
+	if secret != nil {
+		lagrange, err := selfID.LagrangeAt(lost, r.Helpers)
+		if err != nil {
+			return nil, nil, err
+		}
+		r.Secret.Multiply(lagrange, &secret.Secret)
+	}
+
+	return &r, r.Output, nil
+}
+
+func (round *Round0) Reset() {
+	round.Secret.Set(ristretto.NewScalar())
+	round.Output = nil
+}
+
+// isHelper returns true if the party helps recover the lost share.
+func (round *Round0) isHelper() bool {
+	return round.Helpers.Contains(round.SelfID())
+}
+
+// ---
+// Messages
+// ---
+
+func (round *Round0) AcceptedMessageTypes() []messages.MessageType {
+	return []messages.MessageType{messages.MessageTypeNone, messages.MessageTypeRepair1, messages.MessageTypeRepair2}
+}
+
+type Round0JSON struct {
+	Base      *state.BaseRound   `json:"base"`
+	Helpers   party.IDSlice      `json:"helpers"`
+	Lost      party.ID           `json:"lost"`
+	Public    *eddsa.Public      `json:"public"`
+	Secret    []byte             `json:"secret"`
+	SecretKey *eddsa.SecretShare `json:"secret_key,omitempty"`
+}
+
+func (round *Round0) MarshalJSON() ([]byte, error) {
+	rawJSON := Round0JSON{
+		Base:    round.BaseRound,
+		Helpers: round.Helpers,
+		Lost:    round.Lost,
+		Public:  round.Public,
+		Secret:  round.Secret.Bytes(),
+	}
+	if round.Output != nil {
+		rawJSON.SecretKey = round.Output.SecretKey
+	}
+	return json.Marshal(rawJSON)
+}
+
+func (round *Round0) UnmarshalJSON(data []byte) error {
+	var rawJSON Round0JSON
+	if err := json.Unmarshal(data, &rawJSON); err != nil {
+		return err
+	}
+	if rawJSON.Base == nil || rawJSON.Public == nil {
+		return errors.New("repair.Round0: missing fields")
+	}
+
+	if _, err := round.Secret.SetCanonicalBytes(rawJSON.Secret); err != nil {
+		return err
+	}
+	round.BaseRound = rawJSON.Base
+	round.Helpers = rawJSON.Helpers
+	round.Lost = rawJSON.Lost
+	round.Public = rawJSON.Public
+	round.Output = &Output{
+		SecretKey: rawJSON.SecretKey,
+	}
+	return nil
+}
+
+func (round *Round1) UnmarshalJSON(data []byte) error {
+	var round0 Round0
+	if err := json.Unmarshal(data, &round0); err != nil {
+		return err
+	}
+	round.Round0 = &round0
+	return nil
+}
+
+func (round *Round2) UnmarshalJSON(data []byte) error {
+	var round1 Round1
+	if err := json.Unmarshal(data, &round1); err != nil {
+		return err
+	}
+	round.Round1 = &round1
+	return nil
+}
@@ -0,0 +1,9 @@
+package repair
+
+import "github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+
+type Output struct {
+	// SecretKey is the recovered share of the party being repaired.
+	// It is nil for the helpers.
+	SecretKey *eddsa.SecretShare
+}
@@ -0,0 +1,39 @@
+package repair
+
+import (
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/scalar"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+func (round *Round0) ProcessMessage(*messages.Message) *state.Error {
+	return nil
+}
+
+func (round *Round0) GenerateMessages() ([]*messages.Message, *state.Error) {
+	if !round.isHelper() {
+		return nil, nil
+	}
+
+	// Split δᵢ into random pieces δᵢⱼ for every other helper.
+	// We keep the remaining piece δᵢᵢ = δᵢ - ∑ⱼ δᵢⱼ in Secret.
+	msgsOut := make([]*messages.Message, 0, round.Helpers.N()-1)
+	for _, id := range round.Helpers {
+		if id == round.SelfID() {
+			continue
+		}
+		piece := scalar.NewScalarRandom()
+		round.Secret.Subtract(&round.Secret, piece)
+		msgsOut = append(msgsOut, messages.NewRepair1(round.SelfID(), id, piece))
+	}
+
+	return msgsOut, nil
+}
+
+func (round *Round0) NextRound() state.Round {
+	return &Round1{round}
+}
+
+func (round *Round0) GetOutput() interface{} {
+	return round.Output
+}
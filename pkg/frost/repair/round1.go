@@ -0,0 +1,46 @@
+package repair
+
+import (
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+// ExpectedSenders implements state.ExpectedSenders.
+// Only the helpers exchange pieces in this round.
+func (round *Round1) ExpectedSenders() party.IDSlice {
+	if !round.isHelper() {
+		return party.IDSlice{}
+	}
+	return round.Helpers
+}
+
+func (round *Round1) ProcessMessage(msg *messages.Message) *state.Error {
+	// σⱼ = ∑ᵢ δᵢⱼ
+	round.Secret.Add(&round.Secret, &msg.Repair1.Share)
+
+	// We can reset the share in the message now
+	msg.Repair1.Share.Set(ristretto.NewScalar())
+
+	return nil
+}
+
+func (round *Round1) GenerateMessages() ([]*messages.Message, *state.Error) {
+	if !round.isHelper() {
+		return nil, nil
+	}
+
+	msg := messages.NewRepair2(round.SelfID(), round.Lost, &round.Secret)
+	round.Secret.Set(ristretto.NewScalar())
+
+	return []*messages.Message{msg}, nil
+}
+
+func (round *Round1) NextRound() state.Round {
+	return &Round2{round}
+}
+
+func (round *Round1) GetOutput() interface{} {
+	return round.Output
+}
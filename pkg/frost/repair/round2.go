@@ -0,0 +1,51 @@
+package repair
+
+import (
+	"errors"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+// ExpectedSenders implements state.ExpectedSenders.
+// Only the lost party receives messages in this round.
+func (round *Round2) ExpectedSenders() party.IDSlice {
+	if round.SelfID() != round.Lost {
+		return party.IDSlice{}
+	}
+	return round.Helpers
+}
+
+func (round *Round2) ProcessMessage(msg *messages.Message) *state.Error {
+	// sᵣ = ∑ⱼ σⱼ
+	round.Secret.Add(&round.Secret, &msg.Repair2.Share)
+
+	// We can reset the share in the message now
+	msg.Repair2.Share.Set(ristretto.NewScalar())
+
+	return nil
+}
+
+func (round *Round2) GenerateMessages() ([]*messages.Message, *state.Error) {
+	if round.SelfID() != round.Lost {
+		return nil, nil
+	}
+
+	secretKey := eddsa.NewSecretShare(round.SelfID(), &round.Secret)
+	if secretKey.Public.Equal(round.Public.Shares[round.SelfID()]) != 1 {
+		return nil, state.NewError(0, errors.New("repaired share does not match its public share"))
+	}
+	round.Output.SecretKey = secretKey
+	return nil, nil
+}
+
+func (round *Round2) NextRound() state.Round {
+	return nil
+}
+
+func (round *Round2) GetOutput() interface{} {
+	return round.Output
+}
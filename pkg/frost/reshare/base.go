@@ -0,0 +1,265 @@
+package reshare
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/polynomial"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+// The resharing protocol transfers an existing key to a new set of parties, with a new threshold.
+//
+// A quorum of at least t+1 dealers Pᵢ from the old set computes their Lagrange-weighted share λᵢ⋅sᵢ, so that ∑ᵢ λᵢ⋅sᵢ = s.
+// Each dealer then shares λᵢ⋅sᵢ with a polynomial gᵢ of degree t' among the new parties, and publishes the commitments to gᵢ.
+// The new share of Pⱼ is sⱼ' = ∑ᵢ gᵢ(j), and the commitment to its constant coefficient must be λᵢ⋅Aᵢ,
+// where Aᵢ is the dealer's public share. The group key is therefore unchanged.
+
+type (
+	Round0 struct {
+		*state.BaseRound
+
+		// Dealers is the quorum of old parties dealing their shares
+		Dealers party.IDSlice
+
+		// NewPartyIDs is the set of parties receiving the new shares
+		NewPartyIDs party.IDSlice
+
+		// NewThreshold is the degree of the polynomial used for Shamir in the new set
+		NewThreshold party.Size
+
+		// Previous holds the public shares of the old parties.
+		Previous *eddsa.Public
+
+		// Secret is set to λᵢ⋅sᵢ for a dealer.
+		// Once all received shares are declared, it holds the party's new secret key share.
+		Secret ristretto.Scalar
+
+		// Polynomial used to deal the dealer's share
+		Polynomial *polynomial.Polynomial
+
+		// CommitmentsSum is the sum of all commitments, we use it to compute the new public key shares
+		CommitmentsSum *polynomial.Exponent
+
+		// Commitments contains all dealers' commitment polynomials
+		Commitments map[party.ID]*polynomial.Exponent
+
+		Output *Output
+	}
+	Round1 struct {
+		*Round0
+	}
+	Round2 struct {
+		*Round1
+	}
+)
+
+func init() {
+	state.RegisterRound("reshare.Round0", func() state.Round { return new(Round0) })
+	state.RegisterRound("reshare.Round1", func() state.Round { return new(Round1) })
+	state.RegisterRound("reshare.Round2", func() state.Round { return new(Round2) })
+}
+
+// NewRound creates a round for resharing the key described by public, from the parties in dealers to those in newPartyIDs,
+// with a new threshold newThreshold.
+//
+// The parameter secret must be the party's SecretShare if selfID is one of the dealers, and nil otherwise.
+// All parties in dealers and newPartyIDs must participate.
+func NewRound(selfID party.ID, secret *eddsa.SecretShare, public *eddsa.Public, dealers, newPartyIDs party.IDSlice, newThreshold party.Size) (state.Round, *Output, error) {
+	if !dealers.IsSubsetOf(public.PartyIDs) {
+		return nil, nil, errors.New("reshare.NewRound: dealers must be a subset of public.PartyIDs")
+	}
+	if dealers.N() <= public.Threshold {
+		return nil, nil, errors.New("reshare.NewRound: dealers must contain at least Threshold+1 parties")
+	}
+	if newThreshold == 0 {
+		return nil, nil, errors.New("reshare.NewRound: new threshold must be at least 1, or a minimum of T+1=2 signers")
+	}
+	if newThreshold > newPartyIDs.N()-1 {
+		return nil, nil, errors.New("reshare.NewRound: new threshold must be at most N-1, or a maximum of T+1=N signers")
+	}
+	if dealers.Contains(selfID) {
+		if secret == nil || secret.ID != selfID {
+			return nil, nil, errors.New("reshare.NewRound: a dealer must provide its SecretShare")
+		}
+		if public.Shares[selfID].Equal(&secret.Public) != 1 {
+			return nil, nil, errors.New("reshare.NewRound: SecretShare does not match its public share")
+		}
+	} else if secret != nil {
+		return nil, nil, errors.New("reshare.NewRound: SecretShare was provided but party is not a dealer")
+	}
+
+	allIDs := make([]party.ID, 0, dealers.N()+newPartyIDs.N())
+	allIDs = append(allIDs, dealers...)
+	for _, id := range newPartyIDs {
+		if !dealers.Contains(id) {
+			allIDs = append(allIDs, id)
+		}
+	}
+	baseRound, err := state.NewBaseRound(selfID, party.NewIDSlice(allIDs))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r := Round0{
+		BaseRound:    baseRound,
+		Dealers:      dealers.Copy(),
+		NewPartyIDs:  newPartyIDs.Copy(),
+		NewThreshold: newThreshold,
+		Previous:     public,
+		Commitments:  make(map[party.ID]*polynomial.Exponent, dealers.N()),
+		Output:       &Output{},
+	}
+
+	if secret != nil {
+		lagrange, err := selfID.Lagrange(r.Dealers)
+		if err != nil {
+			return nil, nil, err
+		}
+		r.Secret.Multiply(lagrange, &secret.Secret)
+	}
+
+	return &r, r.Output, nil
+}
+
+func (round *Round0) Reset() {
+	round.Secret.Set(ristretto.NewScalar())
+	if round.Polynomial != nil {
+		round.Polynomial.Reset()
+	}
+	if round.CommitmentsSum != nil {
+		round.CommitmentsSum.Reset()
+	}
+	for _, p := range round.Commitments {
+		p.Reset()
+	}
+	round.Output = nil
+}
+
+// isDealer returns true if the party deals its share.
+func (round *Round0) isDealer() bool {
+	return round.Dealers.Contains(round.SelfID())
+}
+
+// isReceiver returns true if the party receives a share in the new set.
+func (round *Round0) isReceiver() bool {
+	return round.NewPartyIDs.Contains(round.SelfID())
+}
+
+// ---
+// Messages
+// ---
+
+func (round *Round0) AcceptedMessageTypes() []messages.MessageType {
+	return []messages.MessageType{messages.MessageTypeNone, messages.MessageTypeReshare1, messages.MessageTypeReshare2}
+}
+
+// ExpectedSenders implements state.ExpectedSenders, since only the dealers send messages.
+func (round *Round0) ExpectedSenders() party.IDSlice {
+	return round.Dealers
+}
+
+type Round0JSON struct {
+	Base           *state.BaseRound       `json:"base"`
+	Dealers        party.IDSlice          `json:"dealers"`
+	NewPartyIDs    party.IDSlice          `json:"new_party_ids"`
+	NewThreshold   party.Size             `json:"new_threshold"`
+	Previous       *eddsa.Public          `json:"previous"`
+	Secret         []byte                 `json:"secret"`
+	Polynomial     *polynomial.Polynomial `json:"polynomial,omitempty"`
+	CommitmentsSum []byte                 `json:"commitments_sum,omitempty"`
+	Commitments    map[party.ID][]byte    `json:"commitments,omitempty"`
+	Public         *eddsa.Public          `json:"public,omitempty"`
+	SecretKey      *eddsa.SecretShare     `json:"secret_key,omitempty"`
+}
+
+func (round *Round0) MarshalJSON() ([]byte, error) {
+	var err error
+	rawJSON := Round0JSON{
+		Base:         round.BaseRound,
+		Dealers:      round.Dealers,
+		NewPartyIDs:  round.NewPartyIDs,
+		NewThreshold: round.NewThreshold,
+		Previous:     round.Previous,
+		Secret:       round.Secret.Bytes(),
+		Polynomial:   round.Polynomial,
+		Commitments:  make(map[party.ID][]byte, len(round.Commitments)),
+	}
+	if round.CommitmentsSum != nil {
+		if rawJSON.CommitmentsSum, err = round.CommitmentsSum.MarshalBinary(); err != nil {
+			return nil, err
+		}
+	}
+	for id, c := range round.Commitments {
+		if rawJSON.Commitments[id], err = c.MarshalBinary(); err != nil {
+			return nil, err
+		}
+	}
+	if round.Output != nil {
+		rawJSON.Public = round.Output.Public
+		rawJSON.SecretKey = round.Output.SecretKey
+	}
+	return json.Marshal(rawJSON)
+}
+
+func (round *Round0) UnmarshalJSON(data []byte) error {
+	var rawJSON Round0JSON
+	if err := json.Unmarshal(data, &rawJSON); err != nil {
+		return err
+	}
+	if rawJSON.Base == nil || rawJSON.Previous == nil {
+		return errors.New("reshare.Round0: missing fields")
+	}
+
+	if _, err := round.Secret.SetCanonicalBytes(rawJSON.Secret); err != nil {
+		return err
+	}
+	if rawJSON.CommitmentsSum != nil {
+		round.CommitmentsSum = &polynomial.Exponent{}
+		if err := round.CommitmentsSum.UnmarshalBinary(rawJSON.CommitmentsSum); err != nil {
+			return err
+		}
+	}
+	round.Commitments = make(map[party.ID]*polynomial.Exponent, len(rawJSON.Commitments))
+	for id, c := range rawJSON.Commitments {
+		var exponent polynomial.Exponent
+		if err := exponent.UnmarshalBinary(c); err != nil {
+			return err
+		}
+		round.Commitments[id] = &exponent
+	}
+
+	round.BaseRound = rawJSON.Base
+	round.Dealers = rawJSON.Dealers
+	round.NewPartyIDs = rawJSON.NewPartyIDs
+	round.NewThreshold = rawJSON.NewThreshold
+	round.Previous = rawJSON.Previous
+	round.Polynomial = rawJSON.Polynomial
+	round.Output = &Output{
+		Public:    rawJSON.Public,
+		SecretKey: rawJSON.SecretKey,
+	}
+	return nil
+}
+
+func (round *Round1) UnmarshalJSON(data []byte) error {
+	var round0 Round0
+	if err := json.Unmarshal(data, &round0); err != nil {
+		return err
+	}
+	round.Round0 = &round0
+	return nil
+}
+
+func (round *Round2) UnmarshalJSON(data []byte) error {
+	var round1 Round1
+	if err := json.Unmarshal(data, &round1); err != nil {
+		return err
+	}
+	round.Round1 = &round1
+	return nil
+}
@@ -0,0 +1,13 @@
+package reshare
+
+import "github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+
+type Output struct {
+	// Public contains the public shares of the new parties, with the new threshold.
+	// Its GroupKey is the same as before the resharing.
+	Public *eddsa.Public
+
+	// SecretKey is the party's share of the group's signing key for the new party set.
+	// It is nil if the party is not part of the new set.
+	SecretKey *eddsa.SecretShare
+}
@@ -0,0 +1,34 @@
+package reshare
+
+import (
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/polynomial"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+func (round *Round0) ProcessMessage(*messages.Message) *state.Error {
+	return nil
+}
+
+func (round *Round0) GenerateMessages() ([]*messages.Message, *state.Error) {
+	if !round.isDealer() {
+		return nil, nil
+	}
+
+	// Sample a polynomial of degree t' with constant coefficient λᵢ⋅sᵢ
+	round.Polynomial = polynomial.NewPolynomial(round.NewThreshold, &round.Secret)
+	commitments := polynomial.NewPolynomialExponent(round.Polynomial)
+	round.Commitments[round.SelfID()] = commitments
+
+	msg := messages.NewReshare1(round.SelfID(), commitments.Copy())
+
+	return []*messages.Message{msg}, nil
+}
+
+func (round *Round0) NextRound() state.Round {
+	return &Round1{round}
+}
+
+func (round *Round0) GetOutput() interface{} {
+	return round.Output
+}
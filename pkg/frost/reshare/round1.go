@@ -0,0 +1,76 @@
+package reshare
+
+import (
+	"errors"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+func (round *Round1) ProcessMessage(msg *messages.Message) *state.Error {
+	from := msg.From
+	commitments := msg.Reshare1.Commitments
+
+	if commitments.Degree() != round.NewThreshold {
+		return state.NewError(from, errors.New("resharing polynomial has the wrong degree"))
+	}
+
+	// The constant coefficient must be λᵢ⋅Aᵢ, otherwise the group key would change
+	lagrange, err := from.Lagrange(round.Dealers)
+	if err != nil {
+		return state.NewError(from, err)
+	}
+	var expected ristretto.Element
+	expected.ScalarMult(lagrange, round.Previous.Shares[from])
+	if commitments.Constant().Equal(&expected) != 1 {
+		return state.NewError(from, errors.New("resharing polynomial does not share the dealer's share"))
+	}
+
+	round.Commitments[from] = commitments
+	return nil
+}
+
+func (round *Round1) GenerateMessages() ([]*messages.Message, *state.Error) {
+	// Sum all commitments, so that we can compute the new public shares
+	for _, id := range round.Dealers {
+		if round.CommitmentsSum == nil {
+			round.CommitmentsSum = round.Commitments[id].Copy()
+		} else if err := round.CommitmentsSum.Add(round.Commitments[id]); err != nil {
+			return nil, state.NewError(id, err)
+		}
+	}
+
+	if !round.isDealer() {
+		round.Secret.Set(ristretto.NewScalar())
+		return nil, nil
+	}
+
+	msgsOut := make([]*messages.Message, 0, round.NewPartyIDs.N())
+	for _, id := range round.NewPartyIDs {
+		if id == round.SelfID() {
+			continue
+		}
+		msgsOut = append(msgsOut, messages.NewReshare2(round.SelfID(), id, round.Polynomial.Evaluate(id.Scalar())))
+	}
+
+	// Keep the share we would send to ourselves, if we are part of the new set
+	if round.isReceiver() {
+		round.Secret.Set(round.Polynomial.Evaluate(round.SelfID().Scalar()))
+	} else {
+		round.Secret.Set(ristretto.NewScalar())
+	}
+
+	// The original polynomial is no longer required, so we reset it
+	round.Polynomial.Reset()
+
+	return msgsOut, nil
+}
+
+func (round *Round1) NextRound() state.Round {
+	return &Round2{round}
+}
+
+func (round *Round1) GetOutput() interface{} {
+	return round.Output
+}
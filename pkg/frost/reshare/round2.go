@@ -0,0 +1,70 @@
+package reshare
+
+import (
+	"errors"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+// ExpectedSenders implements state.ExpectedSenders.
+// Only the parties in the new set receive shares from the dealers.
+func (round *Round2) ExpectedSenders() party.IDSlice {
+	if !round.isReceiver() {
+		return party.IDSlice{}
+	}
+	return round.Dealers
+}
+
+func (round *Round2) ProcessMessage(msg *messages.Message) *state.Error {
+	var computedShareExp ristretto.Element
+	computedShareExp.ScalarBaseMult(&msg.Reshare2.Share)
+
+	id := msg.From
+	shareExp := round.Commitments[id].Evaluate(round.SelfID().Scalar())
+
+	if computedShareExp.Equal(shareExp) != 1 {
+		return state.NewError(id, errors.New("VSS failed to validate"))
+	}
+	round.Secret.Add(&round.Secret, &msg.Reshare2.Share)
+
+	// We can reset the share in the message now
+	msg.Reshare2.Share.Set(ristretto.NewScalar())
+
+	return nil
+}
+
+func (round *Round2) GenerateMessages() ([]*messages.Message, *state.Error) {
+	// Aⱼ' = ∑ᵢ Gᵢ(j)
+	shares := round.CommitmentsSum.EvaluateMulti(round.NewPartyIDs)
+
+	public, err := eddsa.NewPublic(shares, round.NewThreshold)
+	if err != nil {
+		return nil, state.NewError(0, err)
+	}
+	if !public.GroupKey.Equal(round.Previous.GroupKey) {
+		return nil, state.NewError(0, errors.New("group key changed during resharing"))
+	}
+	public.ChainCode = round.Previous.ChainCode
+	round.Output.Public = public
+
+	if round.isReceiver() {
+		secretKey := eddsa.NewSecretShare(round.SelfID(), &round.Secret)
+		if secretKey.Public.Equal(public.Shares[round.SelfID()]) != 1 {
+			return nil, state.NewError(0, errors.New("new secret share does not match its public share"))
+		}
+		round.Output.SecretKey = secretKey
+	}
+	return nil, nil
+}
+
+func (round *Round2) NextRound() state.Round {
+	return nil
+}
+
+func (round *Round2) GetOutput() interface{} {
+	return round.Output
+}
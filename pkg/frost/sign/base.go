@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+
 	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
 	"github.com/taurusgroup/frost-ed25519/pkg/messages"
@@ -18,6 +20,9 @@ type (
 		// Message is the message to be signed
 		Message []byte
 
+		// Options of the signing session
+		Options Options
+
 		// Parties maps IDs to a struct containing all intermediary data for each signer.
 		Parties map[party.ID]*signer
 
@@ -28,6 +33,13 @@ type (
 		// e and d are the scalars committed to in the first round
 		e, d ristretto.Scalar
 
+		// random is the source of the randomness hedged by nonce_generate in ModeRFC9591.
+		// If it is nil, crypto/rand.Reader is used. It is only set to something else in tests.
+		random io.Reader
+
+		// nonceStoreRequired is set when a round created with a NonceStore was unmarshalled
+		nonceStoreRequired bool
+
 		// C = H(R, GroupKey, Message)
 		C ristretto.Scalar
 		// R = ∑ Ri
@@ -43,17 +55,40 @@ type (
 	}
 )
 
-func (round *Round0) MarshalRound() ([]byte, error) {
-	//TODO implement me
-	panic("implement me")
+func init() {
+	state.RegisterRound("sign.Round0", func() state.Round { return new(Round0) })
+	state.RegisterRound("sign.Round1", func() state.Round { return new(Round1) })
+	state.RegisterRound("sign.Round2", func() state.Round { return new(Round2) })
+	state.RegisterRound("sign.PreprocessedRound0", func() state.Round { return new(PreprocessedRound0) })
+	state.RegisterRound("sign.MultiRound0", func() state.Round { return new(MultiRound0) })
+	state.RegisterRound("sign.MultiRound1", func() state.Round { return new(MultiRound1) })
+	state.RegisterRound("sign.MultiRound2", func() state.Round { return new(MultiRound2) })
+
+	state.RegisterError("sign.ErrValidateSigShare", ErrValidateSigShare)
+	state.RegisterError("sign.ErrValidateSignature", ErrValidateSignature)
+	state.RegisterError("sign.ErrCommitmentList", ErrCommitmentList)
+	state.RegisterError("sign.ErrCommitmentUnknown", ErrCommitmentUnknown)
+	state.RegisterError("sign.ErrNonceUnknown", ErrNonceUnknown)
+	state.RegisterError("sign.ErrNonceReused", ErrNonceReused)
+	state.RegisterError("sign.ErrNonceStoreMissing", ErrNonceStoreMissing)
 }
 
-func (round *Round0) UnmarshalRound(data []byte) (state.Round, error) {
-	//TODO implement me
-	panic("implement me")
+func NewRound(partyIDs party.IDSlice, secret *eddsa.SecretShare, shares *eddsa.Public, message []byte) (state.Round, *Output, error) {
+	return NewRoundWithOptions(partyIDs, secret, shares, message, nil)
 }
 
-func NewRound(partyIDs party.IDSlice, secret *eddsa.SecretShare, shares *eddsa.Public, message []byte) (state.Round, *Output, error) {
+// NewRoundWithOptions is similar to NewRound, but allows the session to be configured with opts.
+// For Ed25519ph, message must be the SHA-512 digest of the data to be signed, so that large payloads need not be sent to the signers.
+func NewRoundWithOptions(partyIDs party.IDSlice, secret *eddsa.SecretShare, shares *eddsa.Public, message []byte, opts *Options) (state.Round, *Output, error) {
+	if err := opts.validate(message); err != nil {
+		return nil, nil, fmt.Errorf("base.NewRound: %w", err)
+	}
+	if opts != nil && len(opts.Path) > 0 {
+		var err error
+		if secret, shares, err = secret.Derive(shares, opts.Path); err != nil {
+			return nil, nil, fmt.Errorf("base.NewRound: %w", err)
+		}
+	}
 	if !partyIDs.Contains(secret.ID) {
 		return nil, nil, errors.New("base.NewRound: owner of SecretShare is not contained in partyIDs")
 	}
@@ -61,7 +96,16 @@ func NewRound(partyIDs party.IDSlice, secret *eddsa.SecretShare, shares *eddsa.P
 		return nil, nil, errors.New("base.NewRound: not all parties of partyIDs are contained in shares")
 	}
 
-	baseRound, err := state.NewBaseRound(secret.ID, partyIDs)
+	// The coordinator is a party of the session, but not a signer
+	allPartyIDs := partyIDs
+	if opts != nil && opts.Coordinator != 0 {
+		if partyIDs.Contains(opts.Coordinator) {
+			return nil, nil, errors.New("base.NewRound: coordinator must not be a signer")
+		}
+		allPartyIDs = party.NewIDSlice(append(partyIDs.Copy(), opts.Coordinator))
+	}
+
+	baseRound, err := state.NewBaseRound(secret.ID, allPartyIDs)
 	if err != nil {
 		return nil, nil, fmt.Errorf("base.NewRound: %w", err)
 	}
@@ -73,6 +117,9 @@ func NewRound(partyIDs party.IDSlice, secret *eddsa.SecretShare, shares *eddsa.P
 		GroupKey:  *shares.GroupKey,
 		Output:    &Output{},
 	}
+	if opts != nil {
+		round.Options = *opts
+	}
 
 	// Setup parties
 	for _, id := range partyIDs {
@@ -119,6 +166,12 @@ func (round *Round0) Reset() {
 }
 
 func (round *Round0) AcceptedMessageTypes() []messages.MessageType {
+	if round.Options.Coordinator != 0 && !round.isCoordinator() {
+		return []messages.MessageType{
+			messages.MessageTypeNone,
+			messages.MessageTypeSignCommitments,
+		}
+	}
 	return []messages.MessageType{
 		messages.MessageTypeNone,
 		messages.MessageTypeSign1,
@@ -137,6 +190,12 @@ type Round0JSON struct {
 	C              []byte               `json:"c_scalar,omitempty"`
 	R              []byte               `json:"r_scalar,omitempty"`
 	Output         []byte               `json:"output,omitempty"`
+	Variant        eddsa.Variant        `json:"variant,omitempty"`
+	Context        []byte               `json:"context,omitempty"`
+	NonceStore     bool                 `json:"nonce_store,omitempty"`
+	Mode           Mode                 `json:"mode,omitempty"`
+	Ciphersuite    eddsa.Ciphersuite    `json:"ciphersuite,omitempty"`
+	Coordinator    party.ID             `json:"coordinator,omitempty"`
 }
 
 func (round *Round0) MarshalJSON() ([]byte, error) {
@@ -168,18 +227,22 @@ func (round *Round0) MarshalJSON() ([]byte, error) {
 		D:              d,
 		C:              c,
 		R:              r,
+		NonceStore:     round.Options.NonceStore != nil || round.nonceStoreRequired,
+		Mode:           round.Options.Mode,
+		Ciphersuite:    round.Options.Ciphersuite,
+		Coordinator:    round.Options.Coordinator,
 	}
-	var sigData []byte
-	if round.Output.Signature != nil {
-		sigData = round.Output.Signature.ToEd25519()
-		jsonData.Output = sigData
+	if opts := round.Options.Signature; opts != nil {
+		jsonData.Variant = opts.Variant
+		jsonData.Context = opts.Context
+	}
+	if round.Output != nil && round.Output.Signature != nil {
+		if jsonData.Output, err = round.Output.Signature.MarshalBinary(); err != nil {
+			return nil, err
+		}
 	}
 	data, err := json.Marshal(jsonData)
 
-	fmt.Println("mar--------------------------")
-	fmt.Println(round.SelfID(), len(baseData), len(round.Message), len(round.Parties), len(gk), len(sec), len(e), len(d), len(c), len(r), len(data))
-	fmt.Println("mar end--------------------------")
-
 	return data, err
 }
 
@@ -204,6 +267,7 @@ func (round *Round0) UnmarshalJSON(data []byte) error {
 
 	var out Output
 	if rawJson.Output != nil {
+		out.Signature = new(eddsa.Signature)
 		err = out.Signature.UnmarshalBinary(rawJson.Output)
 		if err != nil {
 			return err
@@ -246,6 +310,16 @@ func (round *Round0) UnmarshalJSON(data []byte) error {
 	round.C = *c
 	round.R = *r
 	round.Output = &out
+	if rawJson.Variant != eddsa.VariantPure || rawJson.Context != nil {
+		round.Options.Signature = &eddsa.Options{
+			Variant: rawJson.Variant,
+			Context: rawJson.Context,
+		}
+	}
+	round.nonceStoreRequired = rawJson.NonceStore
+	round.Options.Mode = rawJson.Mode
+	round.Options.Ciphersuite = rawJson.Ciphersuite
+	round.Options.Coordinator = rawJson.Coordinator
 
 	return err
 }
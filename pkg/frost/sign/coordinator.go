@@ -0,0 +1,154 @@
+package sign
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+// This file implements the coordinator topology of RFC 9591.
+//
+// The coordinator does not hold a secret share, only the eddsa.Public of the group.
+// Instead of broadcasting their messages to each other, the signers only communicate with the coordinator:
+//   - each signer sends its Sign1 commitments (Dᵢ, Eᵢ) to the coordinator,
+//   - the coordinator sends the list of all commitments to each signer with a messages.SignCommitments message,
+//   - each signer sends its Sign2 share zᵢ to the coordinator,
+//   - the coordinator verifies each share and aggregates them into the signature.
+//
+// Each signer therefore sends and receives a constant number of messages, instead of a number linear in the
+// number of signers. The signers do not obtain the signature, it is only set in the Output of the coordinator.
+
+var ErrCommitmentList = errors.New("commitment list is not consistent with the signers")
+
+// NewCoordinatorRound returns the first round of the coordinator of a signing session, see Options.Coordinator.
+// The signers of the session are given by signerIDs, which must not contain the coordinator.
+// The options must be the same as those of the signers, except for the NonceStore which is ignored.
+func NewCoordinatorRound(coordinator party.ID, signerIDs party.IDSlice, shares *eddsa.Public, message []byte, opts *Options) (state.Round, *Output, error) {
+	if err := opts.validate(message); err != nil {
+		return nil, nil, fmt.Errorf("sign.NewCoordinatorRound: %w", err)
+	}
+	if opts != nil && opts.Coordinator != 0 && opts.Coordinator != coordinator {
+		return nil, nil, errors.New("sign.NewCoordinatorRound: options designate another coordinator")
+	}
+	if opts != nil && len(opts.Path) > 0 {
+		var err error
+		if shares, _, err = shares.Derive(opts.Path); err != nil {
+			return nil, nil, fmt.Errorf("sign.NewCoordinatorRound: %w", err)
+		}
+	}
+	if coordinator == 0 || signerIDs.Contains(coordinator) {
+		return nil, nil, errors.New("sign.NewCoordinatorRound: coordinator must be a non zero ID which is not a signer")
+	}
+	if !signerIDs.IsSubsetOf(shares.PartyIDs) {
+		return nil, nil, errors.New("sign.NewCoordinatorRound: not all parties of signerIDs are contained in shares")
+	}
+	if signerIDs.N() <= shares.Threshold {
+		return nil, nil, fmt.Errorf("sign.NewCoordinatorRound: at least %d signers are required", shares.Threshold+1)
+	}
+
+	partyIDs := party.NewIDSlice(append(signerIDs.Copy(), coordinator))
+	baseRound, err := state.NewBaseRound(coordinator, partyIDs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("sign.NewCoordinatorRound: %w", err)
+	}
+
+	round := &Round0{
+		BaseRound: baseRound,
+		Message:   message,
+		Parties:   make(map[party.ID]*signer, signerIDs.N()),
+		GroupKey:  *shares.GroupKey,
+		Output:    &Output{},
+	}
+	if opts != nil {
+		round.Options = *opts
+		round.Options.NonceStore = nil
+	}
+	round.Options.Coordinator = coordinator
+
+	for _, id := range signerIDs {
+		var s signer
+		if id == 0 {
+			return nil, nil, errors.New("sign.NewCoordinatorRound: id 0 is not valid")
+		}
+		lagrange, err := id.Lagrange(signerIDs)
+		if err != nil {
+			return nil, nil, fmt.Errorf("sign.NewCoordinatorRound: %w", err)
+		}
+		s.Public.ScalarMult(lagrange, shares.Shares[id])
+		round.Parties[id] = &s
+	}
+
+	return round, round.Output, nil
+}
+
+// isCoordinator returns true if we are the coordinator of the session.
+func (round *Round0) isCoordinator() bool {
+	return round.Options.Coordinator != 0 && round.Options.Coordinator == round.SelfID()
+}
+
+// signerIDs returns the IDs of the parties which produce a signature share, i.e. all parties except the coordinator.
+func (round *Round0) signerIDs() party.IDSlice {
+	if round.Options.Coordinator == 0 {
+		return round.PartyIDs()
+	}
+	signerIDs := make(party.IDSlice, 0, len(round.PartyIDs()))
+	for _, id := range round.PartyIDs() {
+		if id != round.Options.Coordinator {
+			signerIDs = append(signerIDs, id)
+		}
+	}
+	return signerIDs
+}
+
+// ExpectedSenders implements state.ExpectedSenders.
+// With a coordinator, the signers only receive messages from the coordinator.
+func (round *Round0) ExpectedSenders() party.IDSlice {
+	if round.Options.Coordinator == 0 || round.isCoordinator() {
+		return round.signerIDs()
+	}
+	return party.IDSlice{round.Options.Coordinator}
+}
+
+// commitmentMessages returns the messages.SignCommitments sent by the coordinator to each signer.
+func (round *Round1) commitmentMessages() []*messages.Message {
+	commitments := make(map[party.ID]*messages.Sign1, len(round.Parties))
+	for id, p := range round.Parties {
+		commitments[id] = &messages.Sign1{Di: p.Di, Ei: p.Ei}
+	}
+	msgs := make([]*messages.Message, 0, len(round.Parties))
+	for _, id := range round.signerIDs() {
+		msgs = append(msgs, messages.NewSignCommitments(round.SelfID(), id, commitments))
+	}
+	return msgs
+}
+
+// processCommitments sets the commitments of all signers from the list sent by the coordinator.
+// The list must contain exactly the signers of the session, and our own commitments must not have been modified.
+func (round *Round1) processCommitments(msg *messages.Message) *state.Error {
+	list := msg.SignCommitments
+	if !list.IDs.Equal(round.signerIDs()) {
+		return state.NewError(msg.From, ErrCommitmentList)
+	}
+	identity := ristretto.NewIdentityElement()
+	for k, id := range list.IDs {
+		commitment := &list.Commitments[k]
+		if commitment.Di.Equal(identity) == 1 || commitment.Ei.Equal(identity) == 1 {
+			return state.NewError(msg.From, fmt.Errorf("commitment Ei or Di of party %d was the identity", id))
+		}
+		otherParty := round.Parties[id]
+		if id == round.SelfID() {
+			if commitment.Di.Equal(&otherParty.Di) != 1 || commitment.Ei.Equal(&otherParty.Ei) != 1 {
+				return state.NewError(msg.From, ErrCommitmentList)
+			}
+			continue
+		}
+		otherParty.Di.Set(&commitment.Di)
+		otherParty.Ei.Set(&commitment.Ei)
+	}
+	return nil
+}
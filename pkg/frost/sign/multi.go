@@ -0,0 +1,308 @@
+package sign
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+// This file implements the signing of several messages in a single session.
+//
+// The session runs one instance of the signing protocol for each message, with independent nonces.
+// The messages of all instances are sent together, so that the signers only exchange
+// a single messages.MultiSign1 and a single messages.MultiSign2 message, whatever the number of messages.
+// The signature shares of each message are verified separately.
+
+type (
+	MultiRound0 struct {
+		*state.BaseRound
+
+		// Rounds holds the state of the signing protocol for each message.
+		// They all share the same BaseRound.
+		Rounds []*Round0
+
+		Output *MultiOutput
+	}
+	MultiRound1 struct {
+		*MultiRound0
+	}
+	MultiRound2 struct {
+		*MultiRound1
+	}
+)
+
+// MultiOutput is the output of a session which signs several messages.
+type MultiOutput struct {
+	// Signatures[j] is the signature of the j-th message
+	Signatures []*eddsa.Signature
+}
+
+// NewMultiRound is similar to NewRoundWithOptions, but produces a signature for each of the given messages.
+// The same options apply to all messages. A coordinator is not supported.
+func NewMultiRound(partyIDs party.IDSlice, secret *eddsa.SecretShare, shares *eddsa.Public, msgs [][]byte, opts *Options) (state.Round, *MultiOutput, error) {
+	if len(msgs) == 0 || len(msgs) > math.MaxUint16 {
+		return nil, nil, errors.New("sign.NewMultiRound: invalid number of messages")
+	}
+	if opts != nil && opts.Coordinator != 0 {
+		return nil, nil, errors.New("sign.NewMultiRound: a coordinator is not supported")
+	}
+
+	round := &MultiRound0{
+		Rounds: make([]*Round0, 0, len(msgs)),
+		Output: &MultiOutput{},
+	}
+	for j, message := range msgs {
+		r, _, err := NewRoundWithOptions(partyIDs, secret, shares, message, opts)
+		if err != nil {
+			return nil, nil, fmt.Errorf("sign.NewMultiRound: message %d: %w", j, err)
+		}
+		round.Rounds = append(round.Rounds, r.(*Round0))
+	}
+	round.setBaseRound(round.Rounds[0].BaseRound)
+	return round, round.Output, nil
+}
+
+// setBaseRound sets the BaseRound shared by all rounds, so that they have the same session ID.
+func (round *MultiRound0) setBaseRound(base *state.BaseRound) {
+	round.BaseRound = base
+	for _, r := range round.Rounds {
+		r.BaseRound = base
+	}
+}
+
+// SetNonceStore sets the NonceStore used by the rounds of all messages, and must be called after resuming a State.
+func (round *MultiRound0) SetNonceStore(store NonceStore) {
+	for _, r := range round.Rounds {
+		r.SetNonceStore(store)
+	}
+}
+
+// wrapError adds the index of the message to err, and keeps the culprit.
+func wrapError(j int, err *state.Error) *state.Error {
+	return state.NewError(err.PartyID, fmt.Errorf("message %d: %w", j, err.Unwrap()))
+}
+
+func (round *MultiRound0) ProcessMessage(*messages.Message) *state.Error {
+	return nil
+}
+
+func (round *MultiRound0) GenerateMessages() ([]*messages.Message, *state.Error) {
+	commitments := make([]messages.Sign1, len(round.Rounds))
+	for j, r := range round.Rounds {
+		msgs, err := r.GenerateMessages()
+		if err != nil {
+			return nil, wrapError(j, err)
+		}
+		commitments[j] = *msgs[0].Sign1
+	}
+	return []*messages.Message{messages.NewMultiSign1(round.SelfID(), commitments)}, nil
+}
+
+func (round *MultiRound0) NextRound() state.Round {
+	return &MultiRound1{round}
+}
+
+func (round *MultiRound0) GetOutput() interface{} {
+	return round.Output
+}
+
+func (round *MultiRound0) Reset() {
+	for _, r := range round.Rounds {
+		r.Reset()
+	}
+	round.Output = nil
+}
+
+func (round *MultiRound0) AcceptedMessageTypes() []messages.MessageType {
+	return []messages.MessageType{
+		messages.MessageTypeNone,
+		messages.MessageTypeMultiSign1,
+		messages.MessageTypeMultiSign2,
+	}
+}
+
+func (round *MultiRound1) ProcessMessage(msg *messages.Message) *state.Error {
+	if len(msg.MultiSign1.Commitments) != len(round.Rounds) {
+		return state.NewError(msg.From, errors.New("number of commitments does not match the number of messages"))
+	}
+	for j, r := range round.Rounds {
+		sign1 := &messages.Message{
+			Header: msg.Header,
+			Sign1:  &msg.MultiSign1.Commitments[j],
+		}
+		sign1.Type = messages.MessageTypeSign1
+		if err := (&Round1{r}).ProcessMessage(sign1); err != nil {
+			return wrapError(j, err)
+		}
+	}
+	return nil
+}
+
+func (round *MultiRound1) GenerateMessages() ([]*messages.Message, *state.Error) {
+	shares := make([]ristretto.Scalar, len(round.Rounds))
+	for j, r := range round.Rounds {
+		msgs, err := (&Round1{r}).GenerateMessages()
+		if err != nil {
+			return nil, wrapError(j, err)
+		}
+		shares[j].Set(&msgs[0].Sign2.Zi)
+	}
+	return []*messages.Message{messages.NewMultiSign2(round.SelfID(), shares)}, nil
+}
+
+func (round *MultiRound1) NextRound() state.Round {
+	return &MultiRound2{round}
+}
+
+// ProcessMessage verifies the shares of a single sender, see ProcessMessages.
+func (round *MultiRound2) ProcessMessage(msg *messages.Message) *state.Error {
+	if culprits := round.ProcessMessages([]*messages.Message{msg}); len(culprits) > 0 {
+		return culprits[0]
+	}
+	return nil
+}
+
+// ProcessMessages implements state.BatchProcessor.
+// The shares of each message are verified separately with Round2.ProcessMessages.
+// A sender is blamed for the first message for which its share is invalid.
+func (round *MultiRound2) ProcessMessages(msgs []*messages.Message) []*state.Error {
+	var culprits []*state.Error
+	valid := make([]*messages.Message, 0, len(msgs))
+	for _, msg := range msgs {
+		if len(msg.MultiSign2.Zi) != len(round.Rounds) {
+			culprits = append(culprits, state.NewError(msg.From, errors.New("number of shares does not match the number of messages")))
+			continue
+		}
+		valid = append(valid, msg)
+	}
+
+	blamed := map[party.ID]bool{}
+	for j, r := range round.Rounds {
+		sign2 := make([]*messages.Message, 0, len(valid))
+		for _, msg := range valid {
+			if blamed[msg.From] {
+				continue
+			}
+			m := &messages.Message{
+				Header: msg.Header,
+				Sign2:  &messages.Sign2{Zi: msg.MultiSign2.Zi[j]},
+			}
+			m.Type = messages.MessageTypeSign2
+			sign2 = append(sign2, m)
+		}
+		for _, err := range (&Round2{&Round1{r}}).ProcessMessages(sign2) {
+			blamed[err.PartyID] = true
+			culprits = append(culprits, wrapError(j, err))
+		}
+	}
+	return culprits
+}
+
+func (round *MultiRound2) GenerateMessages() ([]*messages.Message, *state.Error) {
+	signatures := make([]*eddsa.Signature, 0, len(round.Rounds))
+	for j, r := range round.Rounds {
+		if _, err := (&Round2{&Round1{r}}).GenerateMessages(); err != nil {
+			return nil, wrapError(j, err)
+		}
+		signatures = append(signatures, r.Output.Signature)
+	}
+	round.Output.Signatures = signatures
+	return nil, nil
+}
+
+func (round *MultiRound2) NextRound() state.Round {
+	return nil
+}
+
+type multiRound0JSON struct {
+	Base   []byte            `json:"base,omitempty"`
+	Rounds []json.RawMessage `json:"rounds,omitempty"`
+}
+
+func (round *MultiRound0) MarshalJSON() ([]byte, error) {
+	baseData, err := round.BaseRound.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	rounds := make([]json.RawMessage, 0, len(round.Rounds))
+	for _, r := range round.Rounds {
+		data, err := r.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		rounds = append(rounds, data)
+	}
+	return json.Marshal(multiRound0JSON{
+		Base:   baseData,
+		Rounds: rounds,
+	})
+}
+
+func (round *MultiRound0) UnmarshalJSON(data []byte) error {
+	var rawJson multiRound0JSON
+	if err := json.Unmarshal(data, &rawJson); err != nil {
+		return err
+	}
+	if len(rawJson.Rounds) == 0 {
+		return errors.New("sign.MultiRound0: no messages")
+	}
+
+	var base state.BaseRound
+	if err := base.UnmarshalJSON(rawJson.Base); err != nil {
+		return err
+	}
+
+	rounds := make([]*Round0, 0, len(rawJson.Rounds))
+	out := &MultiOutput{}
+	for _, rawRound := range rawJson.Rounds {
+		var r Round0
+		if err := r.UnmarshalJSON(rawRound); err != nil {
+			return err
+		}
+		rounds = append(rounds, &r)
+		if r.Output.Signature != nil {
+			out.Signatures = append(out.Signatures, r.Output.Signature)
+		}
+	}
+	if len(out.Signatures) != len(rounds) {
+		out.Signatures = nil
+	}
+
+	round.Rounds = rounds
+	round.Output = out
+	round.setBaseRound(&base)
+	return nil
+}
+
+func (round *MultiRound1) MarshalJSON() ([]byte, error) {
+	return json.Marshal(round.MultiRound0)
+}
+
+func (round *MultiRound1) UnmarshalJSON(data []byte) error {
+	var r MultiRound0
+	if err := json.Unmarshal(data, &r); err != nil {
+		return err
+	}
+	round.MultiRound0 = &r
+	return nil
+}
+
+func (round *MultiRound2) MarshalJSON() ([]byte, error) {
+	return json.Marshal(round.MultiRound1)
+}
+
+func (round *MultiRound2) UnmarshalJSON(data []byte) error {
+	var r MultiRound1
+	if err := json.Unmarshal(data, &r); err != nil {
+		return err
+	}
+	round.MultiRound1 = &r
+	return nil
+}
@@ -0,0 +1,214 @@
+package sign
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// A signing State contains the secret nonces (dᵢ, eᵢ) from the moment they are generated in Round0,
+// until the signature share is sent in Round1.
+// If a State is suspended in between, and later resumed twice, the same nonces could be used to sign two different messages,
+// which reveals the secret share.
+//
+// A NonceStore prevents this by recording the commitment (Dᵢ, Eᵢ) of every nonce pair used to produce a share.
+// When Options.NonceStore is set, Round1 refuses to produce a share for a commitment which was already consumed.
+// Since the store is not part of the State, it must be set again with SetNonceStore after resuming a State,
+// otherwise Round1 fails with ErrNonceStoreMissing.
+
+var (
+	// ErrNonceReused is returned when producing a signature share with nonces which were already used.
+	ErrNonceReused = errors.New("nonce was already used to produce a signature share")
+
+	// ErrNonceStoreMissing is returned when a State created with a NonceStore was resumed without one.
+	ErrNonceStoreMissing = errors.New("signing round was resumed without its NonceStore")
+)
+
+// NonceStore records the nonce commitments which were used to produce a signature share.
+// Implementations must be safe for concurrent use.
+type NonceStore interface {
+	// Consume marks the commitment as used, and returns ErrNonceReused if it already was.
+	// The commitment must be durably recorded before Consume returns, since the share is sent right after.
+	Consume(commitment []byte) error
+}
+
+// nonceCommitment returns Dᵢ ∥ Eᵢ for our own nonces.
+func (round *Round0) nonceCommitment() []byte {
+	selfParty := round.Parties[round.SelfID()]
+	commitment := make([]byte, 0, 64)
+	commitment = append(commitment, selfParty.Di.Bytes()...)
+	return append(commitment, selfParty.Ei.Bytes()...)
+}
+
+// consumeNonce marks our nonce commitment as used in the NonceStore, if any.
+func (round *Round0) consumeNonce() error {
+	if round.Options.NonceStore == nil {
+		if round.nonceStoreRequired {
+			return ErrNonceStoreMissing
+		}
+		return nil
+	}
+	return round.Options.NonceStore.Consume(round.nonceCommitment())
+}
+
+// SetNonceStore sets the NonceStore used by the round, and must be called after resuming a State.
+func (round *Round0) SetNonceStore(store NonceStore) {
+	round.Options.NonceStore = store
+}
+
+// MemoryNonceStore is a NonceStore which only keeps the consumed commitments in memory.
+// It protects against States resumed multiple times within the same process.
+type MemoryNonceStore struct {
+	used map[string]struct{}
+	mtx  sync.Mutex
+}
+
+// NewMemoryNonceStore returns an empty MemoryNonceStore.
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{
+		used: make(map[string]struct{}),
+	}
+}
+
+// Consume implements NonceStore.
+func (s *MemoryNonceStore) Consume(commitment []byte) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	key := string(commitment)
+	if _, ok := s.used[key]; ok {
+		return ErrNonceReused
+	}
+	s.used[key] = struct{}{}
+	return nil
+}
+
+// FileNonceStore is a NonceStore which appends the consumed commitments to a file, one hex encoded commitment per line.
+// Every commitment is synced to disk before Consume returns.
+//
+// The file may be shared by several processes signing with the same key share.
+// Consume takes an exclusive lock on the file, and reads the commitments appended by others before checking its own.
+// File locks are only supported on Unix systems, and OpenFileNonceStore fails on other platforms.
+type FileNonceStore struct {
+	file *os.File
+	// size is the length of the part of the file which was read into used.
+	size int64
+	used map[string]struct{}
+	mtx  sync.Mutex
+}
+
+// OpenFileNonceStore opens the FileNonceStore at path, or creates it if it does not exist.
+// A line left incomplete by an interrupted write is discarded, since the corresponding share was never sent.
+func OpenFileNonceStore(path string) (*FileNonceStore, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	s := &FileNonceStore{
+		file: file,
+		used: make(map[string]struct{}),
+	}
+	if err = s.lockAndLoad(); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("sign.OpenFileNonceStore: %w", err)
+	}
+	if err = unlockFile(file); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("sign.OpenFileNonceStore: %w", err)
+	}
+	return s, nil
+}
+
+// lockAndLoad locks the file and reads the commitments appended since the last call.
+// If it returns nil, the caller must release the lock.
+func (s *FileNonceStore) lockAndLoad() error {
+	if err := lockFile(s.file); err != nil {
+		return err
+	}
+	if err := s.load(); err != nil {
+		_ = unlockFile(s.file)
+		return err
+	}
+	return nil
+}
+
+// load reads the lines written after s.size, and must be called with the file locked.
+func (s *FileNonceStore) load() error {
+	if _, err := s.file.Seek(s.size, io.SeekStart); err != nil {
+		return err
+	}
+	reader := bufio.NewReader(s.file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		commitment, err := hex.DecodeString(string(bytes.TrimSuffix(line, []byte{'\n'})))
+		if err != nil {
+			return fmt.Errorf("line %d: %w", len(s.used)+1, err)
+		}
+		s.used[string(commitment)] = struct{}{}
+		s.size += int64(len(line))
+	}
+
+	// Since writers hold the lock, an incomplete line can only be left by a process which was interrupted.
+	return s.truncate()
+}
+
+// truncate removes anything written after the last complete line.
+func (s *FileNonceStore) truncate() error {
+	if err := s.file.Truncate(s.size); err != nil {
+		return err
+	}
+	_, err := s.file.Seek(s.size, io.SeekStart)
+	return err
+}
+
+// Consume implements NonceStore.
+func (s *FileNonceStore) Consume(commitment []byte) (err error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if err = s.lockAndLoad(); err != nil {
+		return fmt.Errorf("sign.FileNonceStore: %w", err)
+	}
+	defer func() {
+		if unlockErr := unlockFile(s.file); unlockErr != nil && err == nil {
+			err = fmt.Errorf("sign.FileNonceStore: %w", unlockErr)
+		}
+	}()
+
+	key := string(commitment)
+	if _, ok := s.used[key]; ok {
+		return ErrNonceReused
+	}
+	line := make([]byte, hex.EncodedLen(len(commitment))+1)
+	hex.Encode(line, commitment)
+	line[len(line)-1] = '\n'
+	_, err = s.file.Write(line)
+	if err == nil {
+		err = s.file.Sync()
+	}
+	if err != nil {
+		_ = s.truncate()
+		return fmt.Errorf("sign.FileNonceStore: %w", err)
+	}
+	s.size += int64(len(line))
+	s.used[key] = struct{}{}
+	return nil
+}
+
+// Close closes the underlying file.
+func (s *FileNonceStore) Close() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.file.Close()
+}
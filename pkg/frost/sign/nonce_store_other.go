@@ -0,0 +1,20 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package sign
+
+import (
+	"errors"
+	"os"
+)
+
+// Without file locks, concurrent processes could consume the same nonce, so the FileNonceStore is not available.
+var errNoFileLock = errors.New("file locking is not supported on this platform")
+
+func lockFile(*os.File) error {
+	return errNoFileLock
+}
+
+func unlockFile(*os.File) error {
+	return errNoFileLock
+}
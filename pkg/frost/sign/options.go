@@ -0,0 +1,71 @@
+package sign
+
+import (
+	"errors"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+)
+
+// Options configures a signing session.
+// A nil *Options is equivalent to the zero value, which produces a PureEdDSA signature.
+type Options struct {
+	// Signature selects the Ed25519 variant of the resulting signature, and its context string.
+	// All signers must use the same value.
+	Signature *eddsa.Options
+
+	// Mode selects how the nonces and binding factors are computed, see ModeRFC9591.
+	// All signers must use the same value.
+	Mode Mode
+
+	// Ciphersuite selects the ciphersuite of RFC 9591 used in ModeRFC9591, FROST(Ed25519, SHA-512) by default.
+	// Other ciphersuites are only supported in ModeRFC9591. All signers must use the same value.
+	Ciphersuite eddsa.Ciphersuite
+
+	// Path is the derivation path of the child key used for signing.
+	// If it is empty, the group key itself is used.
+	Path eddsa.DerivationPath
+
+	// Coordinator is the ID of the party which collects the commitments and signature shares of the signers,
+	// see NewCoordinatorRound. If it is 0, the signers broadcast their messages and each of them obtains the signature.
+	// Otherwise, the signers only send messages to the coordinator, and only the coordinator obtains the signature.
+	Coordinator party.ID
+
+	// NonceStore records the nonces used to produce signature shares, so that a resumed State cannot reuse them.
+	// If it is nil, nonce reuse is not checked.
+	NonceStore NonceStore
+}
+
+// signature returns the eddsa.Options of the session, which may be nil.
+func (o *Options) signature() *eddsa.Options {
+	if o == nil {
+		return nil
+	}
+	return o.Signature
+}
+
+// validate returns an error if the options are not consistent, or cannot be used to sign message.
+func (o *Options) validate(message []byte) error {
+	if err := o.signature().Validate(message); err != nil {
+		return err
+	}
+	if o == nil {
+		return nil
+	}
+	if err := o.Ciphersuite.Validate(); err != nil {
+		return err
+	}
+	switch o.Mode {
+	case ModeLegacy:
+		if o.Ciphersuite != eddsa.CiphersuiteEd25519 {
+			return errors.New("sign.Options: the ciphersuite requires RFC 9591 mode")
+		}
+	case ModeRFC9591:
+		if o.Signature != nil && o.Signature.Variant != eddsa.VariantPure {
+			return errors.New("sign.Options: RFC 9591 mode only supports PureEdDSA")
+		}
+	default:
+		return errors.New("sign.Options: unknown mode")
+	}
+	return nil
+}
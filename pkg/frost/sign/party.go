@@ -2,7 +2,6 @@ package sign
 
 import (
 	"encoding/json"
-	"fmt"
 	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
 )
 
@@ -60,9 +59,6 @@ func (signer *signer) MarshalJSON() ([]byte, error) {
 
 	//rInital := signer.Ri.PointInited()
 
-	b1 := signer.Ri.Bytes()
-	fmt.Println(len(b1), b1, signer.Ri.CheckPointInited())
-
 	rawjson := signerJSON{
 		Public: signer.Public.Bytes(),
 		Di:     signer.Di.Bytes(),
@@ -0,0 +1,366 @@
+package sign
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/scalar"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+// This file implements the preprocessing stage of FROST.
+//
+// Each signer Pᵢ samples a batch of nonce pairs (dᵢⱼ, eᵢⱼ) ahead of time,
+// and publishes the commitments (Dᵢⱼ, Eᵢⱼ) = ([dᵢⱼ]•B, [eᵢⱼ]•B) with a messages.Preprocess message.
+// Every party stores the published commitments in a Commitments store.
+//
+// A signing session then only requires a single round, in which each signer consumes one
+// pre-published commitment pair and broadcasts its share of the signature.
+// Once a commitment has been consumed, it is deleted from both the Nonces and the Commitments,
+// so that it can never be used in a second signature.
+
+var (
+	ErrCommitmentUnknown = errors.New("commitment was not published or was already used")
+	ErrNonceUnknown      = errors.New("nonce was not generated or was already used")
+)
+
+// noncePair holds the secret nonces for one pre-published commitment.
+type noncePair struct {
+	d, e ristretto.Scalar
+}
+
+// Nonces holds the secret nonce pairs (dᵢⱼ, eᵢⱼ) generated by a party during preprocessing.
+type Nonces struct {
+	id    party.ID
+	next  uint32
+	pairs map[uint32]*noncePair
+	mtx   sync.Mutex
+}
+
+// NewNonces returns an empty set of nonces for the party with the given ID.
+func NewNonces(id party.ID) *Nonces {
+	return &Nonces{
+		id:    id,
+		pairs: make(map[uint32]*noncePair),
+	}
+}
+
+// ID returns the ID of the party owning the nonces.
+func (n *Nonces) ID() party.ID {
+	return n.id
+}
+
+// Generate samples a new batch of count nonce pairs, and returns a messages.Preprocess message
+// containing the associated commitments. The message should be broadcast to all other parties,
+// and also be added to the party's own Commitments.
+func (n *Nonces) Generate(count int) (*messages.Message, error) {
+	if count <= 0 {
+		return nil, errors.New("sign.Nonces: count must be positive")
+	}
+
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	if uint64(n.next)+uint64(count) > uint64(^uint32(0)) {
+		return nil, errors.New("sign.Nonces: too many nonces generated")
+	}
+
+	start := n.next
+	D := make([]ristretto.Element, count)
+	E := make([]ristretto.Element, count)
+	for j := 0; j < count; j++ {
+		var pair noncePair
+		// Dᵢⱼ = [dᵢⱼ] B
+		scalar.SetScalarRandom(&pair.d)
+		D[j].ScalarBaseMult(&pair.d)
+		// Eᵢⱼ = [eᵢⱼ] B
+		scalar.SetScalarRandom(&pair.e)
+		E[j].ScalarBaseMult(&pair.e)
+		n.pairs[start+uint32(j)] = &pair
+	}
+	n.next += uint32(count)
+
+	return messages.NewPreprocess(n.id, start, D, E), nil
+}
+
+// Remaining returns the number of unused nonce pairs.
+func (n *Nonces) Remaining() int {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	return len(n.pairs)
+}
+
+// take removes the nonce pair at index from the set and copies it into d and e.
+func (n *Nonces) take(index uint32, d, e *ristretto.Scalar) error {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	pair, ok := n.pairs[index]
+	if !ok {
+		return fmt.Errorf("index %d: %w", index, ErrNonceUnknown)
+	}
+	delete(n.pairs, index)
+
+	d.Set(&pair.d)
+	e.Set(&pair.e)
+
+	zero := ristretto.NewScalar()
+	pair.d.Set(zero)
+	pair.e.Set(zero)
+	return nil
+}
+
+// Commitments stores the commitments published by all parties during preprocessing.
+// It is safe for concurrent use.
+type Commitments struct {
+	lists map[party.ID]map[uint32]*messages.Sign1
+	mtx   sync.Mutex
+}
+
+// NewCommitments returns an empty store of commitments.
+func NewCommitments() *Commitments {
+	return &Commitments{
+		lists: make(map[party.ID]map[uint32]*messages.Sign1),
+	}
+}
+
+// Add stores all commitments contained in a messages.Preprocess message.
+// It returns an error if one of the commitments is the identity, or if it would replace
+// a commitment that was already published.
+func (c *Commitments) Add(msg *messages.Message) error {
+	if msg.Type != messages.MessageTypePreprocess || msg.Preprocess == nil {
+		return errors.New("sign.Commitments: message does not contain commitments")
+	}
+	from := msg.From
+	start := msg.Preprocess.Start
+	identity := ristretto.NewIdentityElement()
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	list, ok := c.lists[from]
+	if !ok {
+		list = make(map[uint32]*messages.Sign1, len(msg.Preprocess.Commitments))
+	}
+	for j := range msg.Preprocess.Commitments {
+		commitment := msg.Preprocess.Commitments[j]
+		index := start + uint32(j)
+		if commitment.Di.Equal(identity) == 1 || commitment.Ei.Equal(identity) == 1 {
+			return fmt.Errorf("sign.Commitments: party %d: index %d: commitment Ei or Di was the identity", from, index)
+		}
+		if _, exists := list[index]; exists {
+			return fmt.Errorf("sign.Commitments: party %d: index %d: commitment was already published", from, index)
+		}
+	}
+	for j := range msg.Preprocess.Commitments {
+		commitment := msg.Preprocess.Commitments[j]
+		list[start+uint32(j)] = &commitment
+	}
+	c.lists[from] = list
+	return nil
+}
+
+// Next returns the smallest index of an unused commitment published by the party id.
+// The second return value is false if there are no such commitments left.
+func (c *Commitments) Next(id party.ID) (uint32, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	var (
+		next  uint32
+		found bool
+	)
+	for index := range c.lists[id] {
+		if !found || index < next {
+			next = index
+			found = true
+		}
+	}
+	return next, found
+}
+
+// NextIndices returns the smallest unused index for all parties in partyIDs.
+// It can be used to select the commitments of a session, when all signers are known to have consumed
+// the same commitments in previous sessions.
+func (c *Commitments) NextIndices(partyIDs party.IDSlice) (map[party.ID]uint32, error) {
+	indices := make(map[party.ID]uint32, len(partyIDs))
+	for _, id := range partyIDs {
+		index, ok := c.Next(id)
+		if !ok {
+			return nil, fmt.Errorf("sign.Commitments: party %d has no commitments left", id)
+		}
+		indices[id] = index
+	}
+	return indices, nil
+}
+
+// Remaining returns the number of unused commitments published by the party id.
+func (c *Commitments) Remaining(id party.ID) int {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return len(c.lists[id])
+}
+
+// take removes the commitments for all parties at the given indices.
+// Either all commitments are removed, or none are if one of them is missing.
+func (c *Commitments) take(indices map[party.ID]uint32) (map[party.ID]*messages.Sign1, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for id, index := range indices {
+		if _, ok := c.lists[id][index]; !ok {
+			return nil, fmt.Errorf("party %d: index %d: %w", id, index, ErrCommitmentUnknown)
+		}
+	}
+
+	commitments := make(map[party.ID]*messages.Sign1, len(indices))
+	for id, index := range indices {
+		commitments[id] = c.lists[id][index]
+		delete(c.lists[id], index)
+	}
+	return commitments, nil
+}
+
+// restore puts back the commitments removed by take, when the session could not be created.
+func (c *Commitments) restore(indices map[party.ID]uint32, commitments map[party.ID]*messages.Sign1) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for id, index := range indices {
+		c.lists[id][index] = commitments[id]
+	}
+}
+
+// PreprocessedRound0 is the first round of a signing session which uses pre-published commitments.
+// Since all commitments are already known, the parties directly send their signature share,
+// and the session only requires a single round of communication.
+type PreprocessedRound0 struct {
+	*Round0
+}
+
+// NewPreprocessedRound is similar to NewRoundWithOptions, but uses the commitments published during preprocessing.
+// For each signer, indices contains the index of the commitment pair used for this session.
+// All signers must use the same indices.
+//
+// The commitments at the given indices are removed from both nonces and commitments,
+// even if the session is later aborted.
+// If opts.Coordinator is set, the signature share is only sent to the coordinator, see NewPreprocessedCoordinatorRound.
+func NewPreprocessedRound(partyIDs party.IDSlice, secret *eddsa.SecretShare, shares *eddsa.Public, message []byte, opts *Options, nonces *Nonces, commitments *Commitments, indices map[party.ID]uint32) (state.Round, *Output, error) {
+	if nonces.ID() != secret.ID {
+		return nil, nil, errors.New("sign.NewPreprocessedRound: nonces do not belong to the owner of SecretShare")
+	}
+	if err := checkIndices(partyIDs, indices); err != nil {
+		return nil, nil, fmt.Errorf("sign.NewPreprocessedRound: %w", err)
+	}
+
+	r, output, err := NewRoundWithOptions(partyIDs, secret, shares, message, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	round := r.(*Round0)
+
+	used, err := commitments.take(indices)
+	if err != nil {
+		return nil, nil, fmt.Errorf("sign.NewPreprocessedRound: %w", err)
+	}
+	if err = nonces.take(indices[round.SelfID()], &round.d, &round.e); err != nil {
+		// Nothing was consumed, so the commitments can still be used for another session
+		commitments.restore(indices, used)
+		return nil, nil, fmt.Errorf("sign.NewPreprocessedRound: %w", err)
+	}
+	round.setCommitments(used)
+
+	// Make sure our own nonces correspond to the commitments the other parties will use
+	selfParty := round.Parties[round.SelfID()]
+	var D, E ristretto.Element
+	D.ScalarBaseMult(&round.d)
+	E.ScalarBaseMult(&round.e)
+	if D.Equal(&selfParty.Di) != 1 || E.Equal(&selfParty.Ei) != 1 {
+		round.Reset()
+		return nil, nil, errors.New("sign.NewPreprocessedRound: published commitment does not match our nonces")
+	}
+
+	return &PreprocessedRound0{round}, output, nil
+}
+
+// NewPreprocessedCoordinatorRound is similar to NewCoordinatorRound, but uses the commitments published during preprocessing.
+// The signers must create their round with NewPreprocessedRound, with opts.Coordinator set to coordinator,
+// and the same indices. The commitments at the given indices are removed from commitments.
+func NewPreprocessedCoordinatorRound(coordinator party.ID, signerIDs party.IDSlice, shares *eddsa.Public, message []byte, opts *Options, commitments *Commitments, indices map[party.ID]uint32) (state.Round, *Output, error) {
+	if err := checkIndices(signerIDs, indices); err != nil {
+		return nil, nil, fmt.Errorf("sign.NewPreprocessedCoordinatorRound: %w", err)
+	}
+
+	r, output, err := NewCoordinatorRound(coordinator, signerIDs, shares, message, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	round := r.(*Round0)
+
+	used, err := commitments.take(indices)
+	if err != nil {
+		return nil, nil, fmt.Errorf("sign.NewPreprocessedCoordinatorRound: %w", err)
+	}
+	round.setCommitments(used)
+
+	return &PreprocessedRound0{round}, output, nil
+}
+
+// checkIndices verifies that indices contains exactly one index for every signer.
+func checkIndices(signerIDs party.IDSlice, indices map[party.ID]uint32) error {
+	if len(indices) != len(signerIDs) {
+		return errors.New("indices must contain an index for every signer")
+	}
+	for _, id := range signerIDs {
+		if _, ok := indices[id]; !ok {
+			return fmt.Errorf("no index for party %d", id)
+		}
+	}
+	return nil
+}
+
+// setCommitments sets the commitments of all signers from the ones consumed for this session.
+func (round *Round0) setCommitments(commitments map[party.ID]*messages.Sign1) {
+	for id, commitment := range commitments {
+		p := round.Parties[id]
+		p.Di.Set(&commitment.Di)
+		p.Ei.Set(&commitment.Ei)
+	}
+}
+
+func (round *PreprocessedRound0) UnmarshalJSON(data []byte) error {
+	var Round0 Round0
+	err := json.Unmarshal(data, &Round0)
+	if err != nil {
+		return err
+	}
+	round.Round0 = &Round0
+	return nil
+}
+
+func (round *PreprocessedRound0) AcceptedMessageTypes() []messages.MessageType {
+	return []messages.MessageType{
+		messages.MessageTypeNone,
+		messages.MessageTypeSign2,
+	}
+}
+
+// GenerateMessages skips the commitment round and directly computes the signature share.
+// The coordinator does not need to send the commitments, since the signers already know them.
+func (round *PreprocessedRound0) GenerateMessages() ([]*messages.Message, *state.Error) {
+	msgs, err := (&Round1{round.Round0}).GenerateMessages()
+	if round.isCoordinator() {
+		return nil, err
+	}
+	return msgs, err
+}
+
+func (round *PreprocessedRound0) NextRound() state.Round {
+	return (&Round1{round.Round0}).NextRound()
+}
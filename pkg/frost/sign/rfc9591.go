@@ -0,0 +1,90 @@
+package sign
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+)
+
+// This file implements the FROST ciphersuites of RFC 9591, see eddsa.Ciphersuite.
+//
+// The signing protocol itself is the same as in the legacy mode, only the nonces, binding factors and challenge
+// are computed differently. For FROST(Ed25519, SHA-512), the challenge is the same as in the legacy mode.
+
+// Mode selects how the nonces and binding factors of a signing session are computed.
+// All signers must use the same Mode.
+type Mode uint8
+
+const (
+	// ModeLegacy is the original scheme of this library.
+	// The nonces are sampled uniformly at random, and the binding factors include the session ID.
+	ModeLegacy Mode = iota
+
+	// ModeRFC9591 follows the ciphersuite of RFC 9591 selected by Options.Ciphersuite,
+	// so that signing sessions can be cross-checked with other implementations.
+	// The nonces are hedged with the secret share, and the session ID is not bound to the binding factors.
+	// Only PureEdDSA signatures are supported.
+	ModeRFC9591
+)
+
+// nonceGenerate sets s to H3(random_bytes ∥ secret), where random_bytes are 32 bytes read from random.
+func nonceGenerate(s *ristretto.Scalar, cs eddsa.Ciphersuite, random io.Reader, secret *ristretto.Scalar) error {
+	randomBytes := make([]byte, 32)
+	if _, err := io.ReadFull(random, randomBytes); err != nil {
+		return fmt.Errorf("sign: failed to generate nonce: %w", err)
+	}
+	s.Set(cs.H3(randomBytes, secret.Bytes()))
+	return nil
+}
+
+// generateNonces sets d and e as in commit() of RFC 9591.
+// nonce_generate requires the secret share sᵢ, which we recover from the normalized share 𝛌ᵢ • sᵢ.
+func (round *Round0) generateNonces() error {
+	lagrange, err := round.SelfID().Lagrange(round.signerIDs())
+	if err != nil {
+		return err
+	}
+	var secret ristretto.Scalar
+	secret.Invert(lagrange)
+	secret.Multiply(&secret, &round.SecretKeyShare)
+	defer secret.Set(ristretto.NewScalar())
+
+	random := round.random
+	if random == nil {
+		random = rand.Reader
+	}
+	cs := round.Options.Ciphersuite
+	if err = nonceGenerate(&round.d, cs, random, &secret); err != nil {
+		return err
+	}
+	return nonceGenerate(&round.e, cs, random, &secret)
+}
+
+// computeBindingFactors computes the binding factor 𝜌ᵢ of each signer as in compute_binding_factors of RFC 9591:
+//
+//	𝜌ᵢ = H1(GroupKey ∥ H4(Message) ∥ H5(B) ∥ i)
+//
+// The list B is the concatenation of ( j ∥ Dⱼ ∥ Eⱼ ) for all signers j in sorted order.
+// The IDs are encoded as scalars, and the elements as defined by the ciphersuite.
+func (round *Round1) computeBindingFactors() {
+	cs := round.Options.Ciphersuite
+	commitmentList := make([]byte, 0, int(round.signerIDs().N())*(32+32+32))
+	for _, id := range round.signerIDs() {
+		otherParty := round.Parties[id]
+		commitmentList = append(commitmentList, id.Scalar().Bytes()...)
+		commitmentList = append(commitmentList, cs.EncodeElement(&otherParty.Di)...)
+		commitmentList = append(commitmentList, cs.EncodeElement(&otherParty.Ei)...)
+	}
+
+	prefix := make([]byte, 0, 32+64+64)
+	prefix = append(prefix, cs.EncodePublicKey(&round.GroupKey)...)
+	prefix = append(prefix, cs.H4(round.Message)...)
+	prefix = append(prefix, cs.H5(commitmentList)...)
+
+	for _, id := range round.signerIDs() {
+		round.Parties[id].Pi.Set(cs.H1(prefix, id.Scalar().Bytes()))
+	}
+}
@@ -0,0 +1,261 @@
+package sign
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"testing"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+)
+
+type rfc9591Signer struct {
+	hidingRandomness, bindingRandomness string
+	hidingNonce, bindingNonce           string
+	hidingCommitment, bindingCommitment string
+	bindingFactor                       string
+	sigShare                            string
+}
+
+// rfc9591Vector is a test vector of RFC 9591, Appendix E, for a threshold of 2 out of 3, where parties 1 and 3 sign.
+type rfc9591Vector struct {
+	ciphersuite    eddsa.Ciphersuite
+	groupSecretKey string
+	groupPublicKey string
+	message        string
+	coefficient    string
+	shares         map[party.ID]string
+	signers        map[party.ID]rfc9591Signer
+	signature      string
+}
+
+var rfc9591Vectors = map[string]rfc9591Vector{
+	"FROST(Ed25519, SHA-512)": {
+		ciphersuite:    eddsa.CiphersuiteEd25519,
+		groupSecretKey: "7b1c33d3f5291d85de664833beb1ad469f7fb6025a0ec78b3a790c6e13a98304",
+		groupPublicKey: "15d21ccd7ee42959562fc8aa63224c8851fb3ec85a3faf66040d380fb9738673",
+		message:        "74657374",
+		coefficient:    "178199860edd8c62f5212ee91eff1295d0d670ab4ed4506866bae57e7030b204",
+		shares: map[party.ID]string{
+			1: "929dcc590407aae7d388761cddb0c0db6f5627aea8e217f4a033f2ec83d93509",
+			2: "a91e66e012e4364ac9aaa405fcafd370402d9859f7b6685c07eed76bf409e80d",
+			3: "d3cb090a075eb154e82fdb4b3cb507f110040905468bb9c46da8bdea643a9a02",
+		},
+		signers: map[party.ID]rfc9591Signer{
+			1: {
+				hidingRandomness:  "0fd2e39e111cdc266f6c0f4d0fd45c947761f1f5d3cb583dfcb9bbaf8d4c9fec",
+				bindingRandomness: "69cd85f631d5f7f2721ed5e40519b1366f340a87c2f6856363dbdcda348a7501",
+				hidingNonce:       "812d6104142944d5a55924de6d49940956206909f2acaeedecda2b726e630407",
+				bindingNonce:      "b1110165fc2334149750b28dd813a39244f315cff14d4e89e6142f262ed83301",
+				hidingCommitment:  "b5aa8ab305882a6fc69cbee9327e5a45e54c08af61ae77cb8207be3d2ce13de3",
+				bindingCommitment: "67e98ab55aa310c3120418e5050c9cf76cf387cb20ac9e4b6fdb6f82a469f932",
+				bindingFactor:     "f2cb9d7dd9beff688da6fcc83fa89046b3479417f47f55600b106760eb3b5603",
+				sigShare:          "001719ab5a53ee1a12095cd088fd149702c0720ce5fd2f29dbecf24b7281b603",
+			},
+			3: {
+				hidingRandomness:  "86d64a260059e495d0fb4fcc17ea3da7452391baa494d4b00321098ed2a0062f",
+				bindingRandomness: "13e6b25afb2eba51716a9a7d44130c0dbae0004a9ef8d7b5550c8a0e07c61775",
+				hidingNonce:       "c256de65476204095ebdc01bd11dc10e57b36bc96284595b8215222374f99c0e",
+				bindingNonce:      "243d71944d929063bc51205714ae3c2218bd3451d0214dfb5aeec2a90c35180d",
+				hidingCommitment:  "cfbdb165bd8aad6eb79deb8d287bcc0ab6658ae57fdcc98ed12c0669e90aec91",
+				bindingCommitment: "7487bc41a6e712eea2f2af24681b58b1cf1da278ea11fe4e8b78398965f13552",
+				bindingFactor:     "b087686bf35a13f3dc78e780a34b0fe8a77fef1b9938c563f5573d71d8d7890f",
+				sigShare:          "bd86125de990acc5e1f13781d8e32c03a9bbd4c53539bbc106058bfd14326007",
+			},
+		},
+		signature: "36282629c383bb820a88b71cae937d41f2f2adfcc3d02e55507e2fb9e2dd3cbebd9d2b0844e49ae0f3fa935161e1419aab7b47d21a37ebeae1f17d4987b3160b",
+	},
+	"FROST(ristretto255, SHA-512)": {
+		ciphersuite:    eddsa.CiphersuiteRistretto255,
+		groupSecretKey: "1b25a55e463cfd15cf14a5d3acc3d15053f08da49c8afcf3ab265f2ebc4f970b",
+		groupPublicKey: "e2a62f39eede11269e3bd5a7d97554f5ca384f9f6d3dd9c3c0d05083c7254f57",
+		message:        "74657374",
+		coefficient:    "410f8b744b19325891d73736923525a4f596c805d060dfb9c98009d34e3fec02",
+		shares: map[party.ID]string{
+			1: "5c3430d391552f6e60ecdc093ff9f6f4488756aa6cebdbad75a768010b8f830e",
+			2: "b06fc5eac20b4f6e1b271d9df2343d843e1e1fb03c4cbb673f2872d459ce6f01",
+			3: "f17e505f0e2581c6acfe54d3846a622834b5e7b50cad9a2109a97ba7a80d5c04",
+		},
+		signers: map[party.ID]rfc9591Signer{
+			1: {
+				hidingRandomness:  "f595a133b4d95c6e1f79887220c8b275ce6277e7f68a6640e1e7140f9be2fb5c",
+				bindingRandomness: "34dd1001360e3513cb37bebfabe7be4a32c5bb91ba19fbd4360d039111f0fbdc",
+				hidingNonce:       "214f2cabb86ed71427ea7ad4283b0fae26b6746c801ce824b83ceb2b99278c03",
+				bindingNonce:      "c9b8f5e16770d15603f744f8694c44e335e8faef00dad182b8d7a34a62552f0c",
+				hidingCommitment:  "965def4d0958398391fc06d8c2d72932608b1e6255226de4fb8d972dac15fd57",
+				bindingCommitment: "ec5170920660820007ae9e1d363936659ef622f99879898db86e5bf1d5bf2a14",
+				bindingFactor:     "8967fd70fa06a58e5912603317fa94c77626395a695a0e4e4efc4476662eba0c",
+				sigShare:          "9285f875923ce7e0c491a592e9ea1865ec1b823ead4854b48c8a46287749ee09",
+			},
+			3: {
+				hidingRandomness:  "daa0cf42a32617786d390e0c7edfbf2efbd428037069357b5173ae61d6dd5d5e",
+				bindingRandomness: "b4387e72b2e4108ce4168931cc2c7fcce5f345a5297368952c18b5fc8473f050",
+				hidingNonce:       "3f7927872b0f9051dd98dd73eb2b91494173bbe0feb65a3e7e58d3e2318fa40f",
+				bindingNonce:      "ffd79445fb8030f0a3ddd3861aa4b42b618759282bfe24f1f9304c7009728305",
+				hidingCommitment:  "480e06e3de182bf83489c45d7441879932fd7b434a26af41455756264fbd5d6e",
+				bindingCommitment: "3064746dfd3c1862ef58fc68c706da287dd925066865ceacc816b3a28c7b363b",
+				bindingFactor:     "f2c1bb7c33a10511158c2f1766a4a5fadf9f86f2a92692ed333128277cc31006",
+				sigShare:          "7cb211fe0e3d59d25db6e36b3fb32344794139602a7b24f1ae0dc4e26ad7b908",
+			},
+		},
+		signature: "fc45655fbc66bbffad654ea4ce5fdae253a49a64ace25d9adb62010dd9fb25552164141787162e5b4cab915b4aa45d94655dbb9ed7c378a53b980a0be220a802",
+	},
+}
+
+func fromHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func scalarFromHex(t *testing.T, s string) *ristretto.Scalar {
+	var x ristretto.Scalar
+	if _, err := x.SetCanonicalBytes(fromHex(t, s)); err != nil {
+		t.Fatal(err)
+	}
+	return &x
+}
+
+func TestRFC9591Vectors(t *testing.T) {
+	for name, v := range rfc9591Vectors {
+		t.Run(name, func(t *testing.T) {
+			testRFC9591Vector(t, v)
+		})
+	}
+}
+
+func testRFC9591Vector(t *testing.T, v rfc9591Vector) {
+	cs := v.ciphersuite
+
+	// The shares lie on the polynomial f(X) = s + a₁ • X
+	groupSecret := scalarFromHex(t, v.groupSecretKey)
+	coefficient := scalarFromHex(t, v.coefficient)
+	publicShares := make(map[party.ID]*ristretto.Element, len(v.shares))
+	for id, share := range v.shares {
+		var expected ristretto.Scalar
+		expected.MultiplyAdd(coefficient, id.Scalar(), groupSecret)
+		if expected.Equal(scalarFromHex(t, share)) != 1 {
+			t.Fatalf("share of party %d does not match the polynomial", id)
+		}
+		var public ristretto.Element
+		publicShares[id] = public.ScalarBaseMult(&expected)
+	}
+	public, err := eddsa.NewPublic(publicShares, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(cs.EncodePublicKey(public.GroupKey)) != v.groupPublicKey {
+		t.Fatal("group public key differs")
+	}
+
+	signIDs := party.IDSlice{1, 3}
+	message := fromHex(t, v.message)
+	rounds := make(map[party.ID]*Round0, len(signIDs))
+	for _, id := range signIDs {
+		secret := eddsa.NewSecretShare(id, scalarFromHex(t, v.shares[id]))
+		r, _, err := NewRoundWithOptions(signIDs, secret, public, message, &Options{Mode: ModeRFC9591, Ciphersuite: cs})
+		if err != nil {
+			t.Fatal(err)
+		}
+		round := r.(*Round0)
+		signer := v.signers[id]
+		round.random = bytes.NewReader(append(fromHex(t, signer.hidingRandomness), fromHex(t, signer.bindingRandomness)...))
+		rounds[id] = round
+	}
+
+	run := func(name string, generate func(id party.ID) ([]*messages.Message, error), process func(id party.ID, msg *messages.Message) error) {
+		var msgs []*messages.Message
+		for _, id := range signIDs {
+			out, err := generate(id)
+			if err != nil {
+				t.Fatalf("%s: party %d: %v", name, id, err)
+			}
+			msgs = append(msgs, out...)
+		}
+		for _, msg := range msgs {
+			for _, id := range signIDs {
+				if id == msg.From {
+					continue
+				}
+				if err := process(id, msg); err != nil {
+					t.Fatalf("%s: party %d: %v", name, id, err)
+				}
+			}
+		}
+	}
+
+	// Round one: nonces and commitments
+	run("round 1", func(id party.ID) ([]*messages.Message, error) {
+		msgs, err := rounds[id].GenerateMessages()
+		if err != nil {
+			return nil, err
+		}
+		return msgs, nil
+	}, func(id party.ID, msg *messages.Message) error {
+		if err := (&Round1{rounds[id]}).ProcessMessage(msg); err != nil {
+			return err
+		}
+		return nil
+	})
+	for _, id := range signIDs {
+		round, signer := rounds[id], v.signers[id]
+		if hex.EncodeToString(round.d.Bytes()) != signer.hidingNonce || hex.EncodeToString(round.e.Bytes()) != signer.bindingNonce {
+			t.Errorf("party %d: nonces differ", id)
+		}
+		for _, otherID := range signIDs {
+			p := rounds[otherID].Parties[id]
+			if hex.EncodeToString(cs.EncodeElement(&p.Di)) != signer.hidingCommitment || hex.EncodeToString(cs.EncodeElement(&p.Ei)) != signer.bindingCommitment {
+				t.Errorf("party %d: commitments of party %d differ", otherID, id)
+			}
+		}
+	}
+
+	// Round two: binding factors and signature shares
+	run("round 2", func(id party.ID) ([]*messages.Message, error) {
+		msgs, err := (&Round1{rounds[id]}).GenerateMessages()
+		if err != nil {
+			return nil, err
+		}
+		return msgs, nil
+	}, func(id party.ID, msg *messages.Message) error {
+		if err := (&Round2{&Round1{rounds[id]}}).ProcessMessage(msg); err != nil {
+			return err
+		}
+		return nil
+	})
+	for _, id := range signIDs {
+		signer := v.signers[id]
+		for _, otherID := range signIDs {
+			p := rounds[otherID].Parties[id]
+			if hex.EncodeToString(p.Pi.Bytes()) != signer.bindingFactor {
+				t.Errorf("party %d: binding factor of party %d differs", otherID, id)
+			}
+			if hex.EncodeToString(p.Zi.Bytes()) != signer.sigShare {
+				t.Errorf("party %d: signature share of party %d differs", otherID, id)
+			}
+		}
+	}
+
+	// Aggregation
+	for _, id := range signIDs {
+		round := rounds[id]
+		if _, err := (&Round2{&Round1{round}}).GenerateMessages(); err != nil {
+			t.Fatal(err)
+		}
+		sig := round.Output.Signature
+		if hex.EncodeToString(cs.EncodeSignature(sig)) != v.signature {
+			t.Errorf("party %d: signature differs", id)
+		}
+		if !cs.Verify(public.GroupKey, message, sig) {
+			t.Errorf("party %d: signature failed to verify", id)
+		}
+		if cs == eddsa.CiphersuiteEd25519 && !ed25519.Verify(public.GroupKey.ToEd25519(), message, sig.ToEd25519()) {
+			t.Errorf("party %d: signature failed to verify with crypto/ed25519", id)
+		}
+	}
+}
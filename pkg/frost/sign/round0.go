@@ -11,17 +11,30 @@ func (round *Round0) ProcessMessage(*messages.Message) *state.Error {
 }
 
 func (round *Round0) GenerateMessages() ([]*messages.Message, *state.Error) {
+	if round.isCoordinator() {
+		return nil, nil
+	}
+
 	selfParty := round.Parties[round.SelfID()]
 
-	// Sample dᵢ, Dᵢ = [dᵢ] B
-	scalar.SetScalarRandom(&round.d)
+	if round.Options.Mode == ModeRFC9591 {
+		if err := round.generateNonces(); err != nil {
+			return nil, state.NewError(0, err)
+		}
+	} else {
+		// Sample dᵢ, eᵢ
+		scalar.SetScalarRandom(&round.d)
+		scalar.SetScalarRandom(&round.e)
+	}
+
+	// Dᵢ = [dᵢ] B
 	selfParty.Di.ScalarBaseMult(&round.d)
 
-	// Sample eᵢ, Dᵢ = [eᵢ] B
-	scalar.SetScalarRandom(&round.e)
+	// Eᵢ = [eᵢ] B
 	selfParty.Ei.ScalarBaseMult(&round.e)
 
 	msg := messages.NewSign1(round.SelfID(), &selfParty.Di, &selfParty.Ei)
+	msg.To = round.Options.Coordinator
 
 	return []*messages.Message{msg}, nil
 }
@@ -14,6 +14,10 @@ import (
 var hashDomainSeparation = []byte("FROST-SHA512")
 
 func (round *Round1) ProcessMessage(msg *messages.Message) *state.Error {
+	if msg.Type == messages.MessageTypeSignCommitments {
+		return round.processCommitments(msg)
+	}
+
 	id := msg.From
 	otherParty := round.Parties[id]
 	identity := ristretto.NewIdentityElement()
@@ -33,18 +37,21 @@ func (round *Round1) computeRhos() {
 		We need to compute a very simple hash N times, and Go's caching isn't great for hashing.
 		Therefore, we can simply change the buffer and rehash it many times.
 	*/
-	messageHash := sha512.Sum512(round.Message)
+	messageHash := sha512.Sum512(append(round.Options.signature().Prefix(), round.Message...))
+
+	sessionID := round.SessionID()
 
-	sizeB := int(round.PartyIDs().N() * (party.IDByteSize + 32 + 32))
-	bufferHeader := len(hashDomainSeparation) + party.IDByteSize + len(messageHash)
+	sizeB := int(round.signerIDs().N() * (party.IDByteSize + 32 + 32))
+	bufferHeader := len(hashDomainSeparation) + party.IDByteSize + len(sessionID) + len(messageHash)
 	sizeBuffer := bufferHeader + sizeB
 	offsetID := len(hashDomainSeparation)
 
 	// We compute the binding factor 𝜌_{i} for each party as such:
 	//
-	//     𝜌_d = SHA-512 ("FROST-SHA512" ∥ i ∥ SHA-512(Message) ∥ B )
+	//     𝜌_d = SHA-512 ("FROST-SHA512" ∥ i ∥ sid ∥ SHA-512(dom2(F, C) ∥ Message) ∥ B )
 	//
-	// For each party ID i.
+	// For each party ID i. The prefix dom2(F, C) is empty for PureEdDSA.
+	// The session ID sid binds the factors to this signing session.
 	//
 	// The list B is the concatenation of ( j ∥ Dⱼ ∥ Eⱼ ) for all signers j in sorted order.
 	//     B = (ID1 ∥ D₁ ∥ E₁) ∥ (ID_2 ∥ D₂ ∥ E₂) ∥ ... ∥ (ID_N ∥ D_N ∥ E_N)
@@ -54,17 +61,18 @@ func (round *Round1) computeRhos() {
 	buffer := make([]byte, 0, sizeBuffer)
 	buffer = append(buffer, hashDomainSeparation...)
 	buffer = append(buffer, round.SelfID().Bytes()...)
+	buffer = append(buffer, sessionID[:]...)
 	buffer = append(buffer, messageHash[:]...)
 
 	// compute B
-	for _, id := range round.PartyIDs() {
+	for _, id := range round.signerIDs() {
 		otherParty := round.Parties[id]
 		buffer = append(buffer, id.Bytes()...)
 		buffer = append(buffer, otherParty.Di.Bytes()...)
 		buffer = append(buffer, otherParty.Ei.Bytes()...)
 	}
 
-	for _, id := range round.PartyIDs() {
+	for _, id := range round.signerIDs() {
 		// Update the four bytes with the ID
 		copy(buffer[offsetID:], id.Bytes())
 
@@ -75,7 +83,11 @@ func (round *Round1) computeRhos() {
 }
 
 func (round *Round1) GenerateMessages() ([]*messages.Message, *state.Error) {
-	round.computeRhos()
+	if round.Options.Mode == ModeRFC9591 {
+		round.computeBindingFactors()
+	} else {
+		round.computeRhos()
+	}
 
 	round.R.Set(ristretto.NewIdentityElement())
 	for _, p := range round.Parties {
@@ -88,8 +100,22 @@ func (round *Round1) GenerateMessages() ([]*messages.Message, *state.Error) {
 		round.R.Add(&round.R, &p.Ri)
 	}
 
-	// c = H(R, GroupKey, M)
-	round.C.Set(eddsa.ComputeChallenge(&round.R, &round.GroupKey, round.Message))
+	if round.Options.Mode == ModeRFC9591 {
+		// c = H2(R, GroupKey, M)
+		round.C.Set(round.Options.Ciphersuite.Challenge(&round.R, &round.GroupKey, round.Message))
+	} else {
+		// c = H(dom2(F, C), R, GroupKey, M)
+		round.C.Set(eddsa.ComputeChallengeWithOptions(&round.R, &round.GroupKey, round.Message, round.Options.signature()))
+	}
+
+	if round.isCoordinator() {
+		return round.commitmentMessages(), nil
+	}
+
+	// Our nonces must never be used for a second share
+	if err := round.consumeNonce(); err != nil {
+		return nil, state.NewError(0, err)
+	}
 
 	selfParty := round.Parties[round.SelfID()]
 
@@ -102,11 +128,16 @@ func (round *Round1) GenerateMessages() ([]*messages.Message, *state.Error) {
 	secretShare.Add(secretShare, &round.d)                        // d + (e • ρ) + 𝛌 • s • c
 
 	msg := messages.NewSign2(round.SelfID(), secretShare)
+	msg.To = round.Options.Coordinator
 
 	return []*messages.Message{msg}, nil
 }
 
 func (round *Round1) NextRound() state.Round {
+	// Only the coordinator aggregates the signature shares
+	if round.Options.Coordinator != 0 && !round.isCoordinator() {
+		return nil
+	}
 	return &Round2{round}
 }
 
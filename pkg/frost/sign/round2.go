@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/scalar"
 	"github.com/taurusgroup/frost-ed25519/pkg/messages"
 	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
 	"github.com/taurusgroup/frost-ed25519/pkg/state"
@@ -30,6 +31,48 @@ func (round *Round2) ProcessMessage(msg *messages.Message) *state.Error {
 	return nil
 }
 
+// ProcessMessages implements state.BatchProcessor.
+// All shares are first verified at once, and only checked one by one with ProcessMessage to find the culprits.
+func (round *Round2) ProcessMessages(msgs []*messages.Message) []*state.Error {
+	if len(msgs) > 1 && round.verifyShares(msgs) {
+		for _, msg := range msgs {
+			round.Parties[msg.From].Zi.Set(&msg.Sign2.Zi)
+		}
+		return nil
+	}
+	var culprits []*state.Error
+	for _, msg := range msgs {
+		if err := round.ProcessMessage(msg); err != nil {
+			culprits = append(culprits, err)
+		}
+	}
+	return culprits
+}
+
+// verifyShares checks a random linear combination of the equations [zᵢ]B = Rᵢ + [c](𝛌ᵢ • Aᵢ) of all shares:
+//
+//	[∑ aᵢ • zᵢ] B = ∑ [aᵢ] Rᵢ + [aᵢ • c] (𝛌ᵢ • Aᵢ)
+//
+// where the aᵢ are random. If one of the shares is invalid, the check fails except with negligible probability.
+func (round *Round2) verifyShares(msgs []*messages.Message) bool {
+	scalars := make([]*ristretto.Scalar, 0, 2*len(msgs))
+	points := make([]*ristretto.Element, 0, 2*len(msgs))
+	S := ristretto.NewScalar()
+	for _, msg := range msgs {
+		otherParty := round.Parties[msg.From]
+		a := scalar.NewScalarRandom()
+		var ac ristretto.Scalar
+		ac.Multiply(a, &round.C)
+		S.MultiplyAdd(a, &msg.Sign2.Zi, S)
+		scalars = append(scalars, a, &ac)
+		points = append(points, &otherParty.Ri, &otherParty.Public)
+	}
+	var lhs, rhs ristretto.Element
+	lhs.ScalarBaseMult(S)
+	rhs.VarTimeMultiScalarMult(scalars, points)
+	return lhs.Equal(&rhs) == 1
+}
+
 func (round *Round2) GenerateMessages() ([]*messages.Message, *state.Error) {
 	// S = ∑ sᵢ
 	S := ristretto.NewScalar()
@@ -43,7 +86,13 @@ func (round *Round2) GenerateMessages() ([]*messages.Message, *state.Error) {
 		S: *S,
 	}
 
-	if !round.GroupKey.Verify(round.Message, sig) {
+	var valid bool
+	if round.Options.Mode == ModeRFC9591 {
+		valid = round.Options.Ciphersuite.Verify(&round.GroupKey, round.Message, sig)
+	} else {
+		valid = round.GroupKey.VerifyWithOptions(round.Message, sig, round.Options.signature())
+	}
+	if !valid {
 		return nil, state.NewError(0, ErrValidateSignature)
 	}
 
@@ -41,7 +41,11 @@ func UnmarshalKGState(newState *state.State, data []byte) error {
 	if err != nil {
 		return err
 	}
+	if newState.GetRound() != nil {
+		return nil
+	}
 
+	// States marshalled by older versions do not include the round type
 	switch newState.GetRoundNumber() {
 	case 1:
 		var round0 keygen.Round1
@@ -64,7 +68,17 @@ func UnmarshalKGState(newState *state.State, data []byte) error {
 		return nil
 
 	case 3:
-		var round0 keygen.Round2
+		var round0 keygen.Round3
+		err = json.Unmarshal(newState.RoundData, &round0)
+		if err != nil {
+			fmt.Println(err)
+			return err
+		}
+		newState.SetRound(&round0)
+		return nil
+
+	case 4:
+		var round0 keygen.Round4
 		err = json.Unmarshal(newState.RoundData, &round0)
 		if err != nil {
 			fmt.Println(err)
@@ -216,11 +230,14 @@ func (s *MPCSignatureOutState) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	round, err := UnmarshalSignRound(estate.RoundData, estate.GetRoundNumber())
-	if err != nil {
-		return err
+	if estate.GetRound() == nil {
+		// States marshalled by older versions do not include the round type
+		round, err := UnmarshalSignRound(estate.RoundData, estate.GetRoundNumber())
+		if err != nil {
+			return err
+		}
+		estate.SetRound(round)
 	}
-	estate.SetRound(round)
 
 	s.PartyID = pid
 	s.State = &estate
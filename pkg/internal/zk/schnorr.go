@@ -73,6 +73,9 @@ func (proof *Schnorr) Verify(partyID party.ID, public *ristretto.Element, contex
 //   context: 32 byte context string,
 //   public:  [secret] B
 //   M:       [k] B
+//
+// Earlier versions appended the digest to a non-empty buffer, so that SetUniformBytes failed and the challenge was always 0.
+// Their proofs could be produced without knowing the secret, and are not compatible with the ones computed here.
 func challenge(partyID party.ID, context []byte, public, M *ristretto.Element) *ristretto.Scalar {
 	// S = H( ID || CTX || Public || M )
 	var S ristretto.Scalar
@@ -83,7 +86,7 @@ func challenge(partyID party.ID, context []byte, public, M *ristretto.Element) *
 	_, _ = h.Write(public.Bytes())
 	_, _ = h.Write(M.Bytes())
 
-	buffer := make([]byte, 64)
+	buffer := make([]byte, 0, 64)
 	// SetUniformBytes only returns an error when the length is wrong so we're okay here
 	_, _ = S.SetUniformBytes(h.Sum(buffer))
 	return &S
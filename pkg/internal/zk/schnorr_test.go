@@ -19,3 +19,32 @@ func TestSchnorrProof(t *testing.T) {
 	require.True(t, publicComputed.Equal(public) == 1)
 	require.True(t, proof.Verify(partyID, public, ctx[:]))
 }
+
+func TestSchnorrProofContext(t *testing.T) {
+	var ctx, otherCtx [32]byte
+	otherCtx[0] = 1
+	partyID := party.ID(42)
+	private := scalar.NewScalarRandom()
+	public := new(ristretto.Element).ScalarBaseMult(private)
+	proof := NewSchnorrProof(partyID, public, ctx[:], private)
+	require.False(t, proof.Verify(partyID, public, otherCtx[:]), "proof verified with another context")
+	require.False(t, proof.Verify(partyID+1, public, ctx[:]), "proof verified for another party")
+
+	otherPublic := new(ristretto.Element).ScalarBaseMult(scalar.NewScalarRandom())
+	require.False(t, proof.Verify(partyID, otherPublic, ctx[:]), "proof verified for another public key")
+}
+
+func TestSchnorrProofChallenge(t *testing.T) {
+	var ctx [32]byte
+	partyID := party.ID(42)
+	public := new(ristretto.Element).ScalarBaseMult(scalar.NewScalarRandom())
+
+	// A proof with a zero challenge can be produced without the secret: M = [R] B
+	var forged Schnorr
+	forged.R.Set(scalar.NewScalarRandom())
+	require.False(t, forged.Verify(partyID, public, ctx[:]), "proof with zero challenge verified")
+
+	proof := NewSchnorrProof(partyID, public, ctx[:], scalar.NewScalarRandom())
+	require.False(t, proof.Verify(partyID, public, ctx[:]), "proof verified without the secret")
+	require.NotEqual(t, 1, proof.S.Equal(ristretto.NewScalar()), "challenge is zero")
+}
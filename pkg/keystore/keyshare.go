@@ -0,0 +1,265 @@
+// Package keystore defines the file format of threshold key shares, and stores them in a directory.
+//
+// A key share file is a JSON object with the following fields:
+//
+//	version        format version, currently 1
+//	key_id         identifier of the group key, see KeyID
+//	created_at     creation time of the share, in RFC 3339 format
+//	group_key      Ed25519 encoding of the group key, in hex
+//	threshold      maximum number of corrupted parties t, so that t+1 parties are required to sign
+//	party_ids      IDs of all parties holding a share of the key
+//	party_id       ID of the owner of the file
+//	secret_share   secret share of the owner, as a canonical little-endian scalar in hex
+//	public_shares  map from every party ID to its public share, as a Ristretto encoded point in hex
+//	chain_code     chain code used for key derivation in hex, omitted if derivation is not supported
+//	derivation     omitted unless the key was derived from another one, in which case it contains
+//	               parent_key_id, the key ID of the parent, and path, the derivation path from the parent
+//
+// Apart from created_at and derivation, all fields are checked against each other when a file is loaded.
+// In particular, the public shares must lie on a polynomial of degree threshold.
+// Since it contains the secret share, a key share file should be sealed before being stored, see package seal.
+package keystore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+)
+
+// Version is the version of the key share file format.
+const Version = 1
+
+// keyIDSize is the number of bytes of the hash of the group key used as key ID.
+const keyIDSize = 8
+
+// KeyShare is the content of a key share file.
+type KeyShare struct {
+	// KeyID identifies the group key
+	KeyID string
+
+	// CreatedAt is the time at which the share was created
+	CreatedAt time.Time
+
+	// Secret is the secret share of the owner of the file
+	Secret *eddsa.SecretShare
+
+	// Public contains the group key, the threshold, the party set, the public shares of all parties and the chain code
+	Public *eddsa.Public
+
+	// Derivation is set if the key was derived from another one
+	Derivation *Derivation
+}
+
+// Derivation describes how a key was derived from its parent.
+type Derivation struct {
+	// ParentKeyID is the KeyID of the parent key
+	ParentKeyID string `json:"parent_key_id"`
+
+	// Path is the derivation path from the parent key
+	Path eddsa.DerivationPath `json:"path"`
+}
+
+// KeyID returns the identifier of a group key, which is the hex encoding of the first 8 bytes
+// of the SHA-256 hash of its Ed25519 encoding.
+func KeyID(groupKey *eddsa.PublicKey) string {
+	digest := sha256.Sum256(groupKey.ToEd25519())
+	return hex.EncodeToString(digest[:keyIDSize])
+}
+
+// NewKeyShare returns the KeyShare of the owner of secret, created now.
+func NewKeyShare(secret *eddsa.SecretShare, public *eddsa.Public) (*KeyShare, error) {
+	ks := &KeyShare{
+		KeyID:     KeyID(public.GroupKey),
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+		Secret:    secret,
+		Public:    public,
+	}
+	if err := ks.Validate(); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+// Derive returns the KeyShare of the child key at path.
+func (ks *KeyShare) Derive(path eddsa.DerivationPath) (*KeyShare, error) {
+	secret, public, err := ks.Secret.Derive(ks.Public, path)
+	if err != nil {
+		return nil, fmt.Errorf("keystore.KeyShare: %w", err)
+	}
+	child, err := NewKeyShare(secret, public)
+	if err != nil {
+		return nil, err
+	}
+	child.Derivation = &Derivation{
+		ParentKeyID: ks.KeyID,
+		Path:        append(eddsa.DerivationPath{}, path...),
+	}
+	return child, nil
+}
+
+// Validate checks that the KeyShare is consistent.
+func (ks *KeyShare) Validate() error {
+	if ks.Secret == nil || ks.Public == nil || ks.Public.GroupKey == nil {
+		return errors.New("keystore.KeyShare: missing fields")
+	}
+	if ks.KeyID != KeyID(ks.Public.GroupKey) {
+		return errors.New("keystore.KeyShare: key ID does not match the group key")
+	}
+	public, ok := ks.Public.Shares[ks.Secret.ID]
+	if !ok {
+		return fmt.Errorf("keystore.KeyShare: party %d is not contained in the party set", ks.Secret.ID)
+	}
+	if public.Equal(&ks.Secret.Public) != 1 {
+		return errors.New("keystore.KeyShare: secret share does not match its public share")
+	}
+	return checkThreshold(ks.Public)
+}
+
+// checkThreshold verifies that the public shares lie on a polynomial of degree Threshold,
+// by interpolating the shares of the first Threshold+1 parties at the IDs of the others.
+func checkThreshold(public *eddsa.Public) error {
+	t := int(public.Threshold)
+	if t+1 > len(public.PartyIDs) {
+		return errors.New("keystore.KeyShare: threshold is too large")
+	}
+	base := public.PartyIDs[:t+1]
+	for _, j := range public.PartyIDs[t+1:] {
+		interpolated := ristretto.NewIdentityElement()
+		for _, i := range base {
+			lagrange, err := i.LagrangeAt(j, base)
+			if err != nil {
+				return fmt.Errorf("keystore.KeyShare: %w", err)
+			}
+			var tmp ristretto.Element
+			tmp.ScalarMult(lagrange, public.Shares[i])
+			interpolated.Add(interpolated, &tmp)
+		}
+		if interpolated.Equal(public.Shares[j]) != 1 {
+			return fmt.Errorf("keystore.KeyShare: public share of party %d is not consistent with the threshold", j)
+		}
+	}
+	return nil
+}
+
+type keyShareJSON struct {
+	Version      int                 `json:"version"`
+	KeyID        string              `json:"key_id"`
+	CreatedAt    time.Time           `json:"created_at"`
+	GroupKey     string              `json:"group_key"`
+	Threshold    int                 `json:"threshold"`
+	PartyIDs     []int               `json:"party_ids"`
+	PartyID      int                 `json:"party_id"`
+	SecretShare  string              `json:"secret_share"`
+	PublicShares map[party.ID]string `json:"public_shares"`
+	ChainCode    string              `json:"chain_code,omitempty"`
+	Derivation   *Derivation         `json:"derivation,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (ks *KeyShare) MarshalJSON() ([]byte, error) {
+	if err := ks.Validate(); err != nil {
+		return nil, err
+	}
+	partyIDs := make([]int, 0, len(ks.Public.PartyIDs))
+	for _, id := range ks.Public.PartyIDs {
+		partyIDs = append(partyIDs, int(id))
+	}
+	publicShares := make(map[party.ID]string, len(ks.Public.Shares))
+	for id, share := range ks.Public.Shares {
+		publicShares[id] = hex.EncodeToString(share.Bytes())
+	}
+	return json.Marshal(keyShareJSON{
+		Version:      Version,
+		KeyID:        ks.KeyID,
+		CreatedAt:    ks.CreatedAt,
+		GroupKey:     hex.EncodeToString(ks.Public.GroupKey.ToEd25519()),
+		Threshold:    int(ks.Public.Threshold),
+		PartyIDs:     partyIDs,
+		PartyID:      int(ks.Secret.ID),
+		SecretShare:  hex.EncodeToString(ks.Secret.Secret.Bytes()),
+		PublicShares: publicShares,
+		ChainCode:    hex.EncodeToString(ks.Public.ChainCode),
+		Derivation:   ks.Derivation,
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// It returns an error if the fields are not consistent with each other.
+func (ks *KeyShare) UnmarshalJSON(data []byte) error {
+	var raw keyShareJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw.Version != Version {
+		return fmt.Errorf("keystore.KeyShare: unsupported version %d", raw.Version)
+	}
+
+	if raw.Threshold <= 0 || raw.Threshold > math.MaxUint16 || raw.PartyID <= 0 || raw.PartyID > math.MaxUint16 {
+		return errors.New("keystore.KeyShare: invalid threshold or party ID")
+	}
+	partyIDs := make([]party.ID, 0, len(raw.PartyIDs))
+	for _, id := range raw.PartyIDs {
+		if id <= 0 || id > math.MaxUint16 {
+			return fmt.Errorf("keystore.KeyShare: invalid party ID %d", id)
+		}
+		partyIDs = append(partyIDs, party.ID(id))
+	}
+
+	shares := make(map[party.ID]*ristretto.Element, len(raw.PublicShares))
+	for id, encoded := range raw.PublicShares {
+		b, err := hex.DecodeString(encoded)
+		if err != nil {
+			return fmt.Errorf("keystore.KeyShare: public share of party %d: %w", id, err)
+		}
+		var share ristretto.Element
+		if _, err = share.SetCanonicalBytes(b); err != nil {
+			return fmt.Errorf("keystore.KeyShare: public share of party %d: %w", id, err)
+		}
+		shares[id] = &share
+	}
+	public, err := eddsa.NewPublic(shares, party.Size(raw.Threshold))
+	if err != nil {
+		return fmt.Errorf("keystore.KeyShare: %w", err)
+	}
+	if !public.PartyIDs.Equal(party.NewIDSlice(partyIDs)) {
+		return errors.New("keystore.KeyShare: party IDs do not match the public shares")
+	}
+	if hex.EncodeToString(public.GroupKey.ToEd25519()) != raw.GroupKey {
+		return errors.New("keystore.KeyShare: group key does not match the public shares")
+	}
+	if raw.ChainCode != "" {
+		if public.ChainCode, err = hex.DecodeString(raw.ChainCode); err != nil {
+			return fmt.Errorf("keystore.KeyShare: chain code: %w", err)
+		}
+		if len(public.ChainCode) != eddsa.ChainCodeSize {
+			return errors.New("keystore.KeyShare: invalid chain code")
+		}
+	}
+
+	b, err := hex.DecodeString(raw.SecretShare)
+	if err != nil {
+		return fmt.Errorf("keystore.KeyShare: secret share: %w", err)
+	}
+	var secret ristretto.Scalar
+	if _, err = secret.SetCanonicalBytes(b); err != nil {
+		return fmt.Errorf("keystore.KeyShare: secret share: %w", err)
+	}
+
+	*ks = KeyShare{
+		KeyID:      raw.KeyID,
+		CreatedAt:  raw.CreatedAt,
+		Secret:     eddsa.NewSecretShare(party.ID(raw.PartyID), &secret),
+		Public:     public,
+		Derivation: raw.Derivation,
+	}
+	secret.Set(ristretto.NewScalar())
+	return ks.Validate()
+}
@@ -0,0 +1,262 @@
+package keystore
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/dealer"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/seal"
+)
+
+func newTestShares(t *testing.T) *dealer.Output {
+	seed := make([]byte, 32)
+	_, _ = rand.Read(seed)
+	output, err := dealer.SplitSeed(seed, helpers.GenerateSet(4), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	output.Public.ChainCode = make([]byte, eddsa.ChainCodeSize)
+	return output
+}
+
+func equalKeyShares(a, b *KeyShare) bool {
+	return a.KeyID == b.KeyID && a.CreatedAt.Equal(b.CreatedAt) &&
+		a.Secret.Equal(b.Secret) && a.Public.Equal(b.Public)
+}
+
+func TestKeyShareJSON(t *testing.T) {
+	output := newTestShares(t)
+	ks, err := NewKeyShare(output.Secrets[1], output.Public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	child, err := ks.Derive(eddsa.DerivationPath{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, share := range []*KeyShare{ks, child} {
+		data, err := json.Marshal(share)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded KeyShare
+		if err = json.Unmarshal(data, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if !equalKeyShares(share, &decoded) {
+			t.Error("decoded key share differs")
+		}
+	}
+	if child.Derivation == nil || child.Derivation.ParentKeyID != ks.KeyID {
+		t.Error("derivation info is missing")
+	}
+
+	// Fields which are inconsistent with each other are rejected
+	data, _ := json.Marshal(ks)
+	var raw map[string]interface{}
+	_ = json.Unmarshal(data, &raw)
+	for field, value := range map[string]interface{}{
+		"version":   2,
+		"key_id":    "0000000000000000",
+		"group_key": raw["public_shares"].(map[string]interface{})["1"],
+		"threshold": 1,
+		"party_ids": []int{1, 2, 3},
+		"party_id":  2,
+	} {
+		modified := make(map[string]interface{}, len(raw))
+		for k, v := range raw {
+			modified[k] = v
+		}
+		modified[field] = value
+		data, _ := json.Marshal(modified)
+		var decoded KeyShare
+		if err = json.Unmarshal(data, &decoded); err == nil {
+			t.Errorf("accepted key share with modified %s", field)
+		}
+	}
+}
+
+func TestParseKeyShare(t *testing.T) {
+	output := newTestShares(t)
+	id := party.ID(2)
+	expected, err := NewKeyShare(output.Secrets[id], output.Public)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	current, _ := json.Marshal(expected)
+	keygenOut, _ := json.Marshal(struct {
+		Secrets map[party.ID]*eddsa.SecretShare
+		Shares  *eddsa.Public
+	}{output.Secrets, output.Public})
+	keyFile, err := output.KeyFile(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dealerOut, _ := json.Marshal(keyFile)
+	slice := []byte(base64.StdEncoding.EncodeToString(keygenOut))
+
+	for name, data := range map[string][]byte{
+		"current": current,
+		"keygen":  keygenOut,
+		"dealer":  dealerOut,
+		"slice":   slice,
+	} {
+		ks, err := ParseKeyShare(data, id)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if !ks.Secret.Equal(expected.Secret) || !ks.Public.Equal(expected.Public) || ks.KeyID != expected.KeyID {
+			t.Errorf("%s: parsed key share differs", name)
+		}
+	}
+
+	if _, err = ParseKeyShare(keygenOut, 0); err == nil {
+		t.Error("parsed a file with several shares without party ID")
+	}
+	if _, err = ParseKeyShare(dealerOut, 3); err == nil {
+		t.Error("parsed the share of another party")
+	}
+}
+
+// Slices written by ed25519.DKGSlice before key share files were introduced, for parties 1 and 2 of a keygen
+// with 4 parties and threshold 2. They record the threshold 3.
+const (
+	legacyDKGSlice1 = "ewogIlNlY3JldHMiOiB7CiAgIjEiOiB7CiAgICJpZCI6IDEsCiAgICJzZWNyZXQiOiAiOFBZTWJmSVozTFhocURTUjV1Y0puZ2hPZWVhWkpDaVBRYTZVVnpRWTNnZz0iCiAgfQogfSwKICJTaGFyZXMiOiB7CiAgInQiOiAzLAogICJncm91cGtleSI6ICJnQnlENjdrWVNmVWxJenFqK3Q4MlpLZGxNQVFoSjcrc0trMytMK3ZHN0hZPSIsCiAgInNoYXJlcyI6IHsKICAgIjEiOiAiM29sdDlWY0ZFZVhXZnVySGRSSjMvbHVZMVpwdlhFSitSU1EweGVPUlVUYz0iLAogICAiMiI6ICJITi9Xc2prQkg4UlpMUk0zVjlLclZTMDRIa3dLMXQ5WHY5WFgxSFY0YURrPSIsCiAgICIzIjogIktLaFYwM2hpcTFCbUJCcWlkVnRnR3hCVDlKMXk0Zi91Z3RGMDk1LzdPSG89IiwKICAgIjQiOiAiTW1ZNGJFVmZXQmtYeWtGMXdtSGdaUEJLaEhoNk1hK05ON2ZtckhzQ2x5OD0iCiAgfQogfQp9"
+	legacyDKGSlice2 = "ewogIlNlY3JldHMiOiB7CiAgIjIiOiB7CiAgICJpZCI6IDIsCiAgICJzZWNyZXQiOiAidHNIVDhWdCtwRnB5S1MyblNqaGNpQnM1RldvMklWYXVmM003QWRnWUJRaz0iCiAgfQogfSwKICJTaGFyZXMiOiB7CiAgInQiOiAzLAogICJncm91cGtleSI6ICJnQnlENjdrWVNmVWxJenFqK3Q4MlpLZGxNQVFoSjcrc0trMytMK3ZHN0hZPSIsCiAgInNoYXJlcyI6IHsKICAgIjEiOiAiM29sdDlWY0ZFZVhXZnVySGRSSjMvbHVZMVpwdlhFSitSU1EweGVPUlVUYz0iLAogICAiMiI6ICJITi9Xc2prQkg4UlpMUk0zVjlLclZTMDRIa3dLMXQ5WHY5WFgxSFY0YURrPSIsCiAgICIzIjogIktLaFYwM2hpcTFCbUJCcWlkVnRnR3hCVDlKMXk0Zi91Z3RGMDk1LzdPSG89IiwKICAgIjQiOiAiTW1ZNGJFVmZXQmtYeWtGMXdtSGdaUEJLaEhoNk1hK05ON2ZtckhzQ2x5OD0iCiAgfQogfQp9"
+)
+
+// Slice written by ed25519.SliceKeygen for party 1 of the same key, which only contains its own public share.
+const legacySliceKeygen1 = "ewogIlNlY3JldHMiOiB7CiAgIjEiOiB7CiAgICJpZCI6IDEsCiAgICJzZWNyZXQiOiAiOFBZTWJmSVozTFhocURTUjV1Y0puZ2hPZWVhWkpDaVBRYTZVVnpRWTNnZz0iCiAgfQogfSwKICJTaGFyZXMiOiB7CiAgInQiOiAyLAogICJncm91cGtleSI6ICJnQnlENjdrWVNmVWxJenFqK3Q4MlpLZGxNQVFoSjcrc0trMytMK3ZHN0hZPSIsCiAgInNoYXJlcyI6IHsKICAgIjEiOiAiM29sdDlWY0ZFZVhXZnVySGRSSjMvbHVZMVpwdlhFSitSU1EweGVPUlVUYz0iCiAgfQogfQp9"
+
+func TestParseKeyShareWithThreshold(t *testing.T) {
+	var groupKey *eddsa.PublicKey
+	for id, slice := range map[party.ID]string{1: legacyDKGSlice1, 2: legacyDKGSlice2} {
+		// Without the threshold, the wrong threshold of the slice is kept
+		ks, err := ParseKeyShare([]byte(slice), id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ks.Public.Threshold != 3 {
+			t.Errorf("party %d: expected the recorded threshold 3, got %d", id, ks.Public.Threshold)
+		}
+
+		ks, err = ParseKeyShareWithThreshold([]byte(slice), id, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ks.Public.Threshold != 2 || ks.Secret.ID != id {
+			t.Errorf("party %d: parsed key share differs", id)
+		}
+		if groupKey == nil {
+			groupKey = ks.Public.GroupKey
+		} else if !groupKey.Equal(ks.Public.GroupKey) {
+			t.Error("slices have different group keys")
+		}
+
+		// The public shares are checked against the given threshold
+		if _, err = ParseKeyShareWithThreshold([]byte(slice), id, 1); err == nil {
+			t.Errorf("party %d: parsed the slice with a wrong threshold", id)
+		}
+	}
+
+	if _, err := ParseKeyShareWithThreshold([]byte(legacySliceKeygen1), 1, 2); err == nil {
+		t.Error("parsed a slice without the public shares of the other parties")
+	}
+
+	// Files in the current format must record the given threshold
+	output := newTestShares(t)
+	expected, err := NewKeyShare(output.Secrets[1], output.Public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	current, _ := json.Marshal(expected)
+	if _, err = ParseKeyShareWithThreshold(current, 1, 2); err != nil {
+		t.Error(err)
+	}
+	if _, err = ParseKeyShareWithThreshold(current, 1, 1); err == nil {
+		t.Error("parsed a file which records another threshold")
+	}
+}
+
+func TestStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keystore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	key := make([]byte, seal.KeySize)
+	_, _ = rand.Read(key)
+	sealer, err := seal.NewSealer(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := newTestShares(t)
+
+	for _, s := range []*seal.Sealer{nil, sealer} {
+		store, err := Open(dir, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var shares []*KeyShare
+		for _, id := range output.Public.PartyIDs[:2] {
+			ks, err := NewKeyShare(output.Secrets[id], output.Public)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = store.Save(ks); err != nil {
+				t.Fatal(err)
+			}
+			shares = append(shares, ks)
+		}
+
+		entries, err := store.List()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 2 {
+			t.Fatalf("expected 2 entries, got %v", entries)
+		}
+		for i, e := range entries {
+			ks, err := store.Load(e.KeyID, e.PartyID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equalKeyShares(ks, shares[i]) {
+				t.Error("loaded key share differs")
+			}
+		}
+
+		exported, err := store.Export(shares[0].KeyID, shares[0].Secret.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = store.Delete(shares[0].KeyID, shares[0].Secret.ID); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = store.Load(shares[0].KeyID, shares[0].Secret.ID); !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+		if _, err = store.Import(exported, 0); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = store.Load(shares[0].KeyID, shares[0].Secret.ID); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = store.Load("../"+shares[0].KeyID, shares[0].Secret.ID); !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package keystore
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/dealer"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+)
+
+// legacyJSON contains the fields of the formats used before key share files were introduced:
+//   - {"Secrets": {...}, "Shares": {...}}, written by cmd/keygen, and base64 encoded in the slices of ed25519.DKGSlice
+//   - {"secret": {...}, "public": {...}, "commitments": ...}, the dealer.KeyFile written by cmd/dealer
+//
+// The slices of ed25519.DKGSlice record the threshold n-1, whatever the threshold of the key generation,
+// so the real threshold must be given to ParseKeyShareWithThreshold.
+// The slices of ed25519.SliceKeygen only contain the public share of their owner, and cannot be converted.
+type legacyJSON struct {
+	Version *int                            `json:"version"`
+	Secrets map[party.ID]*eddsa.SecretShare `json:"Secrets"`
+	Shares  *eddsa.Public                   `json:"Shares"`
+	Secret  *eddsa.SecretShare              `json:"secret"`
+	Public  *eddsa.Public                   `json:"public"`
+}
+
+// ParseKeyShare parses a key share file, or the share of the party id in one of the formats used previously.
+// If id is 0, the file must contain a single share, which is returned.
+// Since previous formats do not record it, the creation time of the returned KeyShare is set to now.
+func ParseKeyShare(data []byte, id party.ID) (*KeyShare, error) {
+	return parseKeyShare(data, id, 0)
+}
+
+// ParseKeyShareWithThreshold is similar to ParseKeyShare, but for files which may not record the right threshold,
+// such as the slices of ed25519.DKGSlice.
+// In the {"Secrets": {...}, "Shares": {...}} format, the recorded threshold is replaced with threshold,
+// and the public shares are checked against it. Other formats must record the same threshold.
+func ParseKeyShareWithThreshold(data []byte, id party.ID, threshold party.Size) (*KeyShare, error) {
+	if threshold == 0 {
+		return nil, errors.New("keystore.ParseKeyShareWithThreshold: threshold must be positive")
+	}
+	ks, err := parseKeyShare(data, id, threshold)
+	if err != nil {
+		return nil, err
+	}
+	if ks.Public.Threshold != threshold {
+		return nil, fmt.Errorf("keystore.ParseKeyShareWithThreshold: file records threshold %d", ks.Public.Threshold)
+	}
+	return ks, nil
+}
+
+// parseKeyShare implements ParseKeyShare. If threshold is not 0, it replaces the threshold of the legacy
+// {"Secrets": {...}, "Shares": {...}} format.
+func parseKeyShare(data []byte, id party.ID, threshold party.Size) (*KeyShare, error) {
+	// The slices of package ed25519 are base64 encoded
+	if decoded, err := base64.StdEncoding.DecodeString(string(data)); err == nil {
+		data = decoded
+	}
+
+	var raw legacyJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("keystore.ParseKeyShare: %w", err)
+	}
+
+	switch {
+	case raw.Version != nil:
+		var ks KeyShare
+		if err := json.Unmarshal(data, &ks); err != nil {
+			return nil, err
+		}
+		if id != 0 && ks.Secret.ID != id {
+			return nil, fmt.Errorf("keystore.ParseKeyShare: file contains the share of party %d", ks.Secret.ID)
+		}
+		return &ks, nil
+
+	case raw.Secret != nil && raw.Public != nil:
+		var keyFile dealer.KeyFile
+		if err := json.Unmarshal(data, &keyFile); err != nil {
+			return nil, fmt.Errorf("keystore.ParseKeyShare: %w", err)
+		}
+		if err := keyFile.Verify(); err != nil {
+			return nil, fmt.Errorf("keystore.ParseKeyShare: %w", err)
+		}
+		if id != 0 && keyFile.Secret.ID != id {
+			return nil, fmt.Errorf("keystore.ParseKeyShare: file contains the share of party %d", keyFile.Secret.ID)
+		}
+		return NewKeyShare(keyFile.Secret, keyFile.Public)
+
+	case raw.Secrets != nil && raw.Shares != nil:
+		if id == 0 && len(raw.Secrets) == 1 {
+			for id = range raw.Secrets {
+			}
+		}
+		secret, ok := raw.Secrets[id]
+		if !ok {
+			return nil, fmt.Errorf("keystore.ParseKeyShare: file does not contain the share of party %d", id)
+		}
+		if threshold != 0 {
+			// NewKeyShare checks that the public shares are consistent with the new threshold
+			public := *raw.Shares
+			public.Threshold = threshold
+			raw.Shares = &public
+		}
+		return NewKeyShare(secret, raw.Shares)
+	}
+	return nil, errors.New("keystore.ParseKeyShare: unknown format")
+}
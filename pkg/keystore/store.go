@@ -0,0 +1,195 @@
+package keystore
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/seal"
+)
+
+// Extensions of the key share files in a Store.
+const (
+	plainExtension  = ".json"
+	sealedExtension = ".sealed"
+)
+
+// ErrNotFound is returned when a Store does not contain the requested key share.
+var ErrNotFound = errors.New("keystore: key share not found")
+
+// Store stores key share files in a directory, under the name <key ID>-<party ID>.json.
+// If it was opened with a seal.Sealer, the files are sealed and named <key ID>-<party ID>.sealed instead.
+type Store struct {
+	dir    string
+	sealer *seal.Sealer
+}
+
+// Entry identifies a key share in a Store.
+type Entry struct {
+	KeyID   string
+	PartyID party.ID
+}
+
+// Open returns the Store in dir, and creates the directory if it does not exist.
+// If sealer is nil, the key shares are stored in the clear.
+func Open(dir string, sealer *seal.Sealer) (*Store, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("keystore.Open: %w", err)
+	}
+	return &Store{
+		dir:    dir,
+		sealer: sealer,
+	}, nil
+}
+
+func (s *Store) extension() string {
+	if s.sealer != nil {
+		return sealedExtension
+	}
+	return plainExtension
+}
+
+// validKeyID returns true if keyID has the form returned by KeyID, so that it can safely be used in a file name.
+func validKeyID(keyID string) bool {
+	_, err := hex.DecodeString(keyID)
+	return err == nil && len(keyID) == 2*keyIDSize
+}
+
+func (s *Store) path(keyID string, id party.ID) string {
+	return filepath.Join(s.dir, keyID+"-"+id.String()+s.extension())
+}
+
+// List returns the key shares contained in the Store, sorted by key ID and party ID.
+func (s *Store) List() ([]Entry, error) {
+	files, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("keystore.List: %w", err)
+	}
+	entries := make([]Entry, 0, len(files))
+	for _, f := range files {
+		name := f.Name()
+		if f.IsDir() || !strings.HasSuffix(name, s.extension()) {
+			continue
+		}
+		name = strings.TrimSuffix(name, s.extension())
+		sep := strings.LastIndexByte(name, '-')
+		if sep < 0 || !validKeyID(name[:sep]) {
+			continue
+		}
+		id, err := strconv.ParseUint(name[sep+1:], 10, 16)
+		if err != nil || id == 0 {
+			continue
+		}
+		entries = append(entries, Entry{KeyID: name[:sep], PartyID: party.ID(id)})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].KeyID != entries[j].KeyID {
+			return entries[i].KeyID < entries[j].KeyID
+		}
+		return entries[i].PartyID < entries[j].PartyID
+	})
+	return entries, nil
+}
+
+// Load returns the share of the party id for the key keyID.
+func (s *Store) Load(keyID string, id party.ID) (*KeyShare, error) {
+	if !validKeyID(keyID) {
+		return nil, ErrNotFound
+	}
+	data, err := ioutil.ReadFile(s.path(keyID, id))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("keystore.Load: %w", err)
+	}
+	if s.sealer != nil {
+		if data, _, err = s.sealer.Open(seal.KindSecretShare, data, 0); err != nil {
+			return nil, fmt.Errorf("keystore.Load: %w", err)
+		}
+	}
+
+	var ks KeyShare
+	if err = json.Unmarshal(data, &ks); err != nil {
+		return nil, fmt.Errorf("keystore.Load: %w", err)
+	}
+	if ks.KeyID != keyID || ks.Secret.ID != id {
+		return nil, errors.New("keystore.Load: file does not contain the expected key share")
+	}
+	return &ks, nil
+}
+
+// Save stores a key share, and replaces the previous share of the same party for the same key, if any.
+// This is the case after the shares were refreshed.
+func (s *Store) Save(ks *KeyShare) error {
+	data, err := json.MarshalIndent(ks, "", " ")
+	if err != nil {
+		return fmt.Errorf("keystore.Save: %w", err)
+	}
+	if s.sealer != nil {
+		if data, err = s.sealer.Seal(seal.KindSecretShare, 0, data); err != nil {
+			return fmt.Errorf("keystore.Save: %w", err)
+		}
+	}
+
+	// Write to a temporary file first, so that the previous share is not lost if writing fails
+	tmp, err := ioutil.TempFile(s.dir, ".tmp-")
+	if err != nil {
+		return fmt.Errorf("keystore.Save: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), s.path(ks.KeyID, ks.Secret.ID))
+	}
+	if err != nil {
+		return fmt.Errorf("keystore.Save: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the share of the party id for the key keyID.
+func (s *Store) Delete(keyID string, id party.ID) error {
+	if !validKeyID(keyID) {
+		return ErrNotFound
+	}
+	err := os.Remove(s.path(keyID, id))
+	if os.IsNotExist(err) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// Import parses a key share in any supported format, see ParseKeyShare, and saves it.
+func (s *Store) Import(data []byte, id party.ID) (*KeyShare, error) {
+	ks, err := ParseKeyShare(data, id)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.Save(ks); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+// Export returns the share of the party id for the key keyID as an unsealed key share file.
+func (s *Store) Export(keyID string, id party.ID) ([]byte, error) {
+	ks, err := s.Load(keyID, id)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(ks, "", " ")
+}
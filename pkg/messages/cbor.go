@@ -0,0 +1,503 @@
+package messages
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+)
+
+// The CBOR encoding (RFC 8949) allows peers which are not written in Go to interoperate with this library.
+// It is only defined for the messages of keygen (KeyGen1 to KeyGen5) and signing (Sign1 and Sign2).
+//
+// A message is encoded as a map with unsigned integer keys.
+// The keys 1 to 4 are common to all messages:
+//
+//	1: type       (uint)
+//	2: from       (uint)
+//	3: to         (uint, 0 for broadcast)
+//	4: session ID (bytes, 32)
+//
+// The remaining keys depend on the type:
+//
+//	KeyGen1: 10: proof (bytes, 64), 11: chain code (bytes, 32), 12: encryption key (bytes, 32),
+//	         13: commitments (array of bytes, 32 each)
+//	KeyGen2: 10: ciphertext (bytes, 48), 11: echo (array of bytes, 32 each)
+//	KeyGen3: 10: accused parties (array of uint, increasing)
+//	KeyGen4: 10: parties (array of uint, increasing), 11: shares (array of bytes, 32 each, in the same order),
+//	         12: echo (array of bytes, 32 each)
+//	KeyGen5: 10: echo (array of bytes, 32 each)
+//	Sign1:   10: D (bytes, 32), 11: E (bytes, 32)
+//	Sign2:   10: z (bytes, 32)
+//
+// Group elements and scalars use the same 32 byte encoding as in the binary format.
+// The encoder produces the deterministic encoding of RFC 8949, section 4.2.1.
+// The decoder only accepts definite lengths, rejects duplicate keys, and ignores unknown keys.
+
+const (
+	cborKeyType = 1 + iota
+	cborKeyFrom
+	cborKeyTo
+	cborKeySessionID
+)
+
+const (
+	cborKeyField0 = 10 + iota
+	cborKeyField1
+	cborKeyField2
+	cborKeyField3
+)
+
+const (
+	cborMajorUint  = 0
+	cborMajorBytes = 2
+	cborMajorArray = 4
+	cborMajorMap   = 5
+)
+
+// cborMaxID is the largest value of a party.ID.
+const cborMaxID = 1<<(8*party.IDByteSize) - 1
+
+var errCBOR = errors.New("invalid CBOR")
+
+// MarshalCBOR returns the CBOR encoding of the message.
+func (m *Message) MarshalCBOR() ([]byte, error) {
+	return m.appendCBOR(nil)
+}
+
+// UnmarshalCBOR decodes the CBOR encoding of a message.
+func (m *Message) UnmarshalCBOR(data []byte) error {
+	d := cborDecoder{data: data}
+	n, err := d.readHead(cborMajorMap)
+	if err != nil {
+		return fmt.Errorf("messages.UnmarshalCBOR: %w", err)
+	}
+
+	var (
+		h      Header
+		fields = map[uint64]interface{}{}
+		seen   = map[uint64]bool{}
+	)
+	for i := uint64(0); i < n; i++ {
+		key, err := d.readHead(cborMajorUint)
+		if err != nil {
+			return fmt.Errorf("messages.UnmarshalCBOR: key: %w", err)
+		}
+		if seen[key] {
+			return fmt.Errorf("messages.UnmarshalCBOR: duplicate key %d: %w", key, errCBOR)
+		}
+		seen[key] = true
+
+		switch key {
+		case cborKeyType, cborKeyFrom, cborKeyTo:
+			v, err := d.readHead(cborMajorUint)
+			if err != nil {
+				return fmt.Errorf("messages.UnmarshalCBOR: key %d: %w", key, err)
+			}
+			fields[key] = v
+		case cborKeySessionID, cborKeyField0, cborKeyField1, cborKeyField2, cborKeyField3:
+			v, err := d.readItem()
+			if err != nil {
+				return fmt.Errorf("messages.UnmarshalCBOR: key %d: %w", key, err)
+			}
+			fields[key] = v
+		default:
+			if _, err = d.readItem(); err != nil {
+				return fmt.Errorf("messages.UnmarshalCBOR: key %d: %w", key, err)
+			}
+		}
+	}
+	if len(d.data) != 0 {
+		return fmt.Errorf("messages.UnmarshalCBOR: trailing data: %w", errCBOR)
+	}
+
+	msgType, ok1 := fields[cborKeyType].(uint64)
+	from, ok2 := fields[cborKeyFrom].(uint64)
+	to, ok3 := fields[cborKeyTo].(uint64)
+	sessionID, ok4 := fields[cborKeySessionID].([]byte)
+	if !ok1 || !ok2 || !ok3 || !ok4 || msgType > 0xff || from > cborMaxID || to > cborMaxID || len(sessionID) != SessionIDSize {
+		return fmt.Errorf("messages.UnmarshalCBOR: header: %w", errCBOR)
+	}
+
+	// Reuse the checks of the binary header
+	header := make([]byte, 0, headerSize)
+	header = append(header, byte(msgType))
+	header = append(header, party.ID(from).Bytes()...)
+	header = append(header, party.ID(to).Bytes()...)
+	if err = h.UnmarshalBinary(header); err != nil {
+		return err
+	}
+
+	var body []byte
+	switch h.Type {
+	case MessageTypeKeyGen1:
+		proof, ok1 := fields[cborKeyField0].([]byte)
+		chainCode, ok2 := fields[cborKeyField1].([]byte)
+		encryptionKey, ok3 := fields[cborKeyField2].([]byte)
+		commitments, ok4 := cborByteStrings(fields[cborKeyField3], 32)
+		if !ok1 || !ok2 || !ok3 || !ok4 || len(proof) != 64 || len(chainCode) != ChainCodeSize ||
+			len(encryptionKey) != EncryptionKeySize || len(commitments) == 0 || len(commitments) > cborMaxID+1 {
+			return fmt.Errorf("messages.UnmarshalCBOR: keygen1: %w", errCBOR)
+		}
+		body = append(body, proof...)
+		body = append(body, chainCode...)
+		body = append(body, encryptionKey...)
+		body = append(body, party.ID(len(commitments)-1).Bytes()...)
+		for _, c := range commitments {
+			body = append(body, c...)
+		}
+	case MessageTypeKeyGen2:
+		ciphertext, ok1 := fields[cborKeyField0].([]byte)
+		echo, ok2 := cborByteStrings(fields[cborKeyField1], EchoDigestSize)
+		if !ok1 || !ok2 || len(ciphertext) != KeyGen2CiphertextSize {
+			return fmt.Errorf("messages.UnmarshalCBOR: keygen2: %w", errCBOR)
+		}
+		body = append(body, ciphertext...)
+		for _, digest := range echo {
+			body = append(body, digest...)
+		}
+	case MessageTypeKeyGen3:
+		accused, ok := cborIDs(fields[cborKeyField0])
+		if !ok {
+			return fmt.Errorf("messages.UnmarshalCBOR: keygen3: %w", errCBOR)
+		}
+		for _, id := range accused {
+			body = append(body, id.Bytes()...)
+		}
+	case MessageTypeKeyGen4:
+		ids, ok1 := cborIDs(fields[cborKeyField0])
+		shares, ok2 := cborByteStrings(fields[cborKeyField1], 32)
+		echo, ok3 := cborByteStrings(fields[cborKeyField2], EchoDigestSize)
+		if !ok1 || !ok2 || !ok3 || len(ids) != len(shares) || len(echo) > cborMaxID {
+			return fmt.Errorf("messages.UnmarshalCBOR: keygen4: %w", errCBOR)
+		}
+		body = append(body, party.ID(len(echo)).Bytes()...)
+		for _, digest := range echo {
+			body = append(body, digest...)
+		}
+		for i, id := range ids {
+			body = append(body, id.Bytes()...)
+			body = append(body, shares[i]...)
+		}
+	case MessageTypeKeyGen5:
+		echo, ok := cborByteStrings(fields[cborKeyField0], EchoDigestSize)
+		if !ok {
+			return fmt.Errorf("messages.UnmarshalCBOR: keygen5: %w", errCBOR)
+		}
+		for _, digest := range echo {
+			body = append(body, digest...)
+		}
+	case MessageTypeSign1:
+		D, ok1 := fields[cborKeyField0].([]byte)
+		E, ok2 := fields[cborKeyField1].([]byte)
+		if !ok1 || !ok2 || len(D) != 32 || len(E) != 32 {
+			return fmt.Errorf("messages.UnmarshalCBOR: sign1: %w", errCBOR)
+		}
+		body = append(append(body, D...), E...)
+	case MessageTypeSign2:
+		z, ok := fields[cborKeyField0].([]byte)
+		if !ok || len(z) != 32 {
+			return fmt.Errorf("messages.UnmarshalCBOR: sign2: %w", errCBOR)
+		}
+		body = append(body, z...)
+	default:
+		return fmt.Errorf("messages.UnmarshalCBOR: type %d: %w", h.Type, ErrUnsupportedEncoding)
+	}
+
+	// The fields are validated by the binary decoder
+	if err = m.unmarshalPayload(append(header, body...)); err != nil {
+		return err
+	}
+	copy(m.SessionID[:], sessionID)
+	return nil
+}
+
+func (m *Message) appendCBOR(existing []byte) ([]byte, error) {
+	var fields int
+	switch m.Type {
+	case MessageTypeKeyGen1:
+		if m.KeyGen1 == nil {
+			return nil, errors.New("message does not contain any data")
+		}
+		fields = 4
+	case MessageTypeKeyGen2:
+		if m.KeyGen2 == nil {
+			return nil, errors.New("message does not contain any data")
+		}
+		fields = 2
+	case MessageTypeKeyGen3:
+		if m.KeyGen3 == nil {
+			return nil, errors.New("message does not contain any data")
+		}
+		fields = 1
+	case MessageTypeKeyGen4:
+		if m.KeyGen4 == nil {
+			return nil, errors.New("message does not contain any data")
+		}
+		fields = 3
+	case MessageTypeKeyGen5:
+		if m.KeyGen5 == nil {
+			return nil, errors.New("message does not contain any data")
+		}
+		fields = 1
+	case MessageTypeSign1:
+		if m.Sign1 == nil {
+			return nil, errors.New("message does not contain any data")
+		}
+		fields = 2
+	case MessageTypeSign2:
+		if m.Sign2 == nil {
+			return nil, errors.New("message does not contain any data")
+		}
+		fields = 1
+	default:
+		return nil, fmt.Errorf("messages.MarshalCBOR: type %d: %w", m.Type, ErrUnsupportedEncoding)
+	}
+	// The binary encoder validates the fields
+	if _, err := m.appendPayload(nil); err != nil {
+		return nil, fmt.Errorf("messages.MarshalCBOR: %w", err)
+	}
+
+	existing = cborAppendHead(existing, cborMajorMap, uint64(4+fields))
+	existing = cborAppendUint(existing, cborKeyType, uint64(m.Type))
+	existing = cborAppendUint(existing, cborKeyFrom, uint64(m.From))
+	existing = cborAppendUint(existing, cborKeyTo, uint64(m.To))
+	existing = cborAppendBytes(existing, cborKeySessionID, m.SessionID[:])
+
+	switch m.Type {
+	case MessageTypeKeyGen1:
+		proof, err := m.KeyGen1.Proof.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		commitments, err := m.KeyGen1.Commitments.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		existing = cborAppendBytes(existing, cborKeyField0, proof)
+		existing = cborAppendBytes(existing, cborKeyField1, m.KeyGen1.ChainCode)
+		existing = cborAppendBytes(existing, cborKeyField2, m.KeyGen1.EncryptionKey)
+		existing = cborAppendByteStrings(existing, cborKeyField3, commitments[party.IDByteSize:], 32)
+	case MessageTypeKeyGen2:
+		existing = cborAppendBytes(existing, cborKeyField0, m.KeyGen2.Ciphertext)
+		existing = cborAppendByteStrings(existing, cborKeyField1, m.KeyGen2.EchoBytes(), EchoDigestSize)
+	case MessageTypeKeyGen3:
+		accused := party.NewIDSlice(append([]party.ID{}, m.KeyGen3.Accused...))
+		existing = cborAppendIDs(existing, cborKeyField0, accused)
+	case MessageTypeKeyGen4:
+		ids := make([]party.ID, 0, len(m.KeyGen4.Shares))
+		for id := range m.KeyGen4.Shares {
+			ids = append(ids, id)
+		}
+		sorted := party.NewIDSlice(ids)
+		shares := make([]byte, 0, 32*len(sorted))
+		for _, id := range sorted {
+			shares = append(shares, m.KeyGen4.Shares[id].Bytes()...)
+		}
+		existing = cborAppendIDs(existing, cborKeyField0, sorted)
+		existing = cborAppendByteStrings(existing, cborKeyField1, shares, 32)
+		existing = cborAppendByteStrings(existing, cborKeyField2, m.KeyGen4.EchoBytes(), EchoDigestSize)
+	case MessageTypeKeyGen5:
+		existing = cborAppendByteStrings(existing, cborKeyField0, m.KeyGen5.EchoBytes(), EchoDigestSize)
+	case MessageTypeSign1:
+		existing = cborAppendBytes(existing, cborKeyField0, m.Sign1.Di.Bytes())
+		existing = cborAppendBytes(existing, cborKeyField1, m.Sign1.Ei.Bytes())
+	case MessageTypeSign2:
+		existing = cborAppendBytes(existing, cborKeyField0, m.Sign2.Zi.Bytes())
+	}
+	return existing, nil
+}
+
+func cborAppendHead(existing []byte, major byte, n uint64) []byte {
+	major <<= 5
+	switch {
+	case n < 24:
+		return append(existing, major|byte(n))
+	case n <= 0xff:
+		return append(existing, major|24, byte(n))
+	case n <= 0xffff:
+		existing = append(existing, major|25, 0, 0)
+		binary.BigEndian.PutUint16(existing[len(existing)-2:], uint16(n))
+		return existing
+	case n <= 0xffffffff:
+		existing = append(existing, major|26, 0, 0, 0, 0)
+		binary.BigEndian.PutUint32(existing[len(existing)-4:], uint32(n))
+		return existing
+	default:
+		existing = append(existing, major|27, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(existing[len(existing)-8:], n)
+		return existing
+	}
+}
+
+func cborAppendUint(existing []byte, key, value uint64) []byte {
+	existing = cborAppendHead(existing, cborMajorUint, key)
+	return cborAppendHead(existing, cborMajorUint, value)
+}
+
+func cborAppendBytes(existing []byte, key uint64, value []byte) []byte {
+	existing = cborAppendHead(existing, cborMajorUint, key)
+	existing = cborAppendHead(existing, cborMajorBytes, uint64(len(value)))
+	return append(existing, value...)
+}
+
+// cborAppendByteStrings splits values in chunks of the given size, and encodes them as an array of byte strings.
+func cborAppendByteStrings(existing []byte, key uint64, values []byte, size int) []byte {
+	existing = cborAppendHead(existing, cborMajorUint, key)
+	existing = cborAppendHead(existing, cborMajorArray, uint64(len(values)/size))
+	for ; len(values) >= size; values = values[size:] {
+		existing = cborAppendHead(existing, cborMajorBytes, uint64(size))
+		existing = append(existing, values[:size]...)
+	}
+	return existing
+}
+
+// cborAppendIDs encodes ids as an array of unsigned integers.
+func cborAppendIDs(existing []byte, key uint64, ids party.IDSlice) []byte {
+	existing = cborAppendHead(existing, cborMajorUint, key)
+	existing = cborAppendHead(existing, cborMajorArray, uint64(len(ids)))
+	for _, id := range ids {
+		existing = cborAppendHead(existing, cborMajorUint, uint64(id))
+	}
+	return existing
+}
+
+// cborIDs converts a decoded array into party IDs.
+func cborIDs(v interface{}) ([]party.ID, bool) {
+	items, ok := v.([]interface{})
+	if !ok {
+		return nil, false
+	}
+	ids := make([]party.ID, 0, len(items))
+	for _, item := range items {
+		id, ok := item.(uint64)
+		if !ok || id > cborMaxID {
+			return nil, false
+		}
+		ids = append(ids, party.ID(id))
+	}
+	return ids, true
+}
+
+// cborByteStrings converts a decoded array into byte strings of the given size.
+func cborByteStrings(v interface{}, size int) ([][]byte, bool) {
+	items, ok := v.([]interface{})
+	if !ok {
+		return nil, false
+	}
+	values := make([][]byte, 0, len(items))
+	for _, item := range items {
+		value, ok := item.([]byte)
+		if !ok || len(value) != size {
+			return nil, false
+		}
+		values = append(values, value)
+	}
+	return values, true
+}
+
+// cborMaxDepth limits the nesting of the items we decode.
+const cborMaxDepth = 4
+
+type cborDecoder struct {
+	data  []byte
+	depth int
+}
+
+// readHead reads the head of an item, which must be of the given major type, and returns its argument.
+func (d *cborDecoder) readHead(major byte) (uint64, error) {
+	m, n, err := d.readAnyHead()
+	if err != nil {
+		return 0, err
+	}
+	if m != major {
+		return 0, fmt.Errorf("unexpected major type %d: %w", m, errCBOR)
+	}
+	return n, nil
+}
+
+func (d *cborDecoder) readAnyHead() (byte, uint64, error) {
+	if len(d.data) == 0 {
+		return 0, 0, fmt.Errorf("unexpected end of data: %w", errCBOR)
+	}
+	major, info := d.data[0]>>5, d.data[0]&0x1f
+	d.data = d.data[1:]
+
+	var size int
+	switch {
+	case info < 24:
+		return major, uint64(info), nil
+	case info == 24:
+		size = 1
+	case info == 25:
+		size = 2
+	case info == 26:
+		size = 4
+	case info == 27:
+		size = 8
+	default:
+		// Indefinite lengths and reserved values
+		return 0, 0, fmt.Errorf("unsupported additional information %d: %w", info, errCBOR)
+	}
+	if len(d.data) < size {
+		return 0, 0, fmt.Errorf("unexpected end of data: %w", errCBOR)
+	}
+	var n uint64
+	for _, b := range d.data[:size] {
+		n = n<<8 | uint64(b)
+	}
+	d.data = d.data[size:]
+	return major, n, nil
+}
+
+// readItem decodes an unsigned integer, a byte string, or an array or map of these.
+// Unsigned integers are returned as uint64, byte strings as []byte, and arrays as []interface{}.
+// Maps are skipped and returned as nil.
+func (d *cborDecoder) readItem() (interface{}, error) {
+	major, n, err := d.readAnyHead()
+	if err != nil {
+		return nil, err
+	}
+	switch major {
+	case cborMajorUint:
+		return n, nil
+	case cborMajorBytes:
+		if uint64(len(d.data)) < n {
+			return nil, fmt.Errorf("unexpected end of data: %w", errCBOR)
+		}
+		value := append([]byte{}, d.data[:n]...)
+		d.data = d.data[n:]
+		return value, nil
+	case cborMajorArray, cborMajorMap:
+		if d.depth >= cborMaxDepth {
+			return nil, fmt.Errorf("nesting too deep: %w", errCBOR)
+		}
+		// Every item takes at least one byte
+		if n > uint64(len(d.data)) {
+			return nil, fmt.Errorf("unexpected end of data: %w", errCBOR)
+		}
+		count := n
+		if major == cborMajorMap {
+			count *= 2
+		}
+		if count > uint64(len(d.data)) {
+			return nil, fmt.Errorf("unexpected end of data: %w", errCBOR)
+		}
+		d.depth++
+		defer func() { d.depth-- }()
+		items := make([]interface{}, 0, count)
+		for i := uint64(0); i < count; i++ {
+			item, err := d.readItem()
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		if major == cborMajorMap {
+			return nil, nil
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("unsupported major type %d: %w", major, errCBOR)
+}
@@ -0,0 +1,142 @@
+package messages
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/polynomial"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/scalar"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/zk"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+)
+
+func cborTestMessages() []*Message {
+	secret := scalar.NewScalarRandom()
+	poly := polynomial.NewPolynomial(3, secret)
+	comm := polynomial.NewPolynomialExponent(poly)
+	proof := zk.NewSchnorrProof(1, comm.Constant(), make([]byte, 32), secret)
+	chainCode := make([]byte, ChainCodeSize)
+	_, _ = rand.Read(chainCode)
+	encryptionKey := make([]byte, EncryptionKeySize)
+	_, _ = rand.Read(encryptionKey)
+
+	ciphertext := make([]byte, KeyGen2CiphertextSize)
+	_, _ = rand.Read(ciphertext)
+	echo := make([][]byte, 3)
+	for i := range echo {
+		echo[i] = make([]byte, EchoDigestSize)
+		_, _ = rand.Read(echo[i])
+	}
+
+	D := new(ristretto.Element).ScalarBaseMult(scalar.NewScalarRandom())
+	E := new(ristretto.Element).ScalarBaseMult(scalar.NewScalarRandom())
+
+	return []*Message{
+		NewKeyGen1(1, proof, chainCode, encryptionKey, comm),
+		NewKeyGen2(1, 2, ciphertext, echo),
+		NewKeyGen3(1, party.IDSlice{}),
+		NewKeyGen3(1, party.IDSlice{2, 5}),
+		NewKeyGen4(1, echo, nil),
+		NewKeyGen4(1, echo, map[party.ID]*ristretto.Scalar{
+			2: scalar.NewScalarRandom(),
+			5: scalar.NewScalarRandom(),
+		}),
+		NewKeyGen5(1, echo[:2]),
+		NewSign1(1, D, E),
+		NewSign2(1, scalar.NewScalarRandom()),
+	}
+}
+
+func TestMessage_CBOR(t *testing.T) {
+	for _, msg := range cborTestMessages() {
+		msg.SessionID = SessionID{4, 5, 6}
+
+		data, err := msg.MarshalCBOR()
+		require.NoError(t, err)
+		var msg2 Message
+		require.NoError(t, msg2.UnmarshalCBOR(data))
+		assert.True(t, msg.Equal(&msg2), "type %d: messages are not equal", msg.Type)
+
+		// The encoding is deterministic
+		data2, err := msg2.MarshalCBOR()
+		require.NoError(t, err)
+		assert.Equal(t, data, data2)
+
+		// The CBOR encoding can also be used inside an envelope
+		data, err = msg.MarshalBinaryWithEncoding(EncodingCBOR)
+		require.NoError(t, err)
+		assert.Equal(t, byte(EncodingCBOR), data[3])
+		var msg3 Message
+		require.NoError(t, msg3.UnmarshalBinary(data))
+		assert.True(t, msg.Equal(&msg3), "type %d: messages are not equal", msg.Type)
+
+		// The session ID in the envelope must match the one in the payload
+		data[4] ^= 1
+		assert.Error(t, msg3.UnmarshalBinary(data), "type %d: session ID of envelope was modified", msg.Type)
+	}
+}
+
+func TestMessage_CBORVector(t *testing.T) {
+	var z ristretto.Scalar
+	_, err := z.SetCanonicalBytes(append([]byte{7}, make([]byte, 31)...))
+	require.NoError(t, err)
+	msg := NewSign2(0x0102, &z)
+
+	data, err := msg.MarshalCBOR()
+	require.NoError(t, err)
+	expected := "a5" + // map(5)
+		"0104" + // 1: type Sign2
+		"02" + "190102" + // 2: from
+		"0300" + // 3: to
+		"045820" + "0000000000000000000000000000000000000000000000000000000000000000" + // 4: session ID
+		"0a5820" + "0700000000000000000000000000000000000000000000000000000000000000" // 10: z
+	assert.Equal(t, expected, hex.EncodeToString(data))
+
+	msg = NewKeyGen3(1, party.IDSlice{2, 300})
+	data, err = msg.MarshalCBOR()
+	require.NoError(t, err)
+	expected = "a5" + // map(5)
+		"010c" + // 1: type KeyGen3
+		"0201" + // 2: from
+		"0300" + // 3: to
+		"045820" + "0000000000000000000000000000000000000000000000000000000000000000" + // 4: session ID
+		"0a82" + "02" + "19012c" // 10: accused
+	assert.Equal(t, expected, hex.EncodeToString(data))
+}
+
+func TestMessage_UnmarshalCBORInvalid(t *testing.T) {
+	msg := NewSign2(1, scalar.NewScalarRandom())
+	data, err := msg.MarshalCBOR()
+	require.NoError(t, err)
+
+	// Unknown keys are ignored
+	unknown := append([]byte{0xa6}, data[1:]...)
+	unknown = append(unknown, 0x18, 0x63, 0x82, 0x01, 0x41, 0x00)
+	var msg2 Message
+	require.NoError(t, msg2.UnmarshalCBOR(unknown))
+	assert.True(t, msg.Equal(&msg2), "messages are not equal")
+
+	tests := map[string][]byte{
+		"empty":      {},
+		"not a map":  {0x80},
+		"duplicate":  append(append([]byte{0xa6}, data[1:]...), 0x01, 0x04),
+		"missing":    {0xa1, 0x01, 0x04},
+		"truncated":  data[:len(data)-1],
+		"trailing":   append(append([]byte{}, data...), 0x00),
+		"indefinite": {0xbf, 0xff},
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			var msg2 Message
+			assert.Error(t, msg2.UnmarshalCBOR(data))
+		})
+	}
+
+	// Only some messages can be encoded with CBOR
+	_, err = NewRepair1(1, 2, scalar.NewScalarRandom()).MarshalCBOR()
+	assert.ErrorIs(t, err, ErrUnsupportedEncoding)
+}
@@ -0,0 +1,158 @@
+package messages
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// A Message is sent inside a versioned envelope:
+//
+//	magic (2) ∥ version (1) ∥ encoding (1) ∥ session ID (32) ∥ round (1) ∥ payload length (4) ∥ payload
+//
+// The payload contains the message, encoded as specified by the encoding byte.
+// With EncodingBinary, the payload has the same format as messages encoded before the envelope was introduced,
+// and the session ID of the message is only carried by the envelope.
+// With EncodingCBOR, the session ID is repeated in the payload, and must match the envelope.
+// The round is repeated in the envelope, so that messages can be routed without decoding the payload.
+//
+// Messages encoded before the envelope was introduced consist of the binary payload only.
+// They are still accepted by UnmarshalBinary, can be recognized since their first byte is a MessageType,
+// and belong to the zero SessionID.
+
+// EnvelopeVersion is the version of the envelope produced by MarshalBinary.
+const EnvelopeVersion uint8 = 1
+
+const envelopeHeaderSize = 2 + 1 + 1 + SessionIDSize + 1 + 4
+
+var envelopeMagic = [2]byte{0xF7, 0x05}
+
+// Encoding specifies how the payload of an envelope is encoded.
+type Encoding uint8
+
+const (
+	// EncodingBinary is the compact binary encoding of the message.
+	EncodingBinary Encoding = iota
+
+	// EncodingCBOR is the CBOR encoding of the message, see Message.MarshalCBOR.
+	EncodingCBOR
+)
+
+var (
+	// ErrUnsupportedVersion is returned when decoding an envelope with a newer version.
+	ErrUnsupportedVersion = errors.New("unsupported envelope version")
+
+	// ErrUnsupportedEncoding is returned when a message cannot be encoded or decoded with the requested encoding.
+	ErrUnsupportedEncoding = errors.New("unsupported encoding")
+)
+
+// Round returns the round of its protocol in which a message of this type is sent, starting at 1.
+func (t MessageType) Round() uint8 {
+	switch t {
+	case MessageTypeKeyGen1, MessageTypeSign1, MessageTypePreprocess, MessageTypeRefresh1, MessageTypeReshare1, MessageTypeRepair1, MessageTypeMultiSign1:
+		return 1
+	case MessageTypeKeyGen2, MessageTypeSign2, MessageTypeRefresh2, MessageTypeReshare2, MessageTypeRepair2, MessageTypeSignCommitments, MessageTypeMultiSign2:
+		return 2
+	case MessageTypeKeyGen3:
+		return 3
+	case MessageTypeKeyGen4:
+		return 4
+	case MessageTypeKeyGen5:
+		return 5
+	}
+	return 0
+}
+
+// BytesAppend appends the enveloped binary encoding of the message.
+func (m *Message) BytesAppend(existing []byte) (data []byte, err error) {
+	return m.appendEnvelope(existing, EncodingBinary)
+}
+
+// Size returns the size of the enveloped binary encoding of the message.
+func (m *Message) Size() int {
+	return envelopeHeaderSize + m.payloadSize()
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// The message is encoded in an envelope with EncodingBinary.
+func (m *Message) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, m.Size())
+	return m.BytesAppend(buf)
+}
+
+// MarshalBinaryWithEncoding returns the message in an envelope, with the payload encoded as specified.
+func (m *Message) MarshalBinaryWithEncoding(encoding Encoding) ([]byte, error) {
+	return m.appendEnvelope(nil, encoding)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+// It accepts enveloped messages with any supported encoding, as well as messages without envelope.
+func (m *Message) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 || data[0] != envelopeMagic[0] || data[1] != envelopeMagic[1] {
+		return m.unmarshalPayload(data)
+	}
+
+	if len(data) < envelopeHeaderSize {
+		return fmt.Errorf("messages.UnmarshalBinary: envelope should be at least %d bytes (got %d)", envelopeHeaderSize, len(data))
+	}
+	version, encoding := data[2], Encoding(data[3])
+	if version != EnvelopeVersion {
+		return fmt.Errorf("messages.UnmarshalBinary: version %d: %w", version, ErrUnsupportedVersion)
+	}
+	var sessionID SessionID
+	copy(sessionID[:], data[4:])
+	round := data[4+SessionIDSize]
+	length := binary.BigEndian.Uint32(data[5+SessionIDSize:])
+	payload := data[envelopeHeaderSize:]
+	if uint64(len(payload)) != uint64(length) {
+		return fmt.Errorf("messages.UnmarshalBinary: payload should be %d bytes (got %d)", length, len(payload))
+	}
+
+	var err error
+	switch encoding {
+	case EncodingBinary:
+		if err = m.unmarshalPayload(payload); err == nil {
+			m.SessionID = sessionID
+		}
+	case EncodingCBOR:
+		err = m.UnmarshalCBOR(payload)
+	default:
+		return fmt.Errorf("messages.UnmarshalBinary: encoding %d: %w", encoding, ErrUnsupportedEncoding)
+	}
+	if err != nil {
+		return err
+	}
+
+	if m.SessionID != sessionID {
+		return errors.New("messages.UnmarshalBinary: session ID of envelope and payload differ")
+	}
+	if m.Type.Round() != round {
+		return errors.New("messages.UnmarshalBinary: round of envelope and payload differ")
+	}
+	return nil
+}
+
+func (m *Message) appendEnvelope(existing []byte, encoding Encoding) ([]byte, error) {
+	existing = append(existing, envelopeMagic[:]...)
+	existing = append(existing, EnvelopeVersion, byte(encoding))
+	existing = append(existing, m.SessionID[:]...)
+	existing = append(existing, m.Type.Round())
+	lengthOffset := len(existing)
+	existing = append(existing, 0, 0, 0, 0)
+
+	var err error
+	switch encoding {
+	case EncodingBinary:
+		existing, err = m.appendPayload(existing)
+	case EncodingCBOR:
+		existing, err = m.appendCBOR(existing)
+	default:
+		err = fmt.Errorf("messages.BytesAppend: encoding %d: %w", encoding, ErrUnsupportedEncoding)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	binary.BigEndian.PutUint32(existing[lengthOffset:], uint32(len(existing)-lengthOffset-4))
+	return existing, nil
+}
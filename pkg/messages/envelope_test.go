@@ -0,0 +1,119 @@
+package messages
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/scalar"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+)
+
+func TestMessage_Envelope(t *testing.T) {
+	msg := NewSign2(42, scalar.NewScalarRandom())
+	msg.SessionID = SessionID{1, 2, 3}
+
+	data, err := msg.MarshalBinary()
+	require.NoError(t, err)
+	require.Len(t, data, msg.Size())
+	assert.Equal(t, envelopeMagic[:], data[:2])
+	assert.Equal(t, EnvelopeVersion, data[2])
+	assert.Equal(t, byte(EncodingBinary), data[3])
+	assert.Equal(t, msg.SessionID[:], data[4:4+SessionIDSize])
+	assert.Equal(t, uint8(2), data[4+SessionIDSize])
+
+	var msg2 Message
+	require.NoError(t, msg2.UnmarshalBinary(data))
+	assert.True(t, msg.Equal(&msg2), "messages are not equal")
+}
+
+func TestMessage_UnmarshalLegacy(t *testing.T) {
+	var z ristretto.Scalar
+	_, err := z.SetCanonicalBytes(append([]byte{7}, make([]byte, 31)...))
+	require.NoError(t, err)
+	var D, E ristretto.Element
+	D.ScalarBaseMult(&z)
+	E.ScalarBaseMult(party.ID(3).Scalar())
+
+	// Messages without envelope, as encoded by earlier versions with the 5 byte header Type ∥ From ∥ To
+	tests := []struct {
+		name    string
+		legacy  string
+		message *Message
+	}{
+		{
+			"sign1",
+			"03002a0000" +
+				"44f53520926ec81fbd5a387845beb7df85a96a24ece18738bdcfa6a7822a176d" +
+				"94741f5d5d52755ece4f23f044ee27d5d1ea1e2bd196b462166b16152a9d0259",
+			NewSign1(42, &D, &E),
+		},
+		{
+			"sign2",
+			"04002a0000" +
+				"0700000000000000000000000000000000000000000000000000000000000000",
+			NewSign2(42, &z),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			legacy, err := hex.DecodeString(tt.legacy)
+			require.NoError(t, err)
+
+			msg := Message{Header: Header{SessionID: SessionID{1}}}
+			require.NoError(t, msg.UnmarshalBinary(legacy))
+			assert.True(t, tt.message.Equal(&msg), "messages are not equal")
+			assert.Equal(t, SessionID{}, msg.SessionID)
+
+			// The binary payload of an envelope has the same format
+			payload, err := tt.message.appendPayload(nil)
+			require.NoError(t, err)
+			assert.Equal(t, legacy, payload)
+		})
+	}
+}
+
+func TestMessage_UnmarshalInvalidEnvelope(t *testing.T) {
+	msg := NewSign2(42, scalar.NewScalarRandom())
+	data, err := msg.MarshalBinary()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		modify func(data []byte) []byte
+	}{
+		{"version", func(data []byte) []byte {
+			data[2] = EnvelopeVersion + 1
+			return data
+		}},
+		{"encoding", func(data []byte) []byte {
+			data[3] = 0xff
+			return data
+		}},
+		{"round", func(data []byte) []byte {
+			data[4+SessionIDSize] = 1
+			return data
+		}},
+		{"length", func(data []byte) []byte {
+			return data[:len(data)-1]
+		}},
+		{"trailing", func(data []byte) []byte {
+			return append(data, 0)
+		}},
+		{"short", func(data []byte) []byte {
+			return data[:envelopeHeaderSize-1]
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var msg2 Message
+			assert.Error(t, msg2.UnmarshalBinary(tt.modify(append([]byte{}, data...))))
+		})
+	}
+
+	var msg2 Message
+	data[2] = EnvelopeVersion + 1
+	assert.ErrorIs(t, msg2.UnmarshalBinary(data), ErrUnsupportedVersion)
+}
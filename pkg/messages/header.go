@@ -9,6 +9,13 @@ import (
 
 const headerSize = 1 + 2*party.IDByteSize
 
+// SessionIDSize is the size in bytes of a SessionID.
+const SessionIDSize = 32
+
+// SessionID identifies a single execution of a protocol.
+// It must be unique for each execution, and known to all participants before it starts.
+type SessionID [SessionIDSize]byte
+
 type Header struct {
 	// Type is the message type
 	Type MessageType
@@ -21,6 +28,11 @@ type Header struct {
 	// If the message is intended for broadcast, the ID returned is 0 (invalid),
 	// therefore, you should call IsBroadcast() first.
 	To party.ID
+
+	// SessionID is the identifier of the protocol execution this message belongs to.
+	// It is not part of the binary encoding of the Header, which is unchanged from earlier versions,
+	// and is carried by the envelope of the Message instead.
+	SessionID SessionID
 }
 
 func (h *Header) MarshalBinary() (data []byte, err error) {
@@ -28,6 +40,7 @@ func (h *Header) MarshalBinary() (data []byte, err error) {
 	return h.BytesAppend(data)
 }
 
+// UnmarshalBinary decodes the Type, From and To fields of the Header, and sets the SessionID to zero.
 func (h *Header) UnmarshalBinary(data []byte) error {
 	if l := len(data); l < headerSize {
 		return fmt.Errorf("Header.UnmarshalBinary: data should be at least %d bytes (got %d)", headerSize, l)
@@ -44,16 +57,17 @@ func (h *Header) UnmarshalBinary(data []byte) error {
 	if to, err = party.FromBytes(data[1+party.IDByteSize:]); err != nil {
 		return fmt.Errorf("Header.UnmarshalBinary: from: %w", err)
 	}
-
 	switch msgType {
-	case MessageTypeKeyGen1, MessageTypeSign1, MessageTypeSign2:
+	case MessageTypeKeyGen1, MessageTypePreprocess, MessageTypeRefresh1, MessageTypeReshare1, MessageTypeKeyGen3, MessageTypeKeyGen4, MessageTypeKeyGen5, MessageTypeMultiSign1, MessageTypeMultiSign2:
 		if to != 0 {
 			return errors.New("Header.UnmarshalBinary: .To field must be 0 to indicate broadcast")
 		}
-	case MessageTypeKeyGen2:
+	case MessageTypeKeyGen2, MessageTypeRefresh2, MessageTypeReshare2, MessageTypeRepair1, MessageTypeRepair2, MessageTypeSignCommitments:
 		if to == 0 {
-			return errors.New("Header.UnmarshalBinary: MessageTypeKeyGen2 requires a sender (.To field)")
+			return errors.New("Header.UnmarshalBinary: point-to-point message requires a sender (.To field)")
 		}
+	case MessageTypeSign1, MessageTypeSign2:
+		// Broadcast to all signers, or sent to the coordinator of the session
 	default:
 		return errors.New("Header.UnmarshalBinary: invalid message type")
 	}
@@ -64,19 +78,22 @@ func (h *Header) UnmarshalBinary(data []byte) error {
 	h.Type = msgType
 	h.From = from
 	h.To = to
+	h.SessionID = SessionID{}
 	return nil
 }
 
 func (h *Header) BytesAppend(existing []byte) (data []byte, err error) {
 	switch h.Type {
-	case MessageTypeKeyGen1, MessageTypeSign1, MessageTypeSign2:
+	case MessageTypeKeyGen1, MessageTypePreprocess, MessageTypeRefresh1, MessageTypeReshare1, MessageTypeKeyGen3, MessageTypeKeyGen4, MessageTypeKeyGen5, MessageTypeMultiSign1, MessageTypeMultiSign2:
 		if h.To != 0 {
 			return nil, errors.New("Header.BytesAppend: .To field must be 0 to indicate broadcast")
 		}
-	case MessageTypeKeyGen2:
+	case MessageTypeKeyGen2, MessageTypeRefresh2, MessageTypeReshare2, MessageTypeRepair1, MessageTypeRepair2, MessageTypeSignCommitments:
 		if h.To == 0 {
-			return nil, errors.New("Header.BytesAppend: MessageTypeKeyGen2 requires a sender (.To field)")
+			return nil, errors.New("Header.BytesAppend: point-to-point message requires a sender (.To field)")
 		}
+	case MessageTypeSign1, MessageTypeSign2:
+		// Broadcast to all signers, or sent to the coordinator of the session
 	default:
 		return nil, errors.New("Header.BytesAppend: invalid message type")
 	}
@@ -72,14 +72,14 @@ func TestHeader_UnmarshalBinary(t *testing.T) {
 			false,
 		},
 		{
-			"bad sign1",
+			"sign1 to coordinator",
 			fields{
 				Type: MessageTypeSign1,
 				From: 2,
 				To:   1,
 			},
 			args{data: []byte{3, 0, 2, 0, 1}},
-			true,
+			false,
 		},
 		{
 			"ok sign2",
@@ -92,13 +92,33 @@ func TestHeader_UnmarshalBinary(t *testing.T) {
 			false,
 		},
 		{
-			"bad sign2",
+			"sign2 to coordinator",
 			fields{
 				Type: MessageTypeSign2,
 				From: 2,
 				To:   1,
 			},
 			args{data: []byte{4, 0, 2, 0, 1}},
+			false,
+		},
+		{
+			"ok sign commitments",
+			fields{
+				Type: MessageTypeSignCommitments,
+				From: 1,
+				To:   2,
+			},
+			args{data: []byte{14, 0, 1, 0, 2}},
+			false,
+		},
+		{
+			"bad sign commitments",
+			fields{
+				Type: MessageTypeSignCommitments,
+				From: 1,
+				To:   0,
+			},
+			args{data: []byte{14, 0, 1, 0, 0}},
 			true,
 		},
 		{
@@ -108,7 +128,7 @@ func TestHeader_UnmarshalBinary(t *testing.T) {
 				From: 2,
 				To:   1,
 			},
-			args{data: []byte{4, 0, 2, 0, 1}},
+			args{data: []byte{0, 0, 2, 0, 1}},
 			true,
 		},
 	}
@@ -119,7 +139,7 @@ func TestHeader_UnmarshalBinary(t *testing.T) {
 				From: tt.fields.From,
 				To:   tt.fields.To,
 			}
-			h2 := &Header{}
+			h2 := &Header{SessionID: SessionID{1, 2, 3}}
 			err := h2.UnmarshalBinary(tt.args.data)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("UnmarshalBinary() error = %v, wantErr %v", err, tt.wantErr)
@@ -1,6 +1,7 @@
 package messages
 
 import (
+	"bytes"
 	"fmt"
 
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
@@ -8,20 +9,36 @@ import (
 	"github.com/taurusgroup/frost-ed25519/pkg/internal/zk"
 )
 
+// ChainCodeSize is the size of a party's contribution to the chain code used for key derivation.
+const ChainCodeSize = 32
+
+// EncryptionKeySize is the size of a party's ephemeral X25519 public key.
+const EncryptionKeySize = 32
+
 type KeyGen1 struct {
-	Proof       *zk.Schnorr
+	Proof *zk.Schnorr
+
+	// ChainCode is the party's random contribution to the group's chain code
+	ChainCode []byte
+
+	// EncryptionKey is the party's ephemeral X25519 public key,
+	// used to encrypt the KeyGen2 shares addressed to it
+	EncryptionKey []byte
+
 	Commitments *polynomial.Exponent
 }
 
-func NewKeyGen1(from party.ID, proof *zk.Schnorr, commitments *polynomial.Exponent) *Message {
+func NewKeyGen1(from party.ID, proof *zk.Schnorr, chainCode, encryptionKey []byte, commitments *polynomial.Exponent) *Message {
 	return &Message{
 		Header: Header{
 			Type: MessageTypeKeyGen1,
 			From: from,
 		},
 		KeyGen1: &KeyGen1{
-			Proof:       proof,
-			Commitments: commitments,
+			Proof:         proof,
+			ChainCode:     chainCode,
+			EncryptionKey: encryptionKey,
+			Commitments:   commitments,
 		},
 	}
 }
@@ -32,6 +49,14 @@ func (m *KeyGen1) BytesAppend(existing []byte) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	if len(m.ChainCode) != ChainCodeSize {
+		return nil, fmt.Errorf("msg1: chain code should be %d bytes (got %d)", ChainCodeSize, len(m.ChainCode))
+	}
+	existing = append(existing, m.ChainCode...)
+	if len(m.EncryptionKey) != EncryptionKeySize {
+		return nil, fmt.Errorf("msg1: encryption key should be %d bytes (got %d)", EncryptionKeySize, len(m.EncryptionKey))
+	}
+	existing = append(existing, m.EncryptionKey...)
 	existing, err = m.Commitments.BytesAppend(existing)
 	if err != nil {
 		return nil, err
@@ -47,7 +72,7 @@ func (m *KeyGen1) MarshalBinary() (data []byte, err error) {
 
 // UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
 func (m *KeyGen1) UnmarshalBinary(data []byte) error {
-	if len(data) < 64 {
+	if len(data) < 64+ChainCodeSize+EncryptionKeySize {
 		return fmt.Errorf("msg1: %w", ErrInvalidMessage)
 	}
 
@@ -57,7 +82,11 @@ func (m *KeyGen1) UnmarshalBinary(data []byte) error {
 	if err := m.Proof.UnmarshalBinary(data[:64]); err != nil {
 		return err
 	}
-	if err := m.Commitments.UnmarshalBinary(data[64:]); err != nil {
+	data = data[64:]
+	m.ChainCode = append([]byte{}, data[:ChainCodeSize]...)
+	data = data[ChainCodeSize:]
+	m.EncryptionKey = append([]byte{}, data[:EncryptionKeySize]...)
+	if err := m.Commitments.UnmarshalBinary(data[EncryptionKeySize:]); err != nil {
 		return err
 	}
 
@@ -65,7 +94,7 @@ func (m *KeyGen1) UnmarshalBinary(data []byte) error {
 }
 
 func (m *KeyGen1) Size() int {
-	return m.Proof.Size() + m.Commitments.Size()
+	return m.Proof.Size() + ChainCodeSize + EncryptionKeySize + m.Commitments.Size()
 }
 
 func (m *KeyGen1) Equal(other interface{}) bool {
@@ -76,6 +105,12 @@ func (m *KeyGen1) Equal(other interface{}) bool {
 	if !otherMsg.Proof.Equal(m.Proof) {
 		return false
 	}
+	if !bytes.Equal(otherMsg.ChainCode, m.ChainCode) {
+		return false
+	}
+	if !bytes.Equal(otherMsg.EncryptionKey, m.EncryptionKey) {
+		return false
+	}
 	if !otherMsg.Commitments.Equal(m.Commitments) {
 		return false
 	}
@@ -1,6 +1,7 @@
 package messages
 
 import (
+	"crypto/rand"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -22,7 +23,13 @@ func TestKeyGen1_MarshalBinary(t *testing.T) {
 
 	proof := zk.NewSchnorrProof(from, comm.Constant(), context, poly.Constant())
 
-	msg := NewKeyGen1(from, proof, comm)
+	chainCode := make([]byte, ChainCodeSize)
+	_, _ = rand.Read(chainCode)
+
+	encryptionKey := make([]byte, EncryptionKeySize)
+	_, _ = rand.Read(encryptionKey)
+
+	msg := NewKeyGen1(from, proof, chainCode, encryptionKey, comm)
 
 	var msg2 Message
 	require.NoError(t, CheckFROSTMarshaler(msg, &msg2))
@@ -1,52 +1,89 @@
 package messages
 
 import (
+	"bytes"
 	"fmt"
 
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
-	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
 )
 
-const sizeKeygen2 = 32
+// KeyGen2CiphertextSize is the size of an encrypted share: a 32 byte scalar followed by a 16 byte authentication tag.
+const KeyGen2CiphertextSize = 32 + 16
+
+// EchoDigestSize is the size of the digest of a KeyGen1 message, as echoed in KeyGen2.
+const EchoDigestSize = 32
 
 type KeyGen2 struct {
-	// Share is a Shamir additive share for the destination party
-	Share ristretto.Scalar
+	// Ciphertext is the encryption of the Shamir additive share for the destination party,
+	// under the key shared by the sender and the destination
+	Ciphertext []byte
+
+	// Echo contains the digests of the KeyGen1 messages received by the sender (including its own),
+	// ordered by party.ID. It allows the destination to check that all parties received the same broadcast.
+	Echo [][]byte
 }
 
-func NewKeyGen2(from, to party.ID, share *ristretto.Scalar) *Message {
+func NewKeyGen2(from, to party.ID, ciphertext []byte, echo [][]byte) *Message {
 	return &Message{
 		Header: Header{
 			Type: MessageTypeKeyGen2,
 			From: from,
 			To:   to,
 		},
-		KeyGen2: &KeyGen2{Share: *share},
+		KeyGen2: &KeyGen2{
+			Ciphertext: ciphertext,
+			Echo:       echo,
+		},
 	}
 }
 
 func (m *KeyGen2) BytesAppend(existing []byte) ([]byte, error) {
-	return append(existing, m.Share.Bytes()...), nil
+	if len(m.Ciphertext) != KeyGen2CiphertextSize {
+		return nil, fmt.Errorf("msg2: ciphertext should be %d bytes (got %d)", KeyGen2CiphertextSize, len(m.Ciphertext))
+	}
+	existing = append(existing, m.Ciphertext...)
+	for _, digest := range m.Echo {
+		if len(digest) != EchoDigestSize {
+			return nil, fmt.Errorf("msg2: echo digest should be %d bytes (got %d)", EchoDigestSize, len(digest))
+		}
+		existing = append(existing, digest...)
+	}
+	return existing, nil
 }
 
 // MarshalBinary implements the encoding.BinaryMarshaler interface.
 func (m *KeyGen2) MarshalBinary() ([]byte, error) {
-	buf := make([]byte, 0, sizeKeygen2)
+	buf := make([]byte, 0, m.Size())
 	return m.BytesAppend(buf)
 }
 
 // UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
 func (m *KeyGen2) UnmarshalBinary(data []byte) error {
-	if len(data) != sizeKeygen2 {
+	if len(data) < KeyGen2CiphertextSize || (len(data)-KeyGen2CiphertextSize)%EchoDigestSize != 0 {
 		return fmt.Errorf("msg2: %w", ErrInvalidMessage)
 	}
 
-	_, err := m.Share.SetCanonicalBytes(data)
-	return err
+	m.Ciphertext = append([]byte{}, data[:KeyGen2CiphertextSize]...)
+	data = data[KeyGen2CiphertextSize:]
+	m.Echo = make([][]byte, 0, len(data)/EchoDigestSize)
+	for len(data) > 0 {
+		m.Echo = append(m.Echo, append([]byte{}, data[:EchoDigestSize]...))
+		data = data[EchoDigestSize:]
+	}
+	return nil
 }
 
 func (m *KeyGen2) Size() int {
-	return sizeKeygen2
+	return KeyGen2CiphertextSize + len(m.Echo)*EchoDigestSize
+}
+
+// EchoBytes returns the concatenation of all digests in Echo.
+func (m *KeyGen2) EchoBytes() []byte {
+	out := make([]byte, 0, len(m.Echo)*EchoDigestSize)
+	for _, digest := range m.Echo {
+		out = append(out, digest...)
+	}
+	return out
 }
 
 func (m *KeyGen2) Equal(other interface{}) bool {
@@ -54,8 +91,16 @@ func (m *KeyGen2) Equal(other interface{}) bool {
 	if !ok {
 		return false
 	}
-	if otherMsg.Share.Equal(&m.Share) != 1 {
+	if !bytes.Equal(otherMsg.Ciphertext, m.Ciphertext) {
 		return false
 	}
+	if len(otherMsg.Echo) != len(m.Echo) {
+		return false
+	}
+	for i := range m.Echo {
+		if !bytes.Equal(otherMsg.Echo[i], m.Echo[i]) {
+			return false
+		}
+	}
 	return true
 }
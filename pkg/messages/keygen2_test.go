@@ -7,15 +7,21 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
-	"github.com/taurusgroup/frost-ed25519/pkg/internal/scalar"
 )
 
 func TestKeyGen2_MarshalBinary(t *testing.T) {
 	from := party.ID(rand.Uint32())
 	to := party.ID(rand.Uint32())
-	secret := scalar.NewScalarRandom()
+	ciphertext := make([]byte, KeyGen2CiphertextSize)
+	_, _ = rand.Read(ciphertext)
 
-	msg := NewKeyGen2(from, to, secret)
+	echo := make([][]byte, 5)
+	for i := range echo {
+		echo[i] = make([]byte, EchoDigestSize)
+		_, _ = rand.Read(echo[i])
+	}
+
+	msg := NewKeyGen2(from, to, ciphertext, echo)
 
 	var msg2 Message
 	require.NoError(t, CheckFROSTMarshaler(msg, &msg2))
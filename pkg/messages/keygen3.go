@@ -0,0 +1,73 @@
+package messages
+
+import (
+	"fmt"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+)
+
+// KeyGen3 is the complaint broadcast by every party when keygen is run with complaints enabled.
+type KeyGen3 struct {
+	// Accused contains the parties whose share failed to decrypt or to validate against their commitments.
+	// It is empty if all shares were valid.
+	Accused party.IDSlice
+}
+
+func NewKeyGen3(from party.ID, accused party.IDSlice) *Message {
+	return &Message{
+		Header: Header{
+			Type: MessageTypeKeyGen3,
+			From: from,
+		},
+		KeyGen3: &KeyGen3{
+			Accused: accused,
+		},
+	}
+}
+
+func (m *KeyGen3) BytesAppend(existing []byte) ([]byte, error) {
+	for _, id := range m.Accused {
+		existing = append(existing, id.Bytes()...)
+	}
+	return existing, nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (m *KeyGen3) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, m.Size())
+	return m.BytesAppend(buf)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (m *KeyGen3) UnmarshalBinary(data []byte) error {
+	if len(data)%party.IDByteSize != 0 {
+		return fmt.Errorf("msg3: %w", ErrInvalidMessage)
+	}
+	accused := make([]party.ID, 0, len(data)/party.IDByteSize)
+	for ; len(data) > 0; data = data[party.IDByteSize:] {
+		id, err := party.FromBytes(data)
+		if err != nil {
+			return err
+		}
+		accused = append(accused, id)
+	}
+	m.Accused = party.NewIDSlice(accused)
+	for i := 1; i < len(m.Accused); i++ {
+		if m.Accused[i-1] == m.Accused[i] {
+			return fmt.Errorf("msg3: duplicate accused party: %w", ErrInvalidMessage)
+		}
+	}
+	return nil
+}
+
+func (m *KeyGen3) Size() int {
+	return len(m.Accused) * party.IDByteSize
+}
+
+func (m *KeyGen3) Equal(other interface{}) bool {
+	otherMsg, ok := other.(*KeyGen3)
+	if !ok {
+		return false
+	}
+	return otherMsg.Accused.Equal(m.Accused)
+}
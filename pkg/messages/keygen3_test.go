@@ -0,0 +1,23 @@
+package messages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+)
+
+func TestKeyGen3_MarshalBinary(t *testing.T) {
+	from := party.ID(1)
+	accused := party.NewIDSlice([]party.ID{5, 2, 3})
+
+	msg := NewKeyGen3(from, accused)
+
+	var msg2 Message
+	require.NoError(t, CheckFROSTMarshaler(msg, &msg2))
+	assert.True(t, msg2.Equal(msg), "messages are not equal")
+
+	var duplicate KeyGen3
+	assert.Error(t, duplicate.UnmarshalBinary([]byte{0, 2, 0, 2}))
+}
@@ -0,0 +1,134 @@
+package messages
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+)
+
+const sizeKeygen4Entry = party.IDByteSize + 32
+
+// KeyGen4 is broadcast by every party in the complaint phase, after the complaints of KeyGen3.
+type KeyGen4 struct {
+	// Echo contains the digests of the KeyGen3 messages received by the sender (including its own),
+	// ordered by party.ID. It allows the destination to check that all parties received the same complaints.
+	Echo [][]byte
+
+	// Shares maps every party which complained about the sender to the share the sender sent to it, in the clear.
+	// It is empty if the sender was not accused.
+	Shares map[party.ID]*ristretto.Scalar
+}
+
+func NewKeyGen4(from party.ID, echo [][]byte, shares map[party.ID]*ristretto.Scalar) *Message {
+	return &Message{
+		Header: Header{
+			Type: MessageTypeKeyGen4,
+			From: from,
+		},
+		KeyGen4: &KeyGen4{
+			Echo:   echo,
+			Shares: shares,
+		},
+	}
+}
+
+func (m *KeyGen4) BytesAppend(existing []byte) ([]byte, error) {
+	existing = append(existing, party.ID(len(m.Echo)).Bytes()...)
+	for _, digest := range m.Echo {
+		if len(digest) != EchoDigestSize {
+			return nil, fmt.Errorf("msg4: echo digest should be %d bytes (got %d)", EchoDigestSize, len(digest))
+		}
+		existing = append(existing, digest...)
+	}
+
+	ids := make([]party.ID, 0, len(m.Shares))
+	for id := range m.Shares {
+		ids = append(ids, id)
+	}
+	for _, id := range party.NewIDSlice(ids) {
+		existing = append(existing, id.Bytes()...)
+		existing = append(existing, m.Shares[id].Bytes()...)
+	}
+	return existing, nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (m *KeyGen4) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, m.Size())
+	return m.BytesAppend(buf)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (m *KeyGen4) UnmarshalBinary(data []byte) error {
+	n, err := party.FromBytes(data)
+	if err != nil {
+		return fmt.Errorf("msg4: %w", err)
+	}
+	data = data[party.IDByteSize:]
+	if len(data) < int(n)*EchoDigestSize || (len(data)-int(n)*EchoDigestSize)%sizeKeygen4Entry != 0 {
+		return fmt.Errorf("msg4: %w", ErrInvalidMessage)
+	}
+
+	m.Echo = make([][]byte, 0, n)
+	for i := 0; i < int(n); i++ {
+		m.Echo = append(m.Echo, append([]byte{}, data[:EchoDigestSize]...))
+		data = data[EchoDigestSize:]
+	}
+
+	m.Shares = make(map[party.ID]*ristretto.Scalar, len(data)/sizeKeygen4Entry)
+	for ; len(data) > 0; data = data[sizeKeygen4Entry:] {
+		id, err := party.FromBytes(data)
+		if err != nil {
+			return err
+		}
+		if _, ok := m.Shares[id]; ok {
+			return fmt.Errorf("msg4: duplicate share: %w", ErrInvalidMessage)
+		}
+		var share ristretto.Scalar
+		if _, err = share.SetCanonicalBytes(data[party.IDByteSize:sizeKeygen4Entry]); err != nil {
+			return fmt.Errorf("msg4: %w", err)
+		}
+		m.Shares[id] = &share
+	}
+	return nil
+}
+
+func (m *KeyGen4) Size() int {
+	return party.IDByteSize + len(m.Echo)*EchoDigestSize + len(m.Shares)*sizeKeygen4Entry
+}
+
+// EchoBytes returns the concatenation of all digests in Echo.
+func (m *KeyGen4) EchoBytes() []byte {
+	out := make([]byte, 0, len(m.Echo)*EchoDigestSize)
+	for _, digest := range m.Echo {
+		out = append(out, digest...)
+	}
+	return out
+}
+
+func (m *KeyGen4) Equal(other interface{}) bool {
+	otherMsg, ok := other.(*KeyGen4)
+	if !ok {
+		return false
+	}
+	if len(otherMsg.Echo) != len(m.Echo) {
+		return false
+	}
+	for i := range m.Echo {
+		if !bytes.Equal(otherMsg.Echo[i], m.Echo[i]) {
+			return false
+		}
+	}
+	if len(otherMsg.Shares) != len(m.Shares) {
+		return false
+	}
+	for id, share := range m.Shares {
+		otherShare, ok := otherMsg.Shares[id]
+		if !ok || otherShare.Equal(share) != 1 {
+			return false
+		}
+	}
+	return true
+}
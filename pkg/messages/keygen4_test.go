@@ -0,0 +1,40 @@
+package messages
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/scalar"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+)
+
+func TestKeyGen4_MarshalBinary(t *testing.T) {
+	from := party.ID(1)
+	echo := make([][]byte, 3)
+	for i := range echo {
+		echo[i] = make([]byte, EchoDigestSize)
+		_, _ = rand.Read(echo[i])
+	}
+	shares := map[party.ID]*ristretto.Scalar{
+		2: scalar.NewScalarRandom(),
+		7: scalar.NewScalarRandom(),
+	}
+
+	msg := NewKeyGen4(from, echo, shares)
+
+	var msg2 Message
+	require.NoError(t, CheckFROSTMarshaler(msg, &msg2))
+	assert.True(t, msg2.Equal(msg), "messages are not equal")
+
+	// A party which was not accused only sends the echo
+	msg = NewKeyGen4(from, echo, nil)
+	require.NoError(t, CheckFROSTMarshaler(msg, &msg2))
+	assert.True(t, msg2.Equal(msg), "messages are not equal")
+
+	// The number of digests must match the length of the message
+	var truncated KeyGen4
+	assert.Error(t, truncated.UnmarshalBinary([]byte{0, 2, 0, 2}))
+}
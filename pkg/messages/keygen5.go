@@ -0,0 +1,84 @@
+package messages
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+)
+
+// KeyGen5 is broadcast by every party at the end of the complaint phase, when some party was accused.
+type KeyGen5 struct {
+	// Echo contains the digests of the KeyGen4 messages of the accused parties received by the sender,
+	// ordered by party.ID. It allows the destination to check that all parties received the same justifications.
+	Echo [][]byte
+}
+
+func NewKeyGen5(from party.ID, echo [][]byte) *Message {
+	return &Message{
+		Header: Header{
+			Type: MessageTypeKeyGen5,
+			From: from,
+		},
+		KeyGen5: &KeyGen5{
+			Echo: echo,
+		},
+	}
+}
+
+func (m *KeyGen5) BytesAppend(existing []byte) ([]byte, error) {
+	for _, digest := range m.Echo {
+		if len(digest) != EchoDigestSize {
+			return nil, fmt.Errorf("msg5: echo digest should be %d bytes (got %d)", EchoDigestSize, len(digest))
+		}
+		existing = append(existing, digest...)
+	}
+	return existing, nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (m *KeyGen5) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, m.Size())
+	return m.BytesAppend(buf)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (m *KeyGen5) UnmarshalBinary(data []byte) error {
+	if len(data)%EchoDigestSize != 0 {
+		return fmt.Errorf("msg5: %w", ErrInvalidMessage)
+	}
+	m.Echo = make([][]byte, 0, len(data)/EchoDigestSize)
+	for ; len(data) > 0; data = data[EchoDigestSize:] {
+		m.Echo = append(m.Echo, append([]byte{}, data[:EchoDigestSize]...))
+	}
+	return nil
+}
+
+func (m *KeyGen5) Size() int {
+	return len(m.Echo) * EchoDigestSize
+}
+
+// EchoBytes returns the concatenation of all digests in Echo.
+func (m *KeyGen5) EchoBytes() []byte {
+	out := make([]byte, 0, len(m.Echo)*EchoDigestSize)
+	for _, digest := range m.Echo {
+		out = append(out, digest...)
+	}
+	return out
+}
+
+func (m *KeyGen5) Equal(other interface{}) bool {
+	otherMsg, ok := other.(*KeyGen5)
+	if !ok {
+		return false
+	}
+	if len(otherMsg.Echo) != len(m.Echo) {
+		return false
+	}
+	for i := range m.Echo {
+		if !bytes.Equal(otherMsg.Echo[i], m.Echo[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,28 @@
+package messages
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+)
+
+func TestKeyGen5_MarshalBinary(t *testing.T) {
+	from := party.ID(1)
+	echo := make([][]byte, 2)
+	for i := range echo {
+		echo[i] = make([]byte, EchoDigestSize)
+		_, _ = rand.Read(echo[i])
+	}
+
+	msg := NewKeyGen5(from, echo)
+
+	var msg2 Message
+	require.NoError(t, CheckFROSTMarshaler(msg, &msg2))
+	assert.True(t, msg2.Equal(msg), "messages are not equal")
+
+	var invalid KeyGen5
+	assert.Error(t, invalid.UnmarshalBinary(make([]byte, EchoDigestSize+1)))
+}
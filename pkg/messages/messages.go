@@ -7,10 +7,24 @@ import (
 
 type Message struct {
 	Header
-	KeyGen1 *KeyGen1
-	KeyGen2 *KeyGen2
-	Sign1   *Sign1
-	Sign2   *Sign2
+	KeyGen1    *KeyGen1
+	KeyGen2    *KeyGen2
+	Sign1      *Sign1
+	Sign2      *Sign2
+	Preprocess *Preprocess
+	Refresh1   *Refresh1
+	Refresh2   *Refresh2
+	Reshare1   *Reshare1
+	Reshare2   *Reshare2
+	Repair1    *Repair1
+	Repair2    *Repair2
+	KeyGen3    *KeyGen3
+	KeyGen4    *KeyGen4
+	KeyGen5    *KeyGen5
+
+	SignCommitments *SignCommitments
+	MultiSign1      *MultiSign1
+	MultiSign2      *MultiSign2
 }
 
 var ErrInvalidMessage = errors.New("invalid message")
@@ -24,9 +38,24 @@ const (
 	MessageTypeKeyGen2
 	MessageTypeSign1
 	MessageTypeSign2
+	MessageTypePreprocess
+	MessageTypeRefresh1
+	MessageTypeRefresh2
+	MessageTypeReshare1
+	MessageTypeReshare2
+	MessageTypeRepair1
+	MessageTypeRepair2
+	MessageTypeKeyGen3
+	MessageTypeKeyGen4
+	MessageTypeSignCommitments
+	MessageTypeMultiSign1
+	MessageTypeMultiSign2
+	MessageTypeKeyGen5
 )
 
-func (m *Message) BytesAppend(existing []byte) (data []byte, err error) {
+// appendPayload appends the binary encoding of the message, without envelope.
+// This was the wire format of a message before the envelope was introduced.
+func (m *Message) appendPayload(existing []byte) (data []byte, err error) {
 	existing, err = m.Header.BytesAppend(existing)
 	if err != nil {
 		return nil, fmt.Errorf("message.BytesAppend: %w", err)
@@ -49,12 +78,65 @@ func (m *Message) BytesAppend(existing []byte) (data []byte, err error) {
 		if m.Sign2 != nil {
 			return m.Sign2.BytesAppend(existing)
 		}
+	case MessageTypePreprocess:
+		if m.Preprocess != nil {
+			return m.Preprocess.BytesAppend(existing)
+		}
+	case MessageTypeRefresh1:
+		if m.Refresh1 != nil {
+			return m.Refresh1.BytesAppend(existing)
+		}
+	case MessageTypeRefresh2:
+		if m.Refresh2 != nil {
+			return m.Refresh2.BytesAppend(existing)
+		}
+	case MessageTypeReshare1:
+		if m.Reshare1 != nil {
+			return m.Reshare1.BytesAppend(existing)
+		}
+	case MessageTypeReshare2:
+		if m.Reshare2 != nil {
+			return m.Reshare2.BytesAppend(existing)
+		}
+	case MessageTypeRepair1:
+		if m.Repair1 != nil {
+			return m.Repair1.BytesAppend(existing)
+		}
+	case MessageTypeRepair2:
+		if m.Repair2 != nil {
+			return m.Repair2.BytesAppend(existing)
+		}
+	case MessageTypeKeyGen3:
+		if m.KeyGen3 != nil {
+			return m.KeyGen3.BytesAppend(existing)
+		}
+	case MessageTypeKeyGen4:
+		if m.KeyGen4 != nil {
+			return m.KeyGen4.BytesAppend(existing)
+		}
+	case MessageTypeSignCommitments:
+		if m.SignCommitments != nil {
+			return m.SignCommitments.BytesAppend(existing)
+		}
+	case MessageTypeMultiSign1:
+		if m.MultiSign1 != nil {
+			return m.MultiSign1.BytesAppend(existing)
+		}
+	case MessageTypeMultiSign2:
+		if m.MultiSign2 != nil {
+			return m.MultiSign2.BytesAppend(existing)
+		}
+	case MessageTypeKeyGen5:
+		if m.KeyGen5 != nil {
+			return m.KeyGen5.BytesAppend(existing)
+		}
 	}
 
 	return nil, errors.New("message does not contain any data")
 }
 
-func (m *Message) Size() int {
+// payloadSize returns the size of the binary encoding of the message, without envelope.
+func (m *Message) payloadSize() int {
 	var size int
 	switch m.Type {
 	case MessageTypeKeyGen1:
@@ -73,18 +155,64 @@ func (m *Message) Size() int {
 		if m.Sign2 != nil {
 			size = m.Sign2.Size()
 		}
+	case MessageTypePreprocess:
+		if m.Preprocess != nil {
+			size = m.Preprocess.Size()
+		}
+	case MessageTypeRefresh1:
+		if m.Refresh1 != nil {
+			size = m.Refresh1.Size()
+		}
+	case MessageTypeRefresh2:
+		if m.Refresh2 != nil {
+			size = m.Refresh2.Size()
+		}
+	case MessageTypeReshare1:
+		if m.Reshare1 != nil {
+			size = m.Reshare1.Size()
+		}
+	case MessageTypeReshare2:
+		if m.Reshare2 != nil {
+			size = m.Reshare2.Size()
+		}
+	case MessageTypeRepair1:
+		if m.Repair1 != nil {
+			size = m.Repair1.Size()
+		}
+	case MessageTypeRepair2:
+		if m.Repair2 != nil {
+			size = m.Repair2.Size()
+		}
+	case MessageTypeKeyGen3:
+		if m.KeyGen3 != nil {
+			size = m.KeyGen3.Size()
+		}
+	case MessageTypeKeyGen4:
+		if m.KeyGen4 != nil {
+			size = m.KeyGen4.Size()
+		}
+	case MessageTypeSignCommitments:
+		if m.SignCommitments != nil {
+			size = m.SignCommitments.Size()
+		}
+	case MessageTypeMultiSign1:
+		if m.MultiSign1 != nil {
+			size = m.MultiSign1.Size()
+		}
+	case MessageTypeMultiSign2:
+		if m.MultiSign2 != nil {
+			size = m.MultiSign2.Size()
+		}
+	case MessageTypeKeyGen5:
+		if m.KeyGen5 != nil {
+			size = m.KeyGen5.Size()
+		}
 	}
 	return m.Header.Size() + size
 }
 
-// MarshalBinary implements the encoding.BinaryMarshaler interface.
-func (m *Message) MarshalBinary() ([]byte, error) {
-	buf := make([]byte, 0, m.Size())
-	return m.BytesAppend(buf)
-}
-
-// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
-func (m *Message) UnmarshalBinary(data []byte) error {
+// unmarshalPayload decodes the binary encoding of the message, without envelope.
+func (m *Message) unmarshalPayload(data []byte) error {
 	var err error
 
 	if err = m.Header.UnmarshalBinary(data); err != nil {
@@ -115,11 +243,76 @@ func (m *Message) UnmarshalBinary(data []byte) error {
 		if err = sign2.UnmarshalBinary(data); err == nil {
 			m.Sign2 = &sign2
 		}
+	case MessageTypePreprocess:
+		var preprocess Preprocess
+		if err = preprocess.UnmarshalBinary(data); err == nil {
+			m.Preprocess = &preprocess
+		}
+	case MessageTypeRefresh1:
+		var refresh1 Refresh1
+		if err = refresh1.UnmarshalBinary(data); err == nil {
+			m.Refresh1 = &refresh1
+		}
+	case MessageTypeRefresh2:
+		var refresh2 Refresh2
+		if err = refresh2.UnmarshalBinary(data); err == nil {
+			m.Refresh2 = &refresh2
+		}
+	case MessageTypeReshare1:
+		var reshare1 Reshare1
+		if err = reshare1.UnmarshalBinary(data); err == nil {
+			m.Reshare1 = &reshare1
+		}
+	case MessageTypeReshare2:
+		var reshare2 Reshare2
+		if err = reshare2.UnmarshalBinary(data); err == nil {
+			m.Reshare2 = &reshare2
+		}
+	case MessageTypeRepair1:
+		var repair1 Repair1
+		if err = repair1.UnmarshalBinary(data); err == nil {
+			m.Repair1 = &repair1
+		}
+	case MessageTypeRepair2:
+		var repair2 Repair2
+		if err = repair2.UnmarshalBinary(data); err == nil {
+			m.Repair2 = &repair2
+		}
+	case MessageTypeKeyGen3:
+		var keygen3 KeyGen3
+		if err = keygen3.UnmarshalBinary(data); err == nil {
+			m.KeyGen3 = &keygen3
+		}
+	case MessageTypeKeyGen4:
+		var keygen4 KeyGen4
+		if err = keygen4.UnmarshalBinary(data); err == nil {
+			m.KeyGen4 = &keygen4
+		}
+	case MessageTypeSignCommitments:
+		var signCommitments SignCommitments
+		if err = signCommitments.UnmarshalBinary(data); err == nil {
+			m.SignCommitments = &signCommitments
+		}
+	case MessageTypeMultiSign1:
+		var multiSign1 MultiSign1
+		if err = multiSign1.UnmarshalBinary(data); err == nil {
+			m.MultiSign1 = &multiSign1
+		}
+	case MessageTypeMultiSign2:
+		var multiSign2 MultiSign2
+		if err = multiSign2.UnmarshalBinary(data); err == nil {
+			m.MultiSign2 = &multiSign2
+		}
+	case MessageTypeKeyGen5:
+		var keygen5 KeyGen5
+		if err = keygen5.UnmarshalBinary(data); err == nil {
+			m.KeyGen5 = &keygen5
+		}
 	default:
 		return errors.New("messages.UnmarshalBinary: invalid message type")
 	}
 
-	return nil
+	return err
 }
 
 func (m *Message) Equal(other interface{}) bool {
@@ -149,6 +342,58 @@ func (m *Message) Equal(other interface{}) bool {
 		if m.Sign2 != nil && otherMsg.Sign2 != nil {
 			return m.Sign2.Equal(otherMsg.Sign2)
 		}
+	case MessageTypePreprocess:
+		if m.Preprocess != nil && otherMsg.Preprocess != nil {
+			return m.Preprocess.Equal(otherMsg.Preprocess)
+		}
+	case MessageTypeRefresh1:
+		if m.Refresh1 != nil && otherMsg.Refresh1 != nil {
+			return m.Refresh1.Equal(otherMsg.Refresh1)
+		}
+	case MessageTypeRefresh2:
+		if m.Refresh2 != nil && otherMsg.Refresh2 != nil {
+			return m.Refresh2.Equal(otherMsg.Refresh2)
+		}
+	case MessageTypeReshare1:
+		if m.Reshare1 != nil && otherMsg.Reshare1 != nil {
+			return m.Reshare1.Equal(otherMsg.Reshare1)
+		}
+	case MessageTypeReshare2:
+		if m.Reshare2 != nil && otherMsg.Reshare2 != nil {
+			return m.Reshare2.Equal(otherMsg.Reshare2)
+		}
+	case MessageTypeRepair1:
+		if m.Repair1 != nil && otherMsg.Repair1 != nil {
+			return m.Repair1.Equal(otherMsg.Repair1)
+		}
+	case MessageTypeRepair2:
+		if m.Repair2 != nil && otherMsg.Repair2 != nil {
+			return m.Repair2.Equal(otherMsg.Repair2)
+		}
+	case MessageTypeKeyGen3:
+		if m.KeyGen3 != nil && otherMsg.KeyGen3 != nil {
+			return m.KeyGen3.Equal(otherMsg.KeyGen3)
+		}
+	case MessageTypeKeyGen4:
+		if m.KeyGen4 != nil && otherMsg.KeyGen4 != nil {
+			return m.KeyGen4.Equal(otherMsg.KeyGen4)
+		}
+	case MessageTypeSignCommitments:
+		if m.SignCommitments != nil && otherMsg.SignCommitments != nil {
+			return m.SignCommitments.Equal(otherMsg.SignCommitments)
+		}
+	case MessageTypeMultiSign1:
+		if m.MultiSign1 != nil && otherMsg.MultiSign1 != nil {
+			return m.MultiSign1.Equal(otherMsg.MultiSign1)
+		}
+	case MessageTypeMultiSign2:
+		if m.MultiSign2 != nil && otherMsg.MultiSign2 != nil {
+			return m.MultiSign2.Equal(otherMsg.MultiSign2)
+		}
+	case MessageTypeKeyGen5:
+		if m.KeyGen5 != nil && otherMsg.KeyGen5 != nil {
+			return m.KeyGen5.Equal(otherMsg.KeyGen5)
+		}
 	}
 	return false
 }
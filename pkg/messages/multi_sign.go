@@ -0,0 +1,179 @@
+package messages
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+)
+
+const sizeMultiSignHeader = 2
+
+// MultiSign1 is the first message of a session which signs several messages at once.
+// It contains one pair of commitments for each message to be signed.
+type MultiSign1 struct {
+	// Commitments[j] holds the pair (Dᵢⱼ, Eᵢⱼ) of the sender for the j-th message
+	Commitments []Sign1
+}
+
+// MultiSign2 is the second message of a session which signs several messages at once.
+// It contains the share of the signature of each message.
+type MultiSign2 struct {
+	// Zi[j] is the sender's share of the 's' part of the signature of the j-th message
+	Zi []ristretto.Scalar
+}
+
+func NewMultiSign1(from party.ID, commitments []Sign1) *Message {
+	return &Message{
+		Header: Header{
+			Type: MessageTypeMultiSign1,
+			From: from,
+		},
+		MultiSign1: &MultiSign1{Commitments: commitments},
+	}
+}
+
+func NewMultiSign2(from party.ID, signatureShares []ristretto.Scalar) *Message {
+	return &Message{
+		Header: Header{
+			Type: MessageTypeMultiSign2,
+			From: from,
+		},
+		MultiSign2: &MultiSign2{Zi: signatureShares},
+	}
+}
+
+// readMultiSignCount returns the number of entries of size entrySize in data, and the remaining data.
+func readMultiSignCount(data []byte, entrySize int) (int, []byte, error) {
+	if len(data) < sizeMultiSignHeader {
+		return 0, nil, ErrInvalidMessage
+	}
+	count := int(binary.BigEndian.Uint16(data))
+	data = data[sizeMultiSignHeader:]
+	if count == 0 || len(data) != count*entrySize {
+		return 0, nil, ErrInvalidMessage
+	}
+	return count, data, nil
+}
+
+// appendMultiSignCount appends the number of entries n.
+func appendMultiSignCount(existing []byte, n int) ([]byte, error) {
+	if n > math.MaxUint16 {
+		return nil, errors.New("too many messages")
+	}
+	var header [sizeMultiSignHeader]byte
+	binary.BigEndian.PutUint16(header[:], uint16(n))
+	return append(existing, header[:]...), nil
+}
+
+func (m *MultiSign1) BytesAppend(existing []byte) ([]byte, error) {
+	existing, err := appendMultiSignCount(existing, len(m.Commitments))
+	if err != nil {
+		return nil, fmt.Errorf("multi sign1: %w", err)
+	}
+	for j := range m.Commitments {
+		existing = append(existing, m.Commitments[j].Di.Bytes()...)
+		existing = append(existing, m.Commitments[j].Ei.Bytes()...)
+	}
+	return existing, nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (m *MultiSign1) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, m.Size())
+	return m.BytesAppend(buf)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (m *MultiSign1) UnmarshalBinary(data []byte) error {
+	count, data, err := readMultiSignCount(data, sizeSign1)
+	if err != nil {
+		return fmt.Errorf("multi sign1: %w", err)
+	}
+	commitments := make([]Sign1, count)
+	for j := range commitments {
+		if err = commitments[j].UnmarshalBinary(data[:sizeSign1]); err != nil {
+			return fmt.Errorf("multi sign1: message %d: %w", j, err)
+		}
+		data = data[sizeSign1:]
+	}
+	m.Commitments = commitments
+	return nil
+}
+
+func (m *MultiSign1) Size() int {
+	return sizeMultiSignHeader + len(m.Commitments)*sizeSign1
+}
+
+func (m *MultiSign1) Equal(other interface{}) bool {
+	otherMsg, ok := other.(*MultiSign1)
+	if !ok {
+		return false
+	}
+	if len(otherMsg.Commitments) != len(m.Commitments) {
+		return false
+	}
+	for j := range m.Commitments {
+		if !otherMsg.Commitments[j].Equal(&m.Commitments[j]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *MultiSign2) BytesAppend(existing []byte) ([]byte, error) {
+	existing, err := appendMultiSignCount(existing, len(m.Zi))
+	if err != nil {
+		return nil, fmt.Errorf("multi sign2: %w", err)
+	}
+	for j := range m.Zi {
+		existing = append(existing, m.Zi[j].Bytes()...)
+	}
+	return existing, nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (m *MultiSign2) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, m.Size())
+	return m.BytesAppend(buf)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (m *MultiSign2) UnmarshalBinary(data []byte) error {
+	count, data, err := readMultiSignCount(data, sizeSign2)
+	if err != nil {
+		return fmt.Errorf("multi sign2: %w", err)
+	}
+	shares := make([]ristretto.Scalar, count)
+	for j := range shares {
+		if _, err = shares[j].SetCanonicalBytes(data[:sizeSign2]); err != nil {
+			return fmt.Errorf("multi sign2: message %d: %w", j, err)
+		}
+		data = data[sizeSign2:]
+	}
+	m.Zi = shares
+	return nil
+}
+
+func (m *MultiSign2) Size() int {
+	return sizeMultiSignHeader + len(m.Zi)*sizeSign2
+}
+
+func (m *MultiSign2) Equal(other interface{}) bool {
+	otherMsg, ok := other.(*MultiSign2)
+	if !ok {
+		return false
+	}
+	if len(otherMsg.Zi) != len(m.Zi) {
+		return false
+	}
+	for j := range m.Zi {
+		if otherMsg.Zi[j].Equal(&m.Zi[j]) != 1 {
+			return false
+		}
+	}
+	return true
+}
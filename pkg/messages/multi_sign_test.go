@@ -0,0 +1,46 @@
+package messages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/scalar"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+)
+
+func TestMultiSign1_MarshalBinary(t *testing.T) {
+	commitments := make([]Sign1, 5)
+	for j := range commitments {
+		commitments[j].Di.ScalarBaseMult(scalar.NewScalarRandom())
+		commitments[j].Ei.ScalarBaseMult(scalar.NewScalarRandom())
+	}
+	from := party.ID(42)
+
+	msg := NewMultiSign1(from, commitments)
+
+	var msgDec Message
+	require.NoError(t, CheckFROSTMarshaler(msg, &msgDec))
+	require.True(t, msg.Equal(&msgDec), "messages are not equal")
+}
+
+func TestMultiSign2_MarshalBinary(t *testing.T) {
+	shares := make([]ristretto.Scalar, 5)
+	for j := range shares {
+		scalar.SetScalarRandom(&shares[j])
+	}
+	from := party.ID(42)
+
+	msg := NewMultiSign2(from, shares)
+
+	var msgDec Message
+	require.NoError(t, CheckFROSTMarshaler(msg, &msgDec))
+	require.True(t, msg.Equal(&msgDec), "messages are not equal")
+
+	// The number of shares must match the length of the data
+	data, err := msg.MultiSign2.MarshalBinary()
+	require.NoError(t, err)
+	var decoded MultiSign2
+	require.Error(t, decoded.UnmarshalBinary(data[:len(data)-1]))
+	require.Error(t, decoded.UnmarshalBinary([]byte{0, 0}))
+}
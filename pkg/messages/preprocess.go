@@ -0,0 +1,107 @@
+package messages
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+)
+
+const sizePreprocessHeader = 4 + 2
+
+// Preprocess contains a batch of nonce commitments published ahead of time by a signer.
+// Each commitment pair can later be consumed by exactly one signing session.
+type Preprocess struct {
+	// Start is the index of the first commitment pair in the batch.
+	Start uint32
+
+	// Commitments holds the pairs (Dᵢⱼ, Eᵢⱼ) for j = Start, ..., Start + len(Commitments) - 1
+	Commitments []Sign1
+}
+
+func NewPreprocess(from party.ID, start uint32, commitmentsD, commitmentsE []ristretto.Element) *Message {
+	commitments := make([]Sign1, len(commitmentsD))
+	for j := range commitments {
+		commitments[j].Di.Set(&commitmentsD[j])
+		commitments[j].Ei.Set(&commitmentsE[j])
+	}
+	return &Message{
+		Header: Header{
+			Type: MessageTypePreprocess,
+			From: from,
+		},
+		Preprocess: &Preprocess{
+			Start:       start,
+			Commitments: commitments,
+		},
+	}
+}
+
+func (m *Preprocess) BytesAppend(existing []byte) ([]byte, error) {
+	if len(m.Commitments) > math.MaxUint16 {
+		return nil, errors.New("preprocess: too many commitments in batch")
+	}
+	var header [sizePreprocessHeader]byte
+	binary.BigEndian.PutUint32(header[:4], m.Start)
+	binary.BigEndian.PutUint16(header[4:], uint16(len(m.Commitments)))
+	existing = append(existing, header[:]...)
+	for j := range m.Commitments {
+		existing = append(existing, m.Commitments[j].Di.Bytes()...)
+		existing = append(existing, m.Commitments[j].Ei.Bytes()...)
+	}
+	return existing, nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (m *Preprocess) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, m.Size())
+	return m.BytesAppend(buf)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (m *Preprocess) UnmarshalBinary(data []byte) error {
+	if len(data) < sizePreprocessHeader {
+		return fmt.Errorf("preprocess: %w", ErrInvalidMessage)
+	}
+	start := binary.BigEndian.Uint32(data[:4])
+	count := int(binary.BigEndian.Uint16(data[4:]))
+	data = data[sizePreprocessHeader:]
+	if len(data) != count*sizeSign1 {
+		return fmt.Errorf("preprocess: %w", ErrInvalidMessage)
+	}
+
+	commitments := make([]Sign1, count)
+	for j := range commitments {
+		if err := commitments[j].UnmarshalBinary(data[:sizeSign1]); err != nil {
+			return fmt.Errorf("preprocess: commitment %d: %w", start+uint32(j), err)
+		}
+		data = data[sizeSign1:]
+	}
+
+	m.Start = start
+	m.Commitments = commitments
+	return nil
+}
+
+func (m *Preprocess) Size() int {
+	return sizePreprocessHeader + len(m.Commitments)*sizeSign1
+}
+
+func (m *Preprocess) Equal(other interface{}) bool {
+	otherMsg, ok := other.(*Preprocess)
+	if !ok {
+		return false
+	}
+	if otherMsg.Start != m.Start || len(otherMsg.Commitments) != len(m.Commitments) {
+		return false
+	}
+	for j := range m.Commitments {
+		if !otherMsg.Commitments[j].Equal(&m.Commitments[j]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,27 @@
+package messages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/scalar"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+)
+
+func TestPreprocess_MarshalBinary(t *testing.T) {
+	n := 10
+	D := make([]ristretto.Element, n)
+	E := make([]ristretto.Element, n)
+	for j := 0; j < n; j++ {
+		D[j].ScalarBaseMult(scalar.NewScalarRandom())
+		E[j].ScalarBaseMult(scalar.NewScalarRandom())
+	}
+	from := party.ID(42)
+
+	msg := NewPreprocess(from, 100, D, E)
+
+	var msgDec Message
+	require.NoError(t, CheckFROSTMarshaler(msg, &msgDec))
+	require.True(t, msg.Equal(&msgDec), "messages are not equal")
+}
@@ -0,0 +1,57 @@
+package messages
+
+import (
+	"fmt"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/polynomial"
+)
+
+type Refresh1 struct {
+	// Commitments to the coefficients of the refresh polynomial.
+	// The constant coefficient must be the identity.
+	Commitments *polynomial.Exponent
+}
+
+func NewRefresh1(from party.ID, commitments *polynomial.Exponent) *Message {
+	return &Message{
+		Header: Header{
+			Type: MessageTypeRefresh1,
+			From: from,
+		},
+		Refresh1: &Refresh1{
+			Commitments: commitments,
+		},
+	}
+}
+
+func (m *Refresh1) BytesAppend(existing []byte) ([]byte, error) {
+	return m.Commitments.BytesAppend(existing)
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (m *Refresh1) MarshalBinary() (data []byte, err error) {
+	buf := make([]byte, 0, m.Size())
+	return m.BytesAppend(buf)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (m *Refresh1) UnmarshalBinary(data []byte) error {
+	m.Commitments = &polynomial.Exponent{}
+	if err := m.Commitments.UnmarshalBinary(data); err != nil {
+		return fmt.Errorf("refresh1: %w", err)
+	}
+	return nil
+}
+
+func (m *Refresh1) Size() int {
+	return m.Commitments.Size()
+}
+
+func (m *Refresh1) Equal(other interface{}) bool {
+	otherMsg, ok := other.(*Refresh1)
+	if !ok {
+		return false
+	}
+	return otherMsg.Commitments.Equal(m.Commitments)
+}
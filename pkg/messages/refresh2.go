@@ -0,0 +1,57 @@
+package messages
+
+import (
+	"fmt"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+)
+
+const sizeRefresh2 = 32
+
+type Refresh2 struct {
+	// Share is the evaluation of the sender's refresh polynomial at the destination party's ID
+	Share ristretto.Scalar
+}
+
+func NewRefresh2(from, to party.ID, share *ristretto.Scalar) *Message {
+	return &Message{
+		Header: Header{
+			Type: MessageTypeRefresh2,
+			From: from,
+			To:   to,
+		},
+		Refresh2: &Refresh2{Share: *share},
+	}
+}
+
+func (m *Refresh2) BytesAppend(existing []byte) ([]byte, error) {
+	return append(existing, m.Share.Bytes()...), nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (m *Refresh2) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, sizeRefresh2)
+	return m.BytesAppend(buf)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (m *Refresh2) UnmarshalBinary(data []byte) error {
+	if len(data) != sizeRefresh2 {
+		return fmt.Errorf("refresh2: %w", ErrInvalidMessage)
+	}
+	_, err := m.Share.SetCanonicalBytes(data)
+	return err
+}
+
+func (m *Refresh2) Size() int {
+	return sizeRefresh2
+}
+
+func (m *Refresh2) Equal(other interface{}) bool {
+	otherMsg, ok := other.(*Refresh2)
+	if !ok {
+		return false
+	}
+	return otherMsg.Share.Equal(&m.Share) == 1
+}
@@ -0,0 +1,38 @@
+package messages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/polynomial"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/scalar"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+)
+
+func TestRefresh1_MarshalBinary(t *testing.T) {
+	from := party.RandID()
+	deg := party.Size(10)
+
+	poly := polynomial.NewPolynomial(deg, ristretto.NewScalar())
+	comm := polynomial.NewPolynomialExponent(poly)
+
+	msg := NewRefresh1(from, comm)
+
+	var msg2 Message
+	require.NoError(t, CheckFROSTMarshaler(msg, &msg2))
+	assert.True(t, msg2.Equal(msg), "messages are not equal")
+}
+
+func TestRefresh2_MarshalBinary(t *testing.T) {
+	from := party.ID(1)
+	to := party.ID(2)
+	share := scalar.NewScalarRandom()
+
+	msg := NewRefresh2(from, to, share)
+
+	var msg2 Message
+	require.NoError(t, CheckFROSTMarshaler(msg, &msg2))
+	assert.Equal(t, *msg, msg2, "messages are not equal")
+}
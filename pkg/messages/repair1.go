@@ -0,0 +1,57 @@
+package messages
+
+import (
+	"fmt"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+)
+
+const sizeRepair1 = 32
+
+type Repair1 struct {
+	// Share is one of the additive pieces of the sender's contribution to the lost share
+	Share ristretto.Scalar
+}
+
+func NewRepair1(from, to party.ID, share *ristretto.Scalar) *Message {
+	return &Message{
+		Header: Header{
+			Type: MessageTypeRepair1,
+			From: from,
+			To:   to,
+		},
+		Repair1: &Repair1{Share: *share},
+	}
+}
+
+func (m *Repair1) BytesAppend(existing []byte) ([]byte, error) {
+	return append(existing, m.Share.Bytes()...), nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (m *Repair1) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, sizeRepair1)
+	return m.BytesAppend(buf)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (m *Repair1) UnmarshalBinary(data []byte) error {
+	if len(data) != sizeRepair1 {
+		return fmt.Errorf("repair1: %w", ErrInvalidMessage)
+	}
+	_, err := m.Share.SetCanonicalBytes(data)
+	return err
+}
+
+func (m *Repair1) Size() int {
+	return sizeRepair1
+}
+
+func (m *Repair1) Equal(other interface{}) bool {
+	otherMsg, ok := other.(*Repair1)
+	if !ok {
+		return false
+	}
+	return otherMsg.Share.Equal(&m.Share) == 1
+}
@@ -0,0 +1,57 @@
+package messages
+
+import (
+	"fmt"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+)
+
+const sizeRepair2 = 32
+
+type Repair2 struct {
+	// Share is the sum of all pieces received by the sender, addressed to the party being repaired
+	Share ristretto.Scalar
+}
+
+func NewRepair2(from, to party.ID, share *ristretto.Scalar) *Message {
+	return &Message{
+		Header: Header{
+			Type: MessageTypeRepair2,
+			From: from,
+			To:   to,
+		},
+		Repair2: &Repair2{Share: *share},
+	}
+}
+
+func (m *Repair2) BytesAppend(existing []byte) ([]byte, error) {
+	return append(existing, m.Share.Bytes()...), nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (m *Repair2) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, sizeRepair2)
+	return m.BytesAppend(buf)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (m *Repair2) UnmarshalBinary(data []byte) error {
+	if len(data) != sizeRepair2 {
+		return fmt.Errorf("repair2: %w", ErrInvalidMessage)
+	}
+	_, err := m.Share.SetCanonicalBytes(data)
+	return err
+}
+
+func (m *Repair2) Size() int {
+	return sizeRepair2
+}
+
+func (m *Repair2) Equal(other interface{}) bool {
+	otherMsg, ok := other.(*Repair2)
+	if !ok {
+		return false
+	}
+	return otherMsg.Share.Equal(&m.Share) == 1
+}
@@ -0,0 +1,34 @@
+package messages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/scalar"
+)
+
+func TestRepair1_MarshalBinary(t *testing.T) {
+	from := party.ID(1)
+	to := party.ID(2)
+	share := scalar.NewScalarRandom()
+
+	msg := NewRepair1(from, to, share)
+
+	var msg2 Message
+	require.NoError(t, CheckFROSTMarshaler(msg, &msg2))
+	assert.Equal(t, *msg, msg2, "messages are not equal")
+}
+
+func TestRepair2_MarshalBinary(t *testing.T) {
+	from := party.ID(1)
+	to := party.ID(2)
+	share := scalar.NewScalarRandom()
+
+	msg := NewRepair2(from, to, share)
+
+	var msg2 Message
+	require.NoError(t, CheckFROSTMarshaler(msg, &msg2))
+	assert.Equal(t, *msg, msg2, "messages are not equal")
+}
@@ -0,0 +1,57 @@
+package messages
+
+import (
+	"fmt"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/polynomial"
+)
+
+type Reshare1 struct {
+	// Commitments to the coefficients of the dealer's resharing polynomial.
+	// The constant coefficient must be the dealer's Lagrange-weighted public share.
+	Commitments *polynomial.Exponent
+}
+
+func NewReshare1(from party.ID, commitments *polynomial.Exponent) *Message {
+	return &Message{
+		Header: Header{
+			Type: MessageTypeReshare1,
+			From: from,
+		},
+		Reshare1: &Reshare1{
+			Commitments: commitments,
+		},
+	}
+}
+
+func (m *Reshare1) BytesAppend(existing []byte) ([]byte, error) {
+	return m.Commitments.BytesAppend(existing)
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (m *Reshare1) MarshalBinary() (data []byte, err error) {
+	buf := make([]byte, 0, m.Size())
+	return m.BytesAppend(buf)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (m *Reshare1) UnmarshalBinary(data []byte) error {
+	m.Commitments = &polynomial.Exponent{}
+	if err := m.Commitments.UnmarshalBinary(data); err != nil {
+		return fmt.Errorf("reshare1: %w", err)
+	}
+	return nil
+}
+
+func (m *Reshare1) Size() int {
+	return m.Commitments.Size()
+}
+
+func (m *Reshare1) Equal(other interface{}) bool {
+	otherMsg, ok := other.(*Reshare1)
+	if !ok {
+		return false
+	}
+	return otherMsg.Commitments.Equal(m.Commitments)
+}
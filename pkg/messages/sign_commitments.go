@@ -0,0 +1,128 @@
+package messages
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+)
+
+const (
+	sizeSignCommitmentsHeader = 2
+	sizeSignCommitment        = party.IDByteSize + sizeSign1
+)
+
+// SignCommitments is sent by the coordinator of a signing session to each signer.
+// It contains the commitments (Dⱼ, Eⱼ) of all signers j, which the coordinator collected from their Sign1 messages.
+type SignCommitments struct {
+	// IDs of the signers, in increasing order
+	IDs party.IDSlice
+
+	// Commitments[k] holds the pair (Dⱼ, Eⱼ) of the signer j = IDs[k]
+	Commitments []Sign1
+}
+
+// NewSignCommitments returns the message sent by the coordinator from to the signer to.
+// The map commitments must contain the Sign1 message content of every signer.
+func NewSignCommitments(from, to party.ID, commitments map[party.ID]*Sign1) *Message {
+	ids := make([]party.ID, 0, len(commitments))
+	for id := range commitments {
+		ids = append(ids, id)
+	}
+	signCommitments := &SignCommitments{
+		IDs:         party.NewIDSlice(ids),
+		Commitments: make([]Sign1, len(ids)),
+	}
+	for k, id := range signCommitments.IDs {
+		signCommitments.Commitments[k].Di.Set(&commitments[id].Di)
+		signCommitments.Commitments[k].Ei.Set(&commitments[id].Ei)
+	}
+	return &Message{
+		Header: Header{
+			Type: MessageTypeSignCommitments,
+			From: from,
+			To:   to,
+		},
+		SignCommitments: signCommitments,
+	}
+}
+
+func (m *SignCommitments) BytesAppend(existing []byte) ([]byte, error) {
+	if len(m.IDs) != len(m.Commitments) {
+		return nil, errors.New("sign commitments: number of IDs and commitments differ")
+	}
+	if len(m.IDs) > math.MaxUint16 {
+		return nil, errors.New("sign commitments: too many signers")
+	}
+	var header [sizeSignCommitmentsHeader]byte
+	binary.BigEndian.PutUint16(header[:], uint16(len(m.IDs)))
+	existing = append(existing, header[:]...)
+	for k, id := range m.IDs {
+		existing = append(existing, id.Bytes()...)
+		existing = append(existing, m.Commitments[k].Di.Bytes()...)
+		existing = append(existing, m.Commitments[k].Ei.Bytes()...)
+	}
+	return existing, nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (m *SignCommitments) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, m.Size())
+	return m.BytesAppend(buf)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+// The IDs must be non zero and strictly increasing.
+func (m *SignCommitments) UnmarshalBinary(data []byte) error {
+	if len(data) < sizeSignCommitmentsHeader {
+		return fmt.Errorf("sign commitments: %w", ErrInvalidMessage)
+	}
+	count := int(binary.BigEndian.Uint16(data))
+	data = data[sizeSignCommitmentsHeader:]
+	if count == 0 || len(data) != count*sizeSignCommitment {
+		return fmt.Errorf("sign commitments: %w", ErrInvalidMessage)
+	}
+
+	ids := make(party.IDSlice, count)
+	commitments := make([]Sign1, count)
+	for k := range commitments {
+		id, err := party.FromBytes(data)
+		if err != nil {
+			return fmt.Errorf("sign commitments: %w", err)
+		}
+		if id == 0 || (k > 0 && id <= ids[k-1]) {
+			return fmt.Errorf("sign commitments: IDs must be non zero and increasing: %w", ErrInvalidMessage)
+		}
+		ids[k] = id
+		if err = commitments[k].UnmarshalBinary(data[party.IDByteSize:sizeSignCommitment]); err != nil {
+			return fmt.Errorf("sign commitments: party %d: %w", id, err)
+		}
+		data = data[sizeSignCommitment:]
+	}
+
+	m.IDs = ids
+	m.Commitments = commitments
+	return nil
+}
+
+func (m *SignCommitments) Size() int {
+	return sizeSignCommitmentsHeader + len(m.IDs)*sizeSignCommitment
+}
+
+func (m *SignCommitments) Equal(other interface{}) bool {
+	otherMsg, ok := other.(*SignCommitments)
+	if !ok {
+		return false
+	}
+	if !otherMsg.IDs.Equal(m.IDs) || len(otherMsg.Commitments) != len(m.Commitments) {
+		return false
+	}
+	for k := range m.Commitments {
+		if !otherMsg.Commitments[k].Equal(&m.Commitments[k]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,47 @@
+package messages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/scalar"
+)
+
+func TestSignCommitments_MarshalBinary(t *testing.T) {
+	commitments := map[party.ID]*Sign1{}
+	for _, id := range []party.ID{7, 3, 42} {
+		var c Sign1
+		c.Di.ScalarBaseMult(scalar.NewScalarRandom())
+		c.Ei.ScalarBaseMult(scalar.NewScalarRandom())
+		commitments[id] = &c
+	}
+
+	msg := NewSignCommitments(100, 3, commitments)
+	require.Equal(t, party.IDSlice{3, 7, 42}, msg.SignCommitments.IDs)
+
+	var msgDec Message
+	require.NoError(t, CheckFROSTMarshaler(msg, &msgDec))
+	require.True(t, msg.Equal(&msgDec), "messages are not equal")
+}
+
+func TestSignCommitments_UnmarshalBinary(t *testing.T) {
+	commitments := map[party.ID]*Sign1{}
+	for _, id := range []party.ID{1, 2} {
+		var c Sign1
+		c.Di.ScalarBaseMult(scalar.NewScalarRandom())
+		c.Ei.ScalarBaseMult(scalar.NewScalarRandom())
+		commitments[id] = &c
+	}
+	data, err := NewSignCommitments(100, 1, commitments).SignCommitments.MarshalBinary()
+	require.NoError(t, err)
+
+	// Swap the IDs so that they are no longer increasing
+	copy(data[2:], party.ID(2).Bytes())
+	copy(data[2+sizeSignCommitment:], party.ID(1).Bytes())
+	var decoded SignCommitments
+	require.Error(t, decoded.UnmarshalBinary(data))
+
+	// An empty list is not valid
+	require.Error(t, decoded.UnmarshalBinary([]byte{0, 0}))
+}
@@ -0,0 +1,268 @@
+// Package seal encrypts and authenticates secret data at rest,
+// such as eddsa.SecretShare and suspended state.State.
+//
+// A sealed blob has the following layout:
+//
+//	magic (2) ∥ version (1) ∥ kind (1) ∥ kdf (1) ∥ time (4) ∥ memory (4) ∥ threads (1) ∥ salt (16) ∥ counter (8) ∥ nonce (24) ∥ ciphertext
+//
+// The plaintext is encrypted with XChaCha20-Poly1305, and the whole header is given as associated data,
+// so that any modification of the blob is detected when opening it.
+// The encryption key is derived from a fresh random salt, either from a key-encryption key with HKDF-SHA256,
+// or from a passphrase with Argon2id, whose parameters (time, memory, threads) are stored in the header.
+// Since the header is only authenticated after the key was derived, a Sealer refuses to open blobs
+// whose Argon2 parameters exceed its own.
+//
+// The kind prevents a blob from being opened as another type of data.
+// The counter is chosen by the caller. It should come from a persistent counter kept for each key or State,
+// stored separately from the blobs, and increased every time a new version of the same data is sealed.
+// When opening, blobs with a counter lower than the expected one are refused,
+// which prevents an old state from being replayed, for example to reuse the nonces of a signing round.
+package seal
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/hkdf"
+)
+
+// Version is the version of the blobs produced by Seal.
+const Version uint8 = 1
+
+// KeySize is the size in bytes of a key-encryption key.
+const KeySize = 32
+
+const (
+	saltSize   = 16
+	headerSize = 2 + 1 + 1 + 1 + 4 + 4 + 1 + saltSize + 8 + chacha20poly1305.NonceSizeX
+)
+
+var (
+	magic = [2]byte{0xF7, 0x5E}
+
+	kdfDomainSeparation = []byte("FROST-Ed25519 seal")
+)
+
+// Kind identifies the type of data contained in a sealed blob.
+type Kind uint8
+
+const (
+	// KindSecretShare is a sealed eddsa.SecretShare, or any other long term secret key material.
+	KindSecretShare Kind = iota + 1
+
+	// KindState is a sealed state.State, or any other serialized protocol state.
+	KindState
+)
+
+type kdf uint8
+
+const (
+	kdfHKDF kdf = iota + 1
+	kdfArgon2id
+)
+
+// Argon2Params are the parameters of Argon2id used to derive keys from a passphrase.
+type Argon2Params struct {
+	// Time is the number of passes over the memory
+	Time uint32
+
+	// Memory is the size of the memory in KiB
+	Memory uint32
+
+	// Threads is the degree of parallelism
+	Threads uint8
+}
+
+// DefaultArgon2Params are the parameters recommended by RFC 9106 for memory constrained environments.
+var DefaultArgon2Params = Argon2Params{
+	Time:    3,
+	Memory:  64 * 1024,
+	Threads: 4,
+}
+
+// maxArgon2Params bounds the parameters of a Sealer, and therefore those accepted when opening a blob.
+var maxArgon2Params = Argon2Params{
+	Time:   16,
+	Memory: 1024 * 1024,
+}
+
+var (
+	// ErrInvalidBlob is returned when the data is not a sealed blob.
+	ErrInvalidBlob = errors.New("seal: invalid blob")
+
+	// ErrWrongKind is returned when a blob does not contain the expected kind of data.
+	ErrWrongKind = errors.New("seal: blob contains another kind of data")
+
+	// ErrAuthentication is returned when a blob was modified, or sealed with another key.
+	ErrAuthentication = errors.New("seal: authentication failed")
+
+	// ErrReplayed is returned when the counter of a blob is lower than expected.
+	ErrReplayed = errors.New("seal: blob was replaced by an older version")
+)
+
+// A Sealer seals and opens blobs with a key-encryption key or a passphrase.
+type Sealer struct {
+	kdf    kdf
+	secret []byte
+	argon2 Argon2Params
+}
+
+// NewSealer returns a Sealer which derives the encryption keys from a KeySize bytes key-encryption key.
+func NewSealer(key []byte) (*Sealer, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("seal: key should be %d bytes (got %d)", KeySize, len(key))
+	}
+	return &Sealer{
+		kdf:    kdfHKDF,
+		secret: append([]byte{}, key...),
+	}, nil
+}
+
+// NewPassphraseSealer returns a Sealer which derives the encryption keys from a passphrase with Argon2id,
+// using DefaultArgon2Params.
+func NewPassphraseSealer(passphrase []byte) *Sealer {
+	s, _ := NewPassphraseSealerWithParams(passphrase, DefaultArgon2Params)
+	return s
+}
+
+// NewPassphraseSealerWithParams returns a Sealer which derives the encryption keys from a passphrase with Argon2id.
+// The parameters used for a blob are read from its header when opening it, and must not exceed params,
+// so that a modified header cannot make opening more expensive than sealing.
+func NewPassphraseSealerWithParams(passphrase []byte, params Argon2Params) (*Sealer, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	return &Sealer{
+		kdf:    kdfArgon2id,
+		secret: append([]byte{}, passphrase...),
+		argon2: params,
+	}, nil
+}
+
+// Reset erases the key-encryption key or passphrase from memory.
+func (s *Sealer) Reset() {
+	for i := range s.secret {
+		s.secret[i] = 0
+	}
+}
+
+// Seal encrypts plaintext in a blob of the given kind.
+// The counter is authenticated along with the plaintext, and checked by Open.
+func (s *Sealer) Seal(kind Kind, counter uint64, plaintext []byte) ([]byte, error) {
+	header := make([]byte, headerSize)
+	copy(header, magic[:])
+	header[2] = Version
+	header[3] = byte(kind)
+	header[4] = byte(s.kdf)
+	binary.BigEndian.PutUint32(header[5:], s.argon2.Time)
+	binary.BigEndian.PutUint32(header[9:], s.argon2.Memory)
+	header[13] = s.argon2.Threads
+	salt := header[14 : 14+saltSize]
+	binary.BigEndian.PutUint64(header[14+saltSize:], counter)
+	nonce := header[22+saltSize:]
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	key, err := s.deriveKey(salt, s.argon2)
+	if err != nil {
+		return nil, err
+	}
+	defer zero(key)
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(header, nonce, plaintext, header), nil
+}
+
+// Open decrypts a blob of the given kind, and returns its content and counter.
+// It returns ErrReplayed if the blob's counter is lower than minCounter.
+func (s *Sealer) Open(kind Kind, data []byte, minCounter uint64) (plaintext []byte, counter uint64, err error) {
+	if len(data) < headerSize+chacha20poly1305.Overhead || data[0] != magic[0] || data[1] != magic[1] {
+		return nil, 0, ErrInvalidBlob
+	}
+	if data[2] != Version {
+		return nil, 0, fmt.Errorf("seal: unsupported version %d", data[2])
+	}
+	if Kind(data[3]) != kind {
+		return nil, 0, ErrWrongKind
+	}
+	if kdf(data[4]) != s.kdf {
+		return nil, 0, ErrAuthentication
+	}
+	params := Argon2Params{
+		Time:    binary.BigEndian.Uint32(data[5:]),
+		Memory:  binary.BigEndian.Uint32(data[9:]),
+		Threads: data[13],
+	}
+	if s.kdf == kdfArgon2id {
+		if err = params.validate(); err != nil {
+			return nil, 0, err
+		}
+		if params.Time > s.argon2.Time || params.Memory > s.argon2.Memory || params.Threads > s.argon2.Threads {
+			return nil, 0, fmt.Errorf("seal: Argon2 parameters %+v of the blob exceed those of the Sealer", params)
+		}
+	}
+	salt := data[14 : 14+saltSize]
+	counter = binary.BigEndian.Uint64(data[14+saltSize:])
+	nonce := data[22+saltSize : headerSize]
+
+	key, err := s.deriveKey(salt, params)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer zero(key)
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, 0, err
+	}
+	plaintext, err = aead.Open(nil, nonce, data[headerSize:], data[:headerSize])
+	if err != nil {
+		return nil, 0, ErrAuthentication
+	}
+
+	// The counter is only checked once the blob is authenticated, since it could otherwise have been modified
+	if counter < minCounter {
+		zero(plaintext)
+		return nil, 0, ErrReplayed
+	}
+	return plaintext, counter, nil
+}
+
+func (s *Sealer) deriveKey(salt []byte, params Argon2Params) ([]byte, error) {
+	switch s.kdf {
+	case kdfHKDF:
+		key := make([]byte, chacha20poly1305.KeySize)
+		if _, err := io.ReadFull(hkdf.New(sha256.New, s.secret, salt, kdfDomainSeparation), key); err != nil {
+			return nil, err
+		}
+		return key, nil
+	case kdfArgon2id:
+		return argon2.IDKey(s.secret, salt, params.Time, params.Memory, params.Threads, chacha20poly1305.KeySize), nil
+	}
+	return nil, errors.New("seal: Sealer was not initialized")
+}
+
+func (params Argon2Params) validate() error {
+	if params.Time == 0 || params.Memory == 0 || params.Threads == 0 ||
+		params.Time > maxArgon2Params.Time || params.Memory > maxArgon2Params.Memory {
+		return fmt.Errorf("seal: invalid Argon2 parameters %+v", params)
+	}
+	return nil
+}
+
+func zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,193 @@
+package seal
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/internal/scalar"
+)
+
+// testArgon2Params are cheap parameters, so that the tests run quickly.
+var testArgon2Params = Argon2Params{
+	Time:    1,
+	Memory:  64,
+	Threads: 1,
+}
+
+func newTestSealers(t *testing.T) map[string]*Sealer {
+	key := make([]byte, KeySize)
+	_, _ = rand.Read(key)
+	keySealer, err := NewSealer(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	passphraseSealer, err := NewPassphraseSealerWithParams([]byte("correct horse battery staple"), testArgon2Params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return map[string]*Sealer{
+		"key":        keySealer,
+		"passphrase": passphraseSealer,
+	}
+}
+
+func TestSeal(t *testing.T) {
+	plaintext := []byte("secret nonces")
+	for name, s := range newTestSealers(t) {
+		data, err := s.Seal(KindState, 3, plaintext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Contains(data, plaintext) {
+			t.Errorf("%s: blob contains the plaintext", name)
+		}
+
+		for _, minCounter := range []uint64{0, 3} {
+			opened, counter, err := s.Open(KindState, data, minCounter)
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			if !bytes.Equal(opened, plaintext) || counter != 3 {
+				t.Errorf("%s: opened blob differs", name)
+			}
+		}
+
+		if _, _, err = s.Open(KindState, data, 4); !errors.Is(err, ErrReplayed) {
+			t.Errorf("%s: expected ErrReplayed, got %v", name, err)
+		}
+		if _, _, err = s.Open(KindSecretShare, data, 0); !errors.Is(err, ErrWrongKind) {
+			t.Errorf("%s: expected ErrWrongKind, got %v", name, err)
+		}
+		if _, _, err = s.Open(KindState, data[:headerSize], 0); !errors.Is(err, ErrInvalidBlob) {
+			t.Errorf("%s: expected ErrInvalidBlob, got %v", name, err)
+		}
+
+		// Modifying any byte after the kind must be detected
+		for i := 4; i < len(data); i++ {
+			tampered := append([]byte{}, data...)
+			tampered[i] ^= 1
+			if _, _, err = s.Open(KindState, tampered, 0); err == nil {
+				t.Errorf("%s: opened a blob modified at byte %d", name, i)
+			}
+		}
+	}
+}
+
+func TestSealWrongKey(t *testing.T) {
+	sealers := newTestSealers(t)
+	data, err := sealers["key"].Seal(KindState, 0, []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = sealers["passphrase"].Open(KindState, data, 0); !errors.Is(err, ErrAuthentication) {
+		t.Errorf("expected ErrAuthentication, got %v", err)
+	}
+
+	other, _ := NewPassphraseSealerWithParams([]byte("wrong"), testArgon2Params)
+	data, err = sealers["passphrase"].Seal(KindState, 0, []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = other.Open(KindState, data, 0); !errors.Is(err, ErrAuthentication) {
+		t.Errorf("expected ErrAuthentication, got %v", err)
+	}
+}
+
+func TestSealSecretShare(t *testing.T) {
+	s := newTestSealers(t)["key"]
+	share := eddsa.NewSecretShare(42, scalar.NewScalarRandom())
+	data, err := s.SealSecretShare(share, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opened, counter, err := s.OpenSecretShare(data, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !opened.Equal(share) || counter != 1 {
+		t.Error("opened share differs")
+	}
+	if _, _, err = s.OpenState(data, 0); !errors.Is(err, ErrWrongKind) {
+		t.Errorf("expected ErrWrongKind, got %v", err)
+	}
+}
+
+func TestSealState(t *testing.T) {
+	s := newTestSealers(t)["passphrase"]
+	partyIDs := helpers.GenerateSet(3)
+	st, _, err := frost.NewKeygenState(partyIDs[0], partyIDs, 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old, err := s.SealState(st, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = helpers.PartyRoutine(nil, st); err != nil {
+		t.Fatal(err)
+	}
+	current, err := s.SealState(st, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resumed, counter, err := s.OpenState(current, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counter != 1 || resumed.GetRoundNumber() != st.GetRoundNumber() {
+		t.Error("resumed state differs")
+	}
+	if _, _, err = s.OpenState(old, 1); !errors.Is(err, ErrReplayed) {
+		t.Errorf("expected ErrReplayed, got %v", err)
+	}
+}
+
+func TestSealArgon2Params(t *testing.T) {
+	passphrase := []byte("correct horse battery staple")
+	strong, err := NewPassphraseSealerWithParams(passphrase, Argon2Params{Time: 2, Memory: 128, Threads: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	weak, err := NewPassphraseSealerWithParams(passphrase, testArgon2Params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Blobs sealed with weaker parameters can be opened
+	data, err := weak.Seal(KindState, 0, []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = strong.Open(KindState, data, 0); err != nil {
+		t.Error(err)
+	}
+
+	// Blobs requiring more work than our own parameters are refused
+	data, err = strong.Seal(KindState, 0, []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = weak.Open(KindState, data, 0); err == nil || errors.Is(err, ErrAuthentication) {
+		t.Errorf("expected the parameters to be refused, got %v", err)
+	}
+
+	// A modified header cannot force the maximum parameters before the blob is authenticated
+	binary.BigEndian.PutUint32(data[5:], maxArgon2Params.Time)
+	binary.BigEndian.PutUint32(data[9:], maxArgon2Params.Memory)
+	start := time.Now()
+	if _, _, err = strong.Open(KindState, data, 0); err == nil || errors.Is(err, ErrAuthentication) {
+		t.Errorf("expected the parameters to be refused, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("refusing the parameters took %v", elapsed)
+	}
+}
@@ -0,0 +1,57 @@
+package seal
+
+import (
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+// SealSecretShare seals a SecretShare with the given counter.
+func (s *Sealer) SealSecretShare(share *eddsa.SecretShare, counter uint64) ([]byte, error) {
+	data, err := share.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	defer zero(data)
+	return s.Seal(KindSecretShare, counter, data)
+}
+
+// OpenSecretShare opens a SecretShare sealed with a counter at least equal to minCounter.
+func (s *Sealer) OpenSecretShare(data []byte, minCounter uint64) (*eddsa.SecretShare, uint64, error) {
+	plaintext, counter, err := s.Open(KindSecretShare, data, minCounter)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer zero(plaintext)
+	var share eddsa.SecretShare
+	if err = share.UnmarshalBinary(plaintext); err != nil {
+		return nil, 0, err
+	}
+	return &share, counter, nil
+}
+
+// SealState suspends a State and seals it with the given counter.
+// The counter should come from a persistent counter kept for the State, increased every time it is sealed,
+// so that an older version cannot be opened in its place.
+// The round number is not suitable, since a State resumed from an older blob would be sealed again with the same numbers.
+func (s *Sealer) SealState(st *state.State, counter uint64) ([]byte, error) {
+	data, err := st.Suspend()
+	if err != nil {
+		return nil, err
+	}
+	defer zero(data)
+	return s.Seal(KindState, counter, data)
+}
+
+// OpenState opens and resumes a State sealed with a counter at least equal to minCounter.
+func (s *Sealer) OpenState(data []byte, minCounter uint64) (*state.State, uint64, error) {
+	plaintext, counter, err := s.Open(KindState, data, minCounter)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer zero(plaintext)
+	st, err := state.Resume(plaintext)
+	if err != nil {
+		return nil, 0, err
+	}
+	return st, counter, nil
+}
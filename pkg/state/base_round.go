@@ -9,8 +9,9 @@ import (
 )
 
 type BaseRound struct {
-	selfID   party.ID
-	partyIDs party.IDSlice
+	selfID    party.ID
+	partyIDs  party.IDSlice
+	sessionID messages.SessionID
 }
 
 func NewBaseRound(selfID party.ID, partyIDs party.IDSlice) (*BaseRound, error) {
@@ -35,9 +36,18 @@ func (r BaseRound) PartyIDs() party.IDSlice {
 	return r.partyIDs
 }
 
+func (r BaseRound) SessionID() messages.SessionID {
+	return r.sessionID
+}
+
+func (r *BaseRound) SetSessionID(sessionID messages.SessionID) {
+	r.sessionID = sessionID
+}
+
 type baseRoundJSON struct {
-	SelfID   uint16   `json:"selfID"`
-	PartyIDs []uint16 `json:"partIDs"`
+	SelfID    uint16             `json:"selfID"`
+	PartyIDs  []uint16           `json:"partIDs"`
+	SessionID messages.SessionID `json:"sessionID"`
 }
 
 func (r *BaseRound) MarshalJSON() ([]byte, error) {
@@ -48,6 +58,7 @@ func (r *BaseRound) MarshalJSON() ([]byte, error) {
 	return json.Marshal(baseRoundJSON{
 		uint16(r.selfID),
 		data,
+		r.sessionID,
 	})
 }
 
@@ -66,6 +77,7 @@ func (r *BaseRound) UnmarshalJSON(data []byte) error {
 
 	r.selfID = party.ID(rawjson.SelfID)
 	r.partyIDs = slice
+	r.sessionID = rawjson.SessionID
 
 	return nil
 }
@@ -1,17 +1,27 @@
 package state
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
 )
 
+// ErrTimeout is the reason of the Error reported when the messages of a round were not received in time.
+var ErrTimeout = errors.New("message timeout")
+
 // Error represents an error related to the protocol execution, and requires an abort.
 // If PartyID is 0, then it was not possible to attribute the fault to one particular party.
 type Error struct {
 	PartyID     party.ID
 	RoundNumber int
 	err         error
+
+	// Message is the marshalled message which caused the error, if any.
+	Message []byte
 }
 
 // NewError wraps err in an Error and attaches the culprit's ID
@@ -26,3 +36,125 @@ func NewError(partyID party.ID, err error) *Error {
 func (e Error) Error() string {
 	return fmt.Sprintf("party %d: round %d: %s", e.PartyID, e.RoundNumber, e.err.Error())
 }
+
+// Unwrap returns the reason for the error.
+func (e Error) Unwrap() error {
+	return e.err
+}
+
+// Blame is the error reported by a State running in identifiable abort mode.
+// It contains an Error for every party that was detected as misbehaving in the round where the abort occurred.
+type Blame struct {
+	// Culprits is sorted by party.ID
+	Culprits []*Error
+}
+
+func newBlame(culprits []*Error) *Blame {
+	sort.Slice(culprits, func(i, j int) bool { return culprits[i].PartyID < culprits[j].PartyID })
+	return &Blame{Culprits: culprits}
+}
+
+// Error implement error
+func (b *Blame) Error() string {
+	errs := make([]string, 0, len(b.Culprits))
+	for _, culprit := range b.Culprits {
+		errs = append(errs, culprit.Error())
+	}
+	return "blame: " + strings.Join(errs, "; ")
+}
+
+// PartyIDs returns the IDs of all blamed parties.
+func (b *Blame) PartyIDs() party.IDSlice {
+	ids := make([]party.ID, 0, len(b.Culprits))
+	for _, culprit := range b.Culprits {
+		ids = append(ids, culprit.PartyID)
+	}
+	return party.NewIDSlice(ids)
+}
+
+// Honest returns the parties in partyIDs which were not blamed.
+// It returns an error if one of the faults could not be attributed to a particular party.
+func (b *Blame) Honest(partyIDs party.IDSlice) (party.IDSlice, error) {
+	culprits := b.PartyIDs()
+	if culprits.Contains(0) {
+		return nil, errors.New("Blame: contains an error which could not be attributed")
+	}
+	honest := make([]party.ID, 0, len(partyIDs))
+	for _, id := range partyIDs {
+		if !culprits.Contains(id) {
+			honest = append(honest, id)
+		}
+	}
+	return party.NewIDSlice(honest), nil
+}
+
+type culpritJSON struct {
+	PartyID     party.ID `json:"party"`
+	RoundNumber int      `json:"round"`
+	Reason      string   `json:"reason"`
+	// Kind is the name of the registered error wrapped by Reason, if any.
+	Kind    string `json:"kind,omitempty"`
+	Message []byte `json:"message,omitempty"`
+}
+
+func newCulpritJSON(e *Error) culpritJSON {
+	return culpritJSON{
+		PartyID:     e.PartyID,
+		RoundNumber: e.RoundNumber,
+		Reason:      e.err.Error(),
+		Kind:        errorName(e.err),
+		Message:     e.Message,
+	}
+}
+
+// toError restores the Error. If the kind of error is not registered in this process,
+// only the reason is kept.
+func (c culpritJSON) toError() *Error {
+	var err error = errors.New(c.Reason)
+	if kind := registeredError(c.Kind); kind != nil {
+		err = &restoredError{reason: c.Reason, kind: kind}
+	}
+	return &Error{
+		PartyID:     c.PartyID,
+		RoundNumber: c.RoundNumber,
+		err:         err,
+		Message:     c.Message,
+	}
+}
+
+// restoredError is the reason of an unmarshalled Error, which wraps the registered error
+// of the original reason so that errors.Is still works.
+type restoredError struct {
+	reason string
+	kind   error
+}
+
+func (e *restoredError) Error() string {
+	return e.reason
+}
+
+func (e *restoredError) Unwrap() error {
+	return e.kind
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (b *Blame) MarshalJSON() ([]byte, error) {
+	culprits := make([]culpritJSON, 0, len(b.Culprits))
+	for _, culprit := range b.Culprits {
+		culprits = append(culprits, newCulpritJSON(culprit))
+	}
+	return json.Marshal(culprits)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (b *Blame) UnmarshalJSON(data []byte) error {
+	var culprits []culpritJSON
+	if err := json.Unmarshal(data, &culprits); err != nil {
+		return err
+	}
+	b.Culprits = make([]*Error, 0, len(culprits))
+	for _, culprit := range culprits {
+		b.Culprits = append(b.Culprits, culprit.toError())
+	}
+	return nil
+}
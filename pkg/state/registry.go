@@ -0,0 +1,120 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// The registry maps the names of round types to constructors, so that a suspended State can be resumed
+// without knowing in advance which protocol or round it was in.
+// Each protocol registers its rounds in an init function, and its round types must implement
+// json.Marshaler and json.Unmarshaler.
+var registry = struct {
+	sync.RWMutex
+	constructors map[string]func() Round
+	names        map[reflect.Type]string
+}{
+	constructors: map[string]func() Round{},
+	names:        map[reflect.Type]string{},
+}
+
+// RegisterRound registers the round type returned by newRound under the given name.
+// newRound must return a new zero value of the type, in which the round can be unmarshalled.
+// It panics if the name or the type is already registered.
+func RegisterRound(name string, newRound func() Round) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	t := reflect.TypeOf(newRound())
+	if _, ok := registry.constructors[name]; ok {
+		panic(fmt.Sprintf("state: round %q registered twice", name))
+	}
+	if other, ok := registry.names[t]; ok {
+		panic(fmt.Sprintf("state: round type %s already registered as %q", t, other))
+	}
+	registry.constructors[name] = newRound
+	registry.names[t] = name
+}
+
+// roundName returns the name under which the type of round was registered.
+func roundName(round Round) (string, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	name, ok := registry.names[reflect.TypeOf(round)]
+	if !ok {
+		return "", fmt.Errorf("state: round type %T is not registered", round)
+	}
+	return name, nil
+}
+
+// newRound returns a new zero value of the round type registered under name.
+func newRound(name string) (Round, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	constructor, ok := registry.constructors[name]
+	if !ok {
+		return nil, fmt.Errorf("state: unknown round type %q", name)
+	}
+	return constructor(), nil
+}
+
+// The error registry maps names to the errors which can be the reason of an Error,
+// so that errors.Is still recognizes them after a State was suspended and resumed.
+var errorRegistry = struct {
+	sync.RWMutex
+	errors map[string]error
+	names  map[error]string
+}{
+	errors: map[string]error{},
+	names:  map[error]string{},
+}
+
+func init() {
+	RegisterError("state.ErrTimeout", ErrTimeout)
+}
+
+// RegisterError registers err under the given name. err should be a sentinel error returned
+// by the rounds of a protocol, usually created with errors.New.
+// It panics if the name or the error is already registered.
+func RegisterError(name string, err error) {
+	errorRegistry.Lock()
+	defer errorRegistry.Unlock()
+
+	if _, ok := errorRegistry.errors[name]; ok {
+		panic(fmt.Sprintf("state: error %q registered twice", name))
+	}
+	if other, ok := errorRegistry.names[err]; ok {
+		panic(fmt.Sprintf("state: error %v already registered as %q", err, other))
+	}
+	errorRegistry.errors[name] = err
+	errorRegistry.names[err] = name
+}
+
+// errorName returns the name of the first registered error in the chain of err, or "" if there is none.
+func errorName(err error) string {
+	errorRegistry.RLock()
+	defer errorRegistry.RUnlock()
+
+	for ; err != nil; err = errors.Unwrap(err) {
+		// errors with a non comparable type can not be registered
+		if !reflect.TypeOf(err).Comparable() {
+			continue
+		}
+		if name, ok := errorRegistry.names[err]; ok {
+			return name
+		}
+	}
+	return ""
+}
+
+// registeredError returns the error registered under name, or nil.
+func registeredError(name string) error {
+	errorRegistry.RLock()
+	defer errorRegistry.RUnlock()
+
+	return errorRegistry.errors[name]
+}
@@ -72,5 +72,35 @@ type Round interface {
 	// PartyIDs returns a set containing all parties participating in the round
 	PartyIDs() party.IDSlice
 
+	// SessionID returns the identifier of the protocol execution.
+	// It should be bound to any proof or hash which could otherwise be replayed in another execution.
+	SessionID() messages.SessionID
+
+	// SetSessionID sets the identifier of the protocol execution.
+	SetSessionID(sessionID messages.SessionID)
+
 	GetOutput() interface{}
 }
+
+// ExpectedSenders can be implemented by a Round which only requires messages from a subset of the parties.
+// By default, a message is required from every party in PartyIDs, except the party itself.
+type ExpectedSenders interface {
+	// ExpectedSenders returns the parties which must send a message in the current round.
+	ExpectedSenders() party.IDSlice
+}
+
+// TimeoutHandler can be implemented by a Round which can be completed without the messages of some parties.
+// By default, the protocol aborts when the timeout expires.
+type TimeoutHandler interface {
+	// ProcessTimeout is called when the timeout expires before the messages of the parties in missing were received.
+	// It returns true if the round no longer expects messages from these parties, in which case the round
+	// is completed by the next call to ProcessAll. Otherwise, the protocol aborts.
+	ProcessTimeout(missing party.IDSlice) bool
+}
+
+// BatchProcessor can be implemented by a Round which validates the messages of a round more efficiently all at once.
+type BatchProcessor interface {
+	// ProcessMessages is called instead of ProcessMessage, with all messages received in the current round sorted by sender.
+	// It returns an Error attributed to the sender of every invalid message.
+	ProcessMessages(msgs []*messages.Message) []*Error
+}
@@ -30,6 +30,14 @@ type State struct {
 	done     bool
 	err      *Error
 
+	// proceedChan receives a value when the current round can be completed after a timeout
+	proceedChan chan struct{}
+
+	// identifiable indicates that all messages of a round should be processed before aborting,
+	// so that every misbehaving party can be identified.
+	identifiable bool
+	blame        *Blame
+
 	mtx sync.Mutex
 
 	RoundData []byte
@@ -43,11 +51,12 @@ func NewBaseState(round Round, timeout time.Duration) (*State, error) {
 		queue:            make([]*messages.Message, 0, N),
 		round:            round,
 		doneChan:         make(chan struct{}),
+		proceedChan:      make(chan struct{}, 1),
 	}
 
 	s.timer = newTimer(timeout, func() {
 		s.mtx.Lock()
-		s.reportError(NewError(0, errors.New("message timeout")))
+		s.reportTimeout()
 		s.mtx.Unlock()
 	})
 
@@ -72,6 +81,7 @@ func (s *State) wrapError(err error, culprit party.ID) error {
 // - Is the protocol already done
 // - Is msg is valid for this round or a future one
 // - Is msg for us and not from us
+// - Does msg belong to our session
 // - Is the sender a party in the protocol
 // - Have we already received a message from the party for this round?
 //
@@ -83,7 +93,6 @@ func (s *State) wrapError(err error, culprit party.ID) error {
 func (s *State) HandleMessage(msg *messages.Message) error {
 	senderID := msg.From
 
-	fmt.Println("handleMsg.....", senderID, s.round.SelfID(), s.roundNumber)
 	s.mtx.Lock()
 	defer s.mtx.Unlock()
 
@@ -104,26 +113,35 @@ func (s *State) HandleMessage(msg *messages.Message) error {
 	if !msg.IsBroadcast() && msg.To != s.round.SelfID() {
 		return nil
 	}
+	// Ignore messages from other sessions, which may be replayed
+	if msg.SessionID != s.round.SessionID() {
+		return s.wrapError(errors.New("message belongs to a different session"), senderID)
+	}
+
 	// Is the sender in our list of participants?
 	if !s.round.PartyIDs().Contains(senderID) {
 		return s.wrapError(errors.New("sender is not a party"), senderID)
 	}
 
+	if !s.isAcceptedType(msg.Type) {
+		return s.wrapError(errors.New("message type is not accepted for this type of round"), senderID)
+	}
+
 	// Check if we have already received a message from this party.
-	// exists should never be false, but you never know
-	if _, exists := s.receivedMessages[senderID]; exists {
+	// Messages for later rounds may arrive before the current round is complete, so we only compare with
+	// messages of the same type.
+	if s.isDuplicate(msg) {
 		return s.wrapError(errors.New("message from this party was already received"), senderID)
 	}
 
-	if !s.isAcceptedType(msg.Type) {
-		return s.wrapError(errors.New("message type is not accepted for this type of round"), senderID)
+	if msg.Type == s.acceptedTypes[0] && !s.expectedSenders().Contains(senderID) {
+		return s.wrapError(errors.New("sender is not expected to send a message in this round"), senderID)
 	}
 
 	s.ackMessage()
 
 	if msg.Type == s.acceptedTypes[0] {
 		s.receivedMessages[senderID] = msg
-		fmt.Println("received accepted message", msg)
 	} else {
 		s.queue = append(s.queue, msg)
 	}
@@ -147,18 +165,17 @@ func (s *State) ProcessAll() []*messages.Message {
 
 	// Only continue if we received messages from all
 
-	fmt.Println("proMsg...", len(s.receivedMessages), s.round.PartyIDs().N()-1, s.roundNumber)
-	if len(s.receivedMessages) != int(s.round.PartyIDs().N()-1) {
+	if !s.ready() {
 		return nil
 	}
 
-	for _, msg := range s.receivedMessages {
-
-		if err := s.round.ProcessMessage(msg); err != nil {
-			s.reportError(err)
-			return nil
+	if culprits := s.processMessages(); len(culprits) > 0 {
+		if !s.identifiable {
+			s.reportError(culprits[0])
+		} else {
+			s.reportBlame(culprits)
 		}
-		fmt.Println("end processed message")
+		return nil
 	}
 
 	// remove all messages that have been processed
@@ -171,33 +188,109 @@ func (s *State) ProcessAll() []*messages.Message {
 		s.reportError(err)
 		return nil
 	}
+	for _, msg := range newMessages {
+		msg.SessionID = s.round.SessionID()
+	}
+
+	// We are finished and move on to the next round
+	nextRound := s.round.NextRound()
+	if nextRound == nil {
+		s.finish()
+	} else {
+		s.roundNumber++
+		s.round = nextRound
+	}
 
 	// remove the messages for the next round from the queue
 	s.acceptedTypes = s.acceptedTypes[1:]
 	if len(s.acceptedTypes) > 0 {
 		newQueue := s.queue[:0]
 		currentType := s.acceptedTypes[0]
+		expected := s.expectedSenders()
 		for _, msg := range s.queue {
-			if msg.Type == currentType {
-				s.receivedMessages[msg.From] = msg
-			} else {
+			if msg.Type != currentType {
 				newQueue = append(newQueue, msg)
+			} else if expected.Contains(msg.From) {
+				s.receivedMessages[msg.From] = msg
 			}
 		}
 		s.queue = newQueue
 	}
 
-	// We are finished and move on to the next round
-	nextRound := s.round.NextRound()
-	if nextRound == nil {
-		s.finish()
-		fmt.Println("finish next round")
-	} else {
-		s.roundNumber++
-		s.round = nextRound
+	return newMessages
+}
+
+// processMessages feeds the messages received in the current round to the round, and returns an Error for each invalid one.
+// Unless we are in identifiable abort mode, it stops at the first error.
+func (s *State) processMessages() []*Error {
+	if batch, ok := s.round.(BatchProcessor); ok {
+		senders := make([]party.ID, 0, len(s.receivedMessages))
+		for id, msg := range s.receivedMessages {
+			if msg != nil {
+				senders = append(senders, id)
+			}
+		}
+		msgs := make([]*messages.Message, 0, len(senders))
+		for _, id := range party.NewIDSlice(senders) {
+			msgs = append(msgs, s.receivedMessages[id])
+		}
+		culprits := batch.ProcessMessages(msgs)
+		for _, err := range culprits {
+			if msg := s.receivedMessages[err.PartyID]; msg != nil {
+				err.Message, _ = msg.MarshalBinary()
+			}
+		}
+		return culprits
 	}
 
-	return newMessages
+	var culprits []*Error
+	for _, msg := range s.receivedMessages {
+
+		if err := s.round.ProcessMessage(msg); err != nil {
+			if msg != nil {
+				err.Message, _ = msg.MarshalBinary()
+			}
+			if !s.identifiable {
+				return []*Error{err}
+			}
+			culprits = append(culprits, err)
+		}
+	}
+	return culprits
+}
+
+// expectedSenders returns the parties from which we require a message in the current round.
+func (s *State) expectedSenders() party.IDSlice {
+	if r, ok := s.round.(ExpectedSenders); ok {
+		return r.ExpectedSenders()
+	}
+	return s.round.PartyIDs()
+}
+
+// ready returns true if all messages required for the current round have been received.
+func (s *State) ready() bool {
+	if s.acceptedTypes[0] == messages.MessageTypeNone {
+		return true
+	}
+	for _, id := range s.expectedSenders() {
+		if id != s.round.SelfID() && s.receivedMessages[id] == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// isDuplicate returns true if a message of the same type from the same sender was already received.
+func (s *State) isDuplicate(msg *messages.Message) bool {
+	if msg.Type == s.acceptedTypes[0] {
+		return s.receivedMessages[msg.From] != nil
+	}
+	for _, queued := range s.queue {
+		if queued.From == msg.From && queued.Type == msg.Type {
+			return true
+		}
+	}
+	return false
 }
 
 func (s *State) isAcceptedType(msgType messages.MessageType) bool {
@@ -234,6 +327,128 @@ func (s *State) reportError(err *Error) {
 	}
 }
 
+// reportBlame aborts the protocol and reports all culprits found in the current round.
+func (s *State) reportBlame(culprits []*Error) {
+	if s.done {
+		return
+	}
+	for _, culprit := range culprits {
+		culprit.RoundNumber = s.roundNumber
+	}
+	s.blame = newBlame(culprits)
+	s.reportError(culprits[0])
+}
+
+// reportTimeout aborts the protocol after a timeout, unless the round implements TimeoutHandler and can be completed
+// without the missing messages.
+// In identifiable abort mode, all parties which have not sent their message for the current round are blamed.
+func (s *State) reportTimeout() {
+	if s.done {
+		return
+	}
+
+	var missing party.IDSlice
+	if len(s.acceptedTypes) > 0 && s.acceptedTypes[0] != messages.MessageTypeNone {
+		for _, id := range s.expectedSenders() {
+			if id != s.round.SelfID() && s.receivedMessages[id] == nil {
+				missing = append(missing, id)
+			}
+		}
+	}
+
+	if r, ok := s.round.(TimeoutHandler); ok && len(missing) > 0 && r.ProcessTimeout(missing) {
+		// Restart the timer, in case ProcessAll is not called
+		s.ackMessage()
+		select {
+		case s.proceedChan <- struct{}{}:
+		default:
+		}
+		return
+	}
+
+	if !s.identifiable {
+		s.reportError(NewError(0, ErrTimeout))
+		return
+	}
+
+	var culprits []*Error
+	for _, id := range missing {
+		culprits = append(culprits, NewError(id, ErrTimeout))
+	}
+	if len(culprits) == 0 {
+		s.reportError(NewError(0, ErrTimeout))
+		return
+	}
+	s.reportBlame(culprits)
+}
+
+// SetIdentifiableAbort enables or disables the identifiable abort mode.
+// When enabled, all messages of a round are validated before aborting,
+// and Err returns a *Blame containing every party which misbehaved in that round.
+// It should be called before any messages are processed.
+func (s *State) SetIdentifiableAbort(enabled bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.identifiable = enabled
+}
+
+// SetSessionID sets the identifier of this protocol execution, which must be the same for all parties.
+// Outgoing messages are tagged with it, and incoming messages with a different one are rejected.
+// The rounds also bind it to their proofs and hashes, so that these cannot be replayed in another session.
+// It should be called before any messages are processed.
+func (s *State) SetSessionID(sessionID messages.SessionID) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.round.SetSessionID(sessionID)
+}
+
+// Abort stops the protocol with err, which is not attributed to any party, and erases the secrets of the round.
+// It has no effect if the protocol is already finished.
+func (s *State) Abort(err error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.reportError(NewError(0, err))
+}
+
+// Progress describes how far a State has advanced in the protocol.
+type Progress struct {
+	// Round is the number of the current round, starting from 0
+	Round int
+
+	// Received is the number of messages received so far for the current round
+	Received int
+
+	// Expected is the number of messages required to complete the current round
+	Expected int
+
+	// Finished is true if the protocol has aborted or successfully finished
+	Finished bool
+}
+
+// Progress returns the current Progress of the protocol.
+func (s *State) Progress() Progress {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	p := Progress{
+		Round:    s.roundNumber,
+		Finished: s.done,
+	}
+	if s.done || len(s.acceptedTypes) == 0 || s.acceptedTypes[0] == messages.MessageTypeNone {
+		return p
+	}
+	for _, id := range s.expectedSenders() {
+		if id == s.round.SelfID() {
+			continue
+		}
+		p.Expected++
+		if s.receivedMessages[id] != nil {
+			p.Received++
+		}
+	}
+	return p
+}
+
 // Done should be called like context.Done:
 //
 //	select {
@@ -243,7 +458,21 @@ func (s *State) Done() <-chan struct{} {
 	return s.doneChan
 }
 
+// Proceed returns a channel which receives a value when the timeout of the current round expired,
+// but the round can be completed without the missing messages.
+// ProcessAll should then be called, even though no new message was received.
+func (s *State) Proceed() <-chan struct{} {
+	return s.proceedChan
+}
+
+// Err returns the error which caused the protocol to abort, or nil if it finished successfully.
+// In identifiable abort mode, the error is a *Blame whenever the fault could be attributed.
 func (s *State) Err() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.blame != nil {
+		return s.blame
+	}
 	if s.err != nil {
 		return s.err
 	}
@@ -253,29 +482,66 @@ func (s *State) Err() error {
 // WaitForError blocks until the protocol is done.
 // This happens either when the protocol has finished correctly,
 // or if an error has been detected.
+//
+// A State restored with Resume or UnmarshalJSON behaves in the same way,
+// and returns immediately if it was already done when it was marshalled.
 func (s *State) WaitForError() error {
-	if !s.done {
-		<-s.doneChan
-	}
+	<-s.doneChan
 	return s.Err()
 }
 
 // IsFinished returns true if the protocol has aborted or successfully finished.
 func (s *State) IsFinished() bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
 	return s.done
 }
 
+// stateVersion is the version of the format produced by MarshalJSON.
+// States marshalled before the format was versioned have version 0, and do not include the round type.
+const stateVersion = 1
+
 type stateJSON struct {
+	Version          int                    `json:"version,omitempty"`
 	AcceptedTypes    []messages.MessageType `json:"acceptedTypes"`
 	ReceivedMessages map[uint16][]byte      `json:"receivedMessages"`
 	Queue            [][]byte               `json:"queue"`
 	RoundNumber      int                    `json:"roundNumber"`
+	RoundType        string                 `json:"roundType,omitempty"`
 	Round            []byte                 `json:"round"`
 	Done             bool                   `json:"done,omitempty"`
+	Timeout          time.Duration          `json:"timeout,omitempty"`
+	Identifiable     bool                   `json:"identifiable,omitempty"`
+	Error            *culpritJSON           `json:"error,omitempty"`
+	Blame            *Blame                 `json:"blame,omitempty"`
+}
+
+// Suspend returns the serialized State, including the current round and all messages received so far,
+// so that the protocol can be continued later with Resume, possibly in another process.
+// The round type must have been registered with RegisterRound, which the protocols of this library do.
+//
+// The result contains the party's secrets, and must be stored accordingly.
+func (s *State) Suspend() ([]byte, error) {
+	return s.MarshalJSON()
+}
+
+// Resume restores a State serialized by Suspend.
+// The timeout, if any, starts over from the moment the State is resumed.
+func Resume(data []byte) (*State, error) {
+	var s State
+	if err := s.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+	if s.round == nil {
+		return nil, errors.New("state: round type is missing")
+	}
+	return &s, nil
 }
 
 // MarshalJSON implements the json.Marshaller interface.
 func (s *State) MarshalJSON() ([]byte, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
 
 	recContainer := make(map[uint16][]byte)
 	for id, msg := range s.receivedMessages {
@@ -289,7 +555,7 @@ func (s *State) MarshalJSON() ([]byte, error) {
 		recContainer[uint16(id)] = data
 	}
 
-	queueContainer := make([][]byte, len(s.queue))
+	queueContainer := make([][]byte, 0, len(s.queue))
 	for _, q := range s.queue {
 		data, err := q.MarshalBinary()
 		if err != nil {
@@ -298,31 +564,50 @@ func (s *State) MarshalJSON() ([]byte, error) {
 		queueContainer = append(queueContainer, data)
 	}
 
+	roundType, err := roundName(s.round)
+	if err != nil {
+		return nil, err
+	}
 	roundData, err := json.Marshal(s.round)
 	if err != nil {
 		return nil, err
 	}
 
-	//fmt.Println("mar--------------------------")
-	//fmt.Println(s.roundNumber, len(s.acceptedTypes), len(recContainer), len(roundData), len(queueContainer))
-	//fmt.Println("mar end--------------------------")
+	var stateErr *culpritJSON
+	if s.err != nil {
+		e := newCulpritJSON(s.err)
+		stateErr = &e
+	}
 
 	return json.Marshal(stateJSON{
+		Version:          stateVersion,
 		AcceptedTypes:    s.acceptedTypes,
 		ReceivedMessages: recContainer,
 		Queue:            queueContainer,
 		RoundNumber:      s.roundNumber,
+		RoundType:        roundType,
 		Round:            roundData,
 		Done:             s.done,
+		Timeout:          s.timer.d,
+		Identifiable:     s.identifiable,
+		Error:            stateErr,
+		Blame:            s.blame,
 	})
 }
 
+// UnmarshalJSON implements the json.Unmarshaler interface.
+//
+// If data does not include the round type, because it was marshalled by an older version,
+// the round is not restored, and the caller must unmarshal RoundData and call SetRound.
 func (s *State) UnmarshalJSON(data []byte) error {
 	var rawJson stateJSON
 	err := json.Unmarshal(data, &rawJson)
 	if err != nil {
 		return err
 	}
+	if rawJson.Version > stateVersion {
+		return fmt.Errorf("state: unsupported version %d", rawJson.Version)
+	}
 
 	recContainer := make(map[party.ID]*messages.Message)
 	for id, msg := range rawJson.ReceivedMessages {
@@ -337,8 +622,9 @@ func (s *State) UnmarshalJSON(data []byte) error {
 		recContainer[party.ID(id)] = &msg1
 	}
 
-	queueContainer := make([]*messages.Message, len(rawJson.Queue))
-	for i, q := range rawJson.Queue {
+	queueContainer := make([]*messages.Message, 0, len(rawJson.Queue))
+	for _, q := range rawJson.Queue {
+		// Older versions included empty entries
 		if q == nil {
 			continue
 		}
@@ -347,27 +633,45 @@ func (s *State) UnmarshalJSON(data []byte) error {
 		if err != nil {
 			return err
 		}
-		queueContainer[i] = &msg
+		queueContainer = append(queueContainer, &msg)
 	}
 
-	//var r1 Round
-	//Round.UnmarshalRound()
-	//r1, err = Round.UnmarshalRound(r1, rawJson.Round)
-
-	//err = json.Unmarshal(rawJson.Round, &r1)
-	//if err != nil {
-	//	return err
-	//}
+	var round Round
+	if rawJson.RoundType != "" {
+		if round, err = newRound(rawJson.RoundType); err != nil {
+			return err
+		}
+		if err = json.Unmarshal(rawJson.Round, round); err != nil {
+			return fmt.Errorf("state: failed to unmarshal round %q: %w", rawJson.RoundType, err)
+		}
+	}
 
 	*s = State{
 		acceptedTypes:    rawJson.AcceptedTypes,
 		receivedMessages: recContainer,
 		queue:            queueContainer,
 		roundNumber:      rawJson.RoundNumber,
-		//round:            r1,
-		RoundData: rawJson.Round,
-		doneChan:  make(chan struct{}),
-		done:      rawJson.Done,
+		round:            round,
+		RoundData:        rawJson.Round,
+		doneChan:         make(chan struct{}),
+		proceedChan:      make(chan struct{}, 1),
+		done:             rawJson.Done,
+		identifiable:     rawJson.Identifiable,
+	}
+	if e := rawJson.Error; e != nil {
+		s.err = e.toError()
+	}
+	s.blame = rawJson.Blame
+
+	if s.done {
+		close(s.doneChan)
+		s.timer = timer{d: rawJson.Timeout}
+	} else {
+		s.timer = newTimer(rawJson.Timeout, func() {
+			s.mtx.Lock()
+			s.reportTimeout()
+			s.mtx.Unlock()
+		})
 	}
 
 	return nil
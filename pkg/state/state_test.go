@@ -0,0 +1,424 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+)
+
+// stubRound is a protocol where every party broadcasts a Sign1 and then a Sign2 message.
+// The messages are not validated, but the round records the sender of every message it processes.
+type stubRound struct {
+	*BaseRound
+	number int
+	// expected are the parties which must send a message, or all parties if it is nil.
+	expected party.IDSlice
+	// processed contains the senders of the messages processed in each round.
+	processed map[int][]party.ID
+}
+
+func newStubState(t *testing.T, selfID party.ID, partyIDs, expected party.IDSlice) (*State, *stubRound) {
+	base, err := NewBaseRound(selfID, partyIDs)
+	require.NoError(t, err)
+	round := &stubRound{
+		BaseRound: base,
+		expected:  expected,
+		processed: map[int][]party.ID{},
+	}
+	s, err := NewBaseState(round, 0)
+	require.NoError(t, err)
+	return s, round
+}
+
+func (r *stubRound) ProcessMessage(msg *messages.Message) *Error {
+	if msg != nil {
+		r.processed[r.number] = append(r.processed[r.number], msg.From)
+	}
+	return nil
+}
+
+func (r *stubRound) GenerateMessages() ([]*messages.Message, *Error) {
+	switch r.number {
+	case 0:
+		return []*messages.Message{stubSign1(r.SelfID())}, nil
+	case 1:
+		return []*messages.Message{stubSign2(r.SelfID())}, nil
+	}
+	return nil, nil
+}
+
+func (r *stubRound) NextRound() Round {
+	if r.number == 2 {
+		return nil
+	}
+	r.number++
+	return r
+}
+
+func (r *stubRound) AcceptedMessageTypes() []messages.MessageType {
+	return []messages.MessageType{messages.MessageTypeNone, messages.MessageTypeSign1, messages.MessageTypeSign2}
+}
+
+func (r *stubRound) Reset() {}
+
+func (r *stubRound) GetOutput() interface{} {
+	return nil
+}
+
+func (r *stubRound) ExpectedSenders() party.IDSlice {
+	if r.expected == nil {
+		return r.PartyIDs()
+	}
+	return r.expected
+}
+
+// senders returns the sorted senders of the messages processed in the given round.
+func (r *stubRound) senders(number int) party.IDSlice {
+	return party.NewIDSlice(append([]party.ID{}, r.processed[number]...))
+}
+
+func stubSign1(from party.ID) *messages.Message {
+	return messages.NewSign1(from, ristretto.NewIdentityElement(), ristretto.NewIdentityElement())
+}
+
+func stubSign2(from party.ID) *messages.Message {
+	return messages.NewSign2(from, ristretto.NewScalar())
+}
+
+func TestState_Duplicates(t *testing.T) {
+	s, round := newStubState(t, 1, party.IDSlice{1, 2, 3}, nil)
+	require.Len(t, s.ProcessAll(), 1)
+	require.Equal(t, 1, s.GetRoundNumber())
+
+	require.NoError(t, s.HandleMessage(stubSign1(2)))
+	assert.Error(t, s.HandleMessage(stubSign1(2)), "duplicate message for the current round")
+
+	// Messages for the next round are queued, and duplicates are also detected in the queue
+	require.NoError(t, s.HandleMessage(stubSign2(3)))
+	assert.Error(t, s.HandleMessage(stubSign2(3)), "duplicate message for the next round")
+
+	// Our own messages are ignored
+	assert.NoError(t, s.HandleMessage(stubSign1(1)))
+
+	require.NoError(t, s.HandleMessage(stubSign1(3)))
+	require.Len(t, s.ProcessAll(), 1)
+	assert.Equal(t, party.IDSlice{2, 3}, round.senders(1))
+
+	// The queued message now belongs to the current round
+	assert.Error(t, s.HandleMessage(stubSign2(3)), "duplicate of the queued message")
+	require.NoError(t, s.HandleMessage(stubSign2(2)))
+	s.ProcessAll()
+	assert.Equal(t, party.IDSlice{2, 3}, round.senders(2))
+	require.NoError(t, s.WaitForError())
+
+	assert.Error(t, s.HandleMessage(stubSign2(2)), "the protocol is finished")
+}
+
+func TestState_ExpectedSenders(t *testing.T) {
+	s, round := newStubState(t, 1, party.IDSlice{1, 2, 3, 4}, party.IDSlice{1, 2, 3})
+
+	// Messages received before their round are queued, and only kept if the sender is expected
+	require.NoError(t, s.HandleMessage(stubSign1(2)))
+	require.NoError(t, s.HandleMessage(stubSign1(4)))
+	require.Len(t, s.ProcessAll(), 1)
+
+	assert.Error(t, s.HandleMessage(stubSign1(4)), "sender is not expected")
+	assert.Nil(t, s.ProcessAll(), "the round must wait for party 3")
+	assert.Equal(t, 1, s.GetRoundNumber())
+
+	require.NoError(t, s.HandleMessage(stubSign1(3)))
+	require.Len(t, s.ProcessAll(), 1)
+	assert.Equal(t, party.IDSlice{2, 3}, round.senders(1))
+
+	require.NoError(t, s.HandleMessage(stubSign2(2)))
+	require.NoError(t, s.HandleMessage(stubSign2(3)))
+	s.ProcessAll()
+	require.NoError(t, s.WaitForError())
+	assert.Equal(t, party.IDSlice{2, 3}, round.senders(2))
+}
+
+var errStub = errors.New("invalid stub message")
+
+// batchRound is a stubRound which processes the messages of a round all at once, and rejects those from invalid parties.
+type batchRound struct {
+	*stubRound
+	invalid party.IDSlice
+	// batches contains the senders of the messages given to ProcessMessages, in order.
+	batches [][]party.ID
+}
+
+func (r *batchRound) ProcessMessages(msgs []*messages.Message) []*Error {
+	senders := make([]party.ID, 0, len(msgs))
+	var culprits []*Error
+	for _, msg := range msgs {
+		senders = append(senders, msg.From)
+		if r.invalid.Contains(msg.From) {
+			culprits = append(culprits, NewError(msg.From, errStub))
+		}
+	}
+	r.batches = append(r.batches, senders)
+	return culprits
+}
+
+func (r *batchRound) NextRound() Round {
+	if r.stubRound.NextRound() == nil {
+		return nil
+	}
+	return r
+}
+
+func newBatchState(t *testing.T, invalid party.IDSlice, identifiable bool) (*State, *batchRound) {
+	base, err := NewBaseRound(1, party.IDSlice{1, 2, 3, 4, 5})
+	require.NoError(t, err)
+	round := &batchRound{
+		stubRound: &stubRound{BaseRound: base, processed: map[int][]party.ID{}},
+		invalid:   invalid,
+	}
+	s, err := NewBaseState(round, 0)
+	require.NoError(t, err)
+	s.SetIdentifiableAbort(identifiable)
+
+	require.Len(t, s.ProcessAll(), 1)
+	for _, id := range []party.ID{5, 4, 3, 2} {
+		require.NoError(t, s.HandleMessage(stubSign1(id)))
+	}
+	assert.Nil(t, s.ProcessAll())
+	return s, round
+}
+
+func TestState_BatchProcessor(t *testing.T) {
+	s, round := newBatchState(t, party.IDSlice{2, 4}, true)
+
+	// All messages are given at once, sorted by sender
+	require.Len(t, round.batches, 2)
+	assert.Equal(t, []party.ID{2, 3, 4, 5}, round.batches[1])
+	assert.Empty(t, round.processed, "ProcessMessage must not be called")
+
+	var blame *Blame
+	require.True(t, errors.As(s.WaitForError(), &blame))
+	assert.Equal(t, party.IDSlice{2, 4}, blame.PartyIDs())
+	for _, culprit := range blame.Culprits {
+		assert.True(t, errors.Is(culprit, errStub))
+		assert.Equal(t, 1, culprit.RoundNumber)
+
+		// The offending message is attached to the culprit
+		var msg messages.Message
+		require.NoError(t, msg.UnmarshalBinary(culprit.Message))
+		assert.Equal(t, culprit.PartyID, msg.From)
+		assert.Equal(t, messages.MessageTypeSign1, msg.Type)
+	}
+	honest, err := blame.Honest(round.PartyIDs())
+	require.NoError(t, err)
+	assert.Equal(t, party.IDSlice{1, 3, 5}, honest)
+}
+
+func TestState_BatchProcessorNotIdentifiable(t *testing.T) {
+	s, _ := newBatchState(t, party.IDSlice{2, 4}, false)
+
+	// Without identifiable abort, only the first culprit is reported
+	err := s.WaitForError()
+	var blame *Blame
+	assert.False(t, errors.As(err, &blame))
+	var stateErr *Error
+	require.True(t, errors.As(err, &stateErr))
+	assert.Equal(t, party.ID(2), stateErr.PartyID)
+	assert.True(t, errors.Is(err, errStub))
+}
+
+// timeoutRound is a stubRound which continues without the parties whose message is missing when the timeout expires,
+// unless reject is set.
+type timeoutRound struct {
+	*stubRound
+	reject  bool
+	missing party.IDSlice
+}
+
+func (r *timeoutRound) ProcessTimeout(missing party.IDSlice) bool {
+	r.missing = missing
+	if r.reject {
+		return false
+	}
+	expected := make(party.IDSlice, 0, len(r.PartyIDs()))
+	for _, id := range r.ExpectedSenders() {
+		if !missing.Contains(id) {
+			expected = append(expected, id)
+		}
+	}
+	r.expected = expected
+	return true
+}
+
+func (r *timeoutRound) NextRound() Round {
+	if r.stubRound.NextRound() == nil {
+		return nil
+	}
+	return r
+}
+
+func newTimeoutState(t *testing.T, reject bool) (*State, *timeoutRound) {
+	base, err := NewBaseRound(1, party.IDSlice{1, 2, 3})
+	require.NoError(t, err)
+	round := &timeoutRound{
+		stubRound: &stubRound{BaseRound: base, processed: map[int][]party.ID{}},
+		reject:    reject,
+	}
+	s, err := NewBaseState(round, 50*time.Millisecond)
+	require.NoError(t, err)
+
+	require.Len(t, s.ProcessAll(), 1)
+	require.NoError(t, s.HandleMessage(stubSign1(2)))
+	assert.Nil(t, s.ProcessAll(), "the round must wait for party 3")
+	return s, round
+}
+
+func TestState_TimeoutHandler(t *testing.T) {
+	s, round := newTimeoutState(t, false)
+
+	select {
+	case <-s.Proceed():
+	case <-s.Done():
+		t.Fatalf("the protocol aborted: %v", s.Err())
+	case <-time.After(5 * time.Second):
+		t.Fatal("the timeout did not expire")
+	}
+	assert.Equal(t, party.IDSlice{3}, round.missing)
+	assert.False(t, s.IsFinished())
+
+	// The round is completed without the message of party 3, which is no longer expected
+	require.Len(t, s.ProcessAll(), 1)
+	assert.Equal(t, party.IDSlice{2}, round.senders(1))
+	assert.Error(t, s.HandleMessage(stubSign2(3)), "sender is not expected")
+	require.NoError(t, s.HandleMessage(stubSign2(2)))
+	s.ProcessAll()
+	require.NoError(t, s.WaitForError())
+}
+
+func TestState_TimeoutHandlerRejects(t *testing.T) {
+	s, round := newTimeoutState(t, true)
+
+	// The round cannot continue without party 3, so the protocol aborts as usual
+	err := s.WaitForError()
+	assert.True(t, errors.Is(err, ErrTimeout))
+	assert.Equal(t, party.IDSlice{3}, round.missing)
+}
+
+func init() {
+	RegisterRound("state.stubRound", func() Round { return new(stubRound) })
+}
+
+type stubRoundJSON struct {
+	Base      json.RawMessage    `json:"base"`
+	Number    int                `json:"number"`
+	Expected  party.IDSlice      `json:"expected"`
+	Processed map[int][]party.ID `json:"processed"`
+}
+
+func (r *stubRound) MarshalJSON() ([]byte, error) {
+	base, err := r.BaseRound.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(stubRoundJSON{
+		Base:      base,
+		Number:    r.number,
+		Expected:  r.expected,
+		Processed: r.processed,
+	})
+}
+
+func (r *stubRound) UnmarshalJSON(data []byte) error {
+	var rawJSON stubRoundJSON
+	if err := json.Unmarshal(data, &rawJSON); err != nil {
+		return err
+	}
+	r.BaseRound = new(BaseRound)
+	if err := r.BaseRound.UnmarshalJSON(rawJSON.Base); err != nil {
+		return err
+	}
+	r.number = rawJSON.Number
+	r.expected = rawJSON.Expected
+	r.processed = rawJSON.Processed
+	if r.processed == nil {
+		r.processed = map[int][]party.ID{}
+	}
+	return nil
+}
+
+var stubSession = messages.SessionID{1, 2, 3}
+
+func inSession(msg *messages.Message) *messages.Message {
+	msg.SessionID = stubSession
+	return msg
+}
+
+func TestState_SuspendResume(t *testing.T) {
+	s, _ := newStubState(t, 1, party.IDSlice{1, 2, 3}, nil)
+	s.SetSessionID(stubSession)
+	require.Len(t, s.ProcessAll(), 1)
+
+	// One message for the current round, and one queued for the next
+	require.NoError(t, s.HandleMessage(inSession(stubSign1(2))))
+	require.NoError(t, s.HandleMessage(inSession(stubSign2(3))))
+
+	data, err := s.Suspend()
+	require.NoError(t, err)
+	s, err = Resume(data)
+	require.NoError(t, err)
+	round, ok := s.GetRound().(*stubRound)
+	require.True(t, ok, "unexpected round type %T", s.GetRound())
+	assert.Equal(t, 1, s.GetRoundNumber())
+	assert.Equal(t, stubSession, round.SessionID())
+
+	// Received and queued messages were restored
+	assert.Error(t, s.HandleMessage(inSession(stubSign1(2))), "duplicate of the received message")
+	assert.Error(t, s.HandleMessage(inSession(stubSign2(3))), "duplicate of the queued message")
+
+	require.NoError(t, s.HandleMessage(inSession(stubSign1(3))))
+	out := s.ProcessAll()
+	require.Len(t, out, 1)
+	assert.Equal(t, stubSession, out[0].SessionID)
+	assert.Equal(t, party.IDSlice{2, 3}, round.senders(1))
+
+	require.NoError(t, s.HandleMessage(inSession(stubSign2(2))))
+	s.ProcessAll()
+	require.NoError(t, s.WaitForError())
+	assert.Equal(t, party.IDSlice{2, 3}, round.senders(2))
+}
+
+func TestState_ResumeBlame(t *testing.T) {
+	base, err := NewBaseRound(1, party.IDSlice{1, 2, 3, 4})
+	require.NoError(t, err)
+	s, err := NewBaseState(&stubRound{BaseRound: base, processed: map[int][]party.ID{}}, 10*time.Millisecond)
+	require.NoError(t, err)
+	s.SetIdentifiableAbort(true)
+	require.Len(t, s.ProcessAll(), 1)
+	require.NoError(t, s.HandleMessage(stubSign1(2)))
+
+	// Parties 3 and 4 are blamed for the timeout
+	var blame *Blame
+	require.True(t, errors.As(s.WaitForError(), &blame))
+	require.Equal(t, party.IDSlice{3, 4}, blame.PartyIDs())
+
+	data, err := s.Suspend()
+	require.NoError(t, err)
+	s, err = Resume(data)
+	require.NoError(t, err)
+
+	// The blame set is restored, and its reasons can still be compared
+	err = s.WaitForError()
+	var resumed *Blame
+	require.True(t, errors.As(err, &resumed))
+	assert.Equal(t, blame.PartyIDs(), resumed.PartyIDs())
+	assert.Equal(t, blame.Error(), resumed.Error())
+	for _, culprit := range resumed.Culprits {
+		assert.True(t, errors.Is(culprit, ErrTimeout))
+		assert.Equal(t, 1, culprit.RoundNumber)
+	}
+}
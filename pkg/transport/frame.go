@@ -0,0 +1,95 @@
+package transport
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// Frames are exchanged over a TLS connection, and are encoded as
+//
+//	length (4 bytes) || kind (1 byte) || sequence number (8 bytes) || payload
+//
+// where length is the number of bytes following the length prefix.
+// Data frames are only sent by the dialing party, and contain a marshalled messages.Message.
+// Ack frames are sent back by the accepting party, and acknowledge all data frames up to and including
+// the given sequence number. They have an empty payload.
+//
+// The first frame of every connection is a hello frame sent by the dialing party.
+// Its sequence number is the one of the first data frame which was not acknowledged,
+// and its payload is the 8 byte epoch of the dialing party, which changes every time it restarts.
+// The accepting party replies with an ack frame for the last data frame it received from the current epoch.
+
+const (
+	frameKindData  byte = 1
+	frameKindAck   byte = 2
+	frameKindHello byte = 3
+
+	helloPayloadSize = 8
+
+	frameHeaderSize = 1 + 8
+
+	// MaxFrameSize is the maximum size of a single frame, excluding the length prefix.
+	MaxFrameSize = 1 << 24
+)
+
+var errFrameTooLarge = errors.New("transport: frame too large")
+
+type frame struct {
+	kind    byte
+	seq     uint64
+	payload []byte
+}
+
+func writeFrame(w io.Writer, f *frame) error {
+	size := frameHeaderSize + len(f.payload)
+	if size > MaxFrameSize {
+		return errFrameTooLarge
+	}
+	buf := make([]byte, 4+size)
+	binary.BigEndian.PutUint32(buf, uint32(size))
+	buf[4] = f.kind
+	binary.BigEndian.PutUint64(buf[5:], f.seq)
+	copy(buf[4+frameHeaderSize:], f.payload)
+	_, err := w.Write(buf)
+	return err
+}
+
+func readFrame(r io.Reader) (*frame, error) {
+	var lengthBytes [4]byte
+	if _, err := io.ReadFull(r, lengthBytes[:]); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(lengthBytes[:])
+	if size < frameHeaderSize {
+		return nil, errors.New("transport: frame too short")
+	}
+	if size > MaxFrameSize {
+		return nil, errFrameTooLarge
+	}
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	f := &frame{
+		kind: buf[0],
+		seq:  binary.BigEndian.Uint64(buf[1:]),
+	}
+	if len(buf) > frameHeaderSize {
+		f.payload = buf[frameHeaderSize:]
+	}
+	switch f.kind {
+	case frameKindData:
+	case frameKindHello:
+		if len(f.payload) != helloPayloadSize {
+			return nil, errors.New("transport: invalid hello frame")
+		}
+	case frameKindAck:
+		if len(f.payload) != 0 {
+			return nil, errors.New("transport: ack frame with payload")
+		}
+	default:
+		return nil, errors.New("transport: unknown frame kind")
+	}
+	return f, nil
+}
@@ -0,0 +1,632 @@
+package transport
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+)
+
+const (
+	defaultRetryInterval    = 500 * time.Millisecond
+	defaultHandshakeTimeout = 10 * time.Second
+	defaultLingerTimeout    = 5 * time.Second
+)
+
+// Config contains the parameters of a TCP transport.
+type Config struct {
+	// ID is the party.ID of the party running the transport.
+	ID party.ID
+
+	// ListenAddress is the address on which connections from other parties are accepted, for example ":4000".
+	// It is ignored by NewTCPWithListener.
+	ListenAddress string
+
+	// Peers maps the party.ID of every other party to the address on which it is listening.
+	Peers map[party.ID]string
+
+	// Certificate is presented to other parties, both when dialing and when accepting connections.
+	// Its leaf must be valid for client and server authentication.
+	Certificate tls.Certificate
+
+	// CAs is the pool of certificate authorities used to verify the certificates of other parties.
+	CAs *x509.CertPool
+
+	// PartyID extracts the party.ID bound to a verified certificate.
+	// If nil, PartyIDFromCommonName is used.
+	PartyID func(cert *x509.Certificate) (party.ID, error)
+
+	// RetryInterval is the delay between two connection attempts to a peer.
+	// If zero, a default of 500ms is used.
+	RetryInterval time.Duration
+
+	// HandshakeTimeout bounds the time spent establishing a connection.
+	// If zero, a default of 10s is used.
+	HandshakeTimeout time.Duration
+
+	// LingerTimeout is the maximum time Close waits for pending messages to be acknowledged by their recipient.
+	// If zero, a default of 5s is used. A negative value disables waiting.
+	LingerTimeout time.Duration
+}
+
+// PartyIDFromCommonName returns the party.ID contained in the subject's common name of cert,
+// encoded in base 10.
+func PartyIDFromCommonName(cert *x509.Certificate) (party.ID, error) {
+	var id party.ID
+	if err := id.UnmarshalText([]byte(cert.Subject.CommonName)); err != nil {
+		return 0, err
+	}
+	if id == 0 {
+		return 0, errors.New("transport: certificate is bound to party.ID 0")
+	}
+	return id, nil
+}
+
+// TCP is a Transport which connects all parties with TLS connections, where both sides are authenticated
+// by their certificate.
+//
+// Each party dials every other party, and uses this connection to send its own messages.
+// Messages are numbered, and kept until the recipient acknowledges them.
+// If a connection is lost, it is re-established and all unacknowledged messages are sent again,
+// so that messages are delivered exactly once and in order, as long as both parties are running.
+//
+// Every TCP has a random epoch, which is sent along with the first unacknowledged sequence number
+// whenever a connection is established. When a party restarts, its peers notice the new epoch,
+// or their own new epoch, and continue from the sequence number it announces.
+// Messages acknowledged by a party before it restarted are not sent again.
+//
+// A message received over a connection is only delivered if its sender is the party bound to the certificate
+// presented by the other end.
+type TCP struct {
+	config    Config
+	epoch     uint64
+	listener  net.Listener
+	serverTLS *tls.Config
+
+	peers    map[party.ID]*peer
+	incoming chan *messages.Message
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mtx    sync.Mutex
+	closed bool
+	conns  map[net.Conn]struct{}
+}
+
+// peer holds the state of the connections with a single other party.
+type peer struct {
+	id      party.ID
+	address string
+	t       *TCP
+
+	// mtx protects the outgoing queue.
+	mtx     sync.Mutex
+	nextSeq uint64
+	pending []*frame
+	notify  chan struct{}
+
+	// recvMtx protects recvEpoch and received, and ensures frames from this peer are delivered one at a time,
+	// even if it has more than one open connection.
+	recvMtx   sync.Mutex
+	recvEpoch uint64
+	received  uint64
+}
+
+// NewTCP listens on config.ListenAddress and starts connecting to all peers.
+func NewTCP(config Config) (*TCP, error) {
+	l, err := net.Listen("tcp", config.ListenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("transport: %w", err)
+	}
+	t, err := NewTCPWithListener(l, config)
+	if err != nil {
+		_ = l.Close()
+		return nil, err
+	}
+	return t, nil
+}
+
+// NewTCPWithListener is similar to NewTCP, but accepts connections from l.
+// The returned TCP takes ownership of l.
+func NewTCPWithListener(l net.Listener, config Config) (*TCP, error) {
+	if config.ID == 0 {
+		return nil, errors.New("transport: ID cannot be 0")
+	}
+	if config.CAs == nil {
+		return nil, errors.New("transport: no certificate authorities given")
+	}
+	if len(config.Certificate.Certificate) == 0 {
+		return nil, errors.New("transport: no certificate given")
+	}
+	if config.PartyID == nil {
+		config.PartyID = PartyIDFromCommonName
+	}
+	if config.RetryInterval == 0 {
+		config.RetryInterval = defaultRetryInterval
+	}
+	if config.HandshakeTimeout == 0 {
+		config.HandshakeTimeout = defaultHandshakeTimeout
+	}
+	if config.LingerTimeout == 0 {
+		config.LingerTimeout = defaultLingerTimeout
+	}
+
+	epoch, err := newEpoch()
+	if err != nil {
+		return nil, fmt.Errorf("transport: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t := &TCP{
+		config:   config,
+		epoch:    epoch,
+		listener: l,
+		serverTLS: &tls.Config{
+			Certificates: []tls.Certificate{config.Certificate},
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+			ClientCAs:    config.CAs,
+			MinVersion:   tls.VersionTLS12,
+		},
+		peers:    make(map[party.ID]*peer, len(config.Peers)),
+		incoming: make(chan *messages.Message, len(config.Peers)+1),
+		ctx:      ctx,
+		cancel:   cancel,
+		conns:    map[net.Conn]struct{}{},
+	}
+	for id, address := range config.Peers {
+		if id == 0 || id == config.ID {
+			cancel()
+			return nil, fmt.Errorf("transport: invalid peer ID %d", id)
+		}
+		t.peers[id] = &peer{
+			id:      id,
+			address: address,
+			t:       t,
+			nextSeq: 1,
+			notify:  make(chan struct{}, 1),
+		}
+	}
+
+	t.wg.Add(1 + len(t.peers))
+	go t.acceptLoop()
+	for _, p := range t.peers {
+		go p.run()
+	}
+	return t, nil
+}
+
+// newEpoch returns a random non-zero epoch.
+func newEpoch() (uint64, error) {
+	var buf [8]byte
+	for {
+		if _, err := rand.Read(buf[:]); err != nil {
+			return 0, err
+		}
+		if epoch := binary.BigEndian.Uint64(buf[:]); epoch != 0 {
+			return epoch, nil
+		}
+	}
+}
+
+// Addr returns the address on which t accepts connections.
+func (t *TCP) Addr() net.Addr {
+	return t.listener.Addr()
+}
+
+// Send implements Transport.
+// The message is queued, and delivered once a connection with the recipient is available.
+func (t *TCP) Send(msg *messages.Message) error {
+	if msg.From != t.config.ID {
+		return errors.New("transport: message was not sent by this party")
+	}
+	payload, err := msg.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if t.closed {
+		return ErrClosed
+	}
+
+	if msg.IsBroadcast() {
+		for _, p := range t.peers {
+			p.enqueue(payload)
+		}
+		return nil
+	}
+	if msg.To == t.config.ID {
+		return nil
+	}
+	p, ok := t.peers[msg.To]
+	if !ok {
+		return fmt.Errorf("transport: unknown recipient %d", msg.To)
+	}
+	p.enqueue(payload)
+	return nil
+}
+
+// Incoming implements Transport.
+func (t *TCP) Incoming() <-chan *messages.Message {
+	return t.incoming
+}
+
+// Close implements Transport.
+// It waits at most Config.LingerTimeout for pending messages to be acknowledged,
+// and then closes all connections.
+func (t *TCP) Close() error {
+	t.mtx.Lock()
+	if t.closed {
+		t.mtx.Unlock()
+		return ErrClosed
+	}
+	t.closed = true
+	t.mtx.Unlock()
+
+	t.linger()
+
+	t.cancel()
+	err := t.listener.Close()
+	t.closeConns()
+	t.wg.Wait()
+	close(t.incoming)
+	return err
+}
+
+// linger waits until all peers have acknowledged all messages, or until the linger timeout expires.
+func (t *TCP) linger() {
+	if t.config.LingerTimeout < 0 {
+		return
+	}
+	deadline := time.Now().Add(t.config.LingerTimeout)
+	for time.Now().Before(deadline) {
+		idle := true
+		for _, p := range t.peers {
+			if !p.idle() {
+				idle = false
+				break
+			}
+		}
+		if idle {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// trackConn registers conn so that it is closed by Close.
+// It returns false if t is already closed, in which case conn is closed immediately.
+func (t *TCP) trackConn(conn net.Conn) bool {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if t.ctx.Err() != nil {
+		_ = conn.Close()
+		return false
+	}
+	t.conns[conn] = struct{}{}
+	return true
+}
+
+func (t *TCP) untrackConn(conn net.Conn) {
+	t.mtx.Lock()
+	delete(t.conns, conn)
+	t.mtx.Unlock()
+	_ = conn.Close()
+}
+
+// closeConns closes all open connections.
+// Unless t is closed, outgoing connections are re-established by their peer's run loop.
+func (t *TCP) closeConns() {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	for conn := range t.conns {
+		_ = conn.Close()
+	}
+}
+
+// verifyPeer checks that the certificate chain was issued by one of the configured CAs,
+// and returns the party.ID bound to it.
+func (t *TCP) verifyPeer(certs []*x509.Certificate) (party.ID, error) {
+	if len(certs) == 0 {
+		return 0, errors.New("transport: peer did not present a certificate")
+	}
+	intermediates := x509.NewCertPool()
+	for _, c := range certs[1:] {
+		intermediates.AddCert(c)
+	}
+	if _, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         t.config.CAs,
+		Intermediates: intermediates,
+		// Both sides authenticate with the same certificate, as a client and as a server.
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+	}); err != nil {
+		return 0, fmt.Errorf("transport: %w", err)
+	}
+	id, err := t.config.PartyID(certs[0])
+	if err != nil {
+		return 0, fmt.Errorf("transport: %w", err)
+	}
+	if _, ok := t.peers[id]; !ok {
+		return 0, fmt.Errorf("transport: certificate is bound to unknown party %d", id)
+	}
+	return id, nil
+}
+
+func (t *TCP) acceptLoop() {
+	defer t.wg.Done()
+	for {
+		conn, err := t.listener.Accept()
+		if err != nil {
+			if t.ctx.Err() != nil {
+				return
+			}
+			time.Sleep(t.config.RetryInterval)
+			continue
+		}
+		if !t.trackConn(conn) {
+			return
+		}
+		t.wg.Add(1)
+		go t.serveInbound(conn)
+	}
+}
+
+// serveInbound authenticates the party which dialed conn, and delivers the messages it sends.
+func (t *TCP) serveInbound(conn net.Conn) {
+	defer t.wg.Done()
+	defer t.untrackConn(conn)
+
+	tlsConn := tls.Server(conn, t.serverTLS)
+	_ = tlsConn.SetDeadline(time.Now().Add(t.config.HandshakeTimeout))
+	if err := tlsConn.Handshake(); err != nil {
+		return
+	}
+	_ = tlsConn.SetDeadline(time.Time{})
+
+	id, err := t.verifyPeer(tlsConn.ConnectionState().PeerCertificates)
+	if err != nil {
+		return
+	}
+	p := t.peers[id]
+
+	f, err := readFrame(tlsConn)
+	if err != nil || f.kind != frameKindHello {
+		return
+	}
+	acked := p.resume(binary.BigEndian.Uint64(f.payload), f.seq)
+	if err = writeFrame(tlsConn, &frame{kind: frameKindAck, seq: acked}); err != nil {
+		return
+	}
+
+	for {
+		f, err := readFrame(tlsConn)
+		if err != nil || f.kind != frameKindData {
+			return
+		}
+		acked, ok := p.receive(f)
+		if !ok {
+			return
+		}
+		if err = writeFrame(tlsConn, &frame{kind: frameKindAck, seq: acked}); err != nil {
+			return
+		}
+	}
+}
+
+// resume handles the hello frame of a new connection from p, which announces its epoch and the sequence number first
+// of the first frame it did not see acknowledged. It returns the sequence number up to which all frames were received.
+//
+// If p or this party restarted since the last connection, the epoch differs from the one recorded,
+// and the sequence numbers restart at first.
+func (p *peer) resume(epoch, first uint64) uint64 {
+	p.recvMtx.Lock()
+	defer p.recvMtx.Unlock()
+
+	if epoch != p.recvEpoch || p.received+1 < first {
+		p.recvEpoch = epoch
+		p.received = first - 1
+	}
+	return p.received
+}
+
+// receive delivers the message in f, if it was not already delivered.
+// It returns the sequence number up to which all frames were received,
+// and false if f is out of order, or t was closed.
+func (p *peer) receive(f *frame) (uint64, bool) {
+	p.recvMtx.Lock()
+	defer p.recvMtx.Unlock()
+
+	switch {
+	case f.seq <= p.received:
+		// retransmission of a frame which was delivered before the previous connection was lost
+		return p.received, true
+	case f.seq != p.received+1:
+		return 0, false
+	}
+	p.received = f.seq
+
+	var msg messages.Message
+	if err := msg.UnmarshalBinary(f.payload); err != nil || msg.From != p.id {
+		// the frame is acknowledged, since sending it again would not change the outcome
+		return p.received, true
+	}
+	select {
+	case p.t.incoming <- &msg:
+		return p.received, true
+	case <-p.t.ctx.Done():
+		return 0, false
+	}
+}
+
+func (p *peer) enqueue(payload []byte) {
+	p.mtx.Lock()
+	p.pending = append(p.pending, &frame{
+		kind:    frameKindData,
+		seq:     p.nextSeq,
+		payload: payload,
+	})
+	p.nextSeq++
+	p.mtx.Unlock()
+
+	select {
+	case p.notify <- struct{}{}:
+	default:
+	}
+}
+
+// ack removes all frames up to seq from the queue.
+func (p *peer) ack(seq uint64) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	i := 0
+	for i < len(p.pending) && p.pending[i].seq <= seq {
+		i++
+	}
+	p.pending = p.pending[i:]
+}
+
+// idle returns true if all messages sent to p were acknowledged.
+func (p *peer) idle() bool {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	return len(p.pending) == 0
+}
+
+// hello returns the hello frame sent over a new connection to p.
+func (p *peer) hello() *frame {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	first := p.nextSeq
+	if len(p.pending) > 0 {
+		first = p.pending[0].seq
+	}
+	payload := make([]byte, helloPayloadSize)
+	binary.BigEndian.PutUint64(payload, p.t.epoch)
+	return &frame{kind: frameKindHello, seq: first, payload: payload}
+}
+
+// unsent returns the queued frames with a sequence number greater than sent.
+func (p *peer) unsent(sent uint64) []*frame {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	for i, f := range p.pending {
+		if f.seq > sent {
+			return append([]*frame(nil), p.pending[i:]...)
+		}
+	}
+	return nil
+}
+
+// run maintains the outgoing connection to p, until the transport is closed.
+func (p *peer) run() {
+	defer p.t.wg.Done()
+	for {
+		if tlsConn, conn, err := p.dial(); err == nil {
+			p.serve(tlsConn)
+			p.t.untrackConn(conn)
+		}
+		select {
+		case <-p.t.ctx.Done():
+			return
+		case <-time.After(p.t.config.RetryInterval):
+		}
+	}
+}
+
+// dial establishes a TLS connection to p, and checks that its certificate is bound to p's party.ID.
+// It also returns the underlying connection, which must be released with untrackConn.
+func (p *peer) dial() (*tls.Conn, net.Conn, error) {
+	dialer := net.Dialer{Timeout: p.t.config.HandshakeTimeout}
+	conn, err := dialer.DialContext(p.t.ctx, "tcp", p.address)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !p.t.trackConn(conn) {
+		return nil, nil, ErrClosed
+	}
+
+	tlsConn := tls.Client(conn, &tls.Config{
+		Certificates: []tls.Certificate{p.t.config.Certificate},
+		// The server's certificate is verified in VerifyPeerCertificate, against the party.ID instead of a host name.
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			certs := make([]*x509.Certificate, 0, len(rawCerts))
+			for _, raw := range rawCerts {
+				cert, err := x509.ParseCertificate(raw)
+				if err != nil {
+					return err
+				}
+				certs = append(certs, cert)
+			}
+			id, err := p.t.verifyPeer(certs)
+			if err != nil {
+				return err
+			}
+			if id != p.id {
+				return fmt.Errorf("transport: expected certificate of party %d, got %d", p.id, id)
+			}
+			return nil
+		},
+		MinVersion: tls.VersionTLS12,
+	})
+	_ = tlsConn.SetDeadline(time.Now().Add(p.t.config.HandshakeTimeout))
+	if err = tlsConn.Handshake(); err != nil {
+		p.t.untrackConn(conn)
+		return nil, nil, err
+	}
+	_ = tlsConn.SetDeadline(time.Time{})
+	return tlsConn, conn, nil
+}
+
+// serve sends all queued frames over conn, and processes the acknowledgements, until conn fails.
+// Frames which were not acknowledged by the previous connection are sent again.
+func (p *peer) serve(conn *tls.Conn) {
+	if err := writeFrame(conn, p.hello()); err != nil {
+		return
+	}
+
+	failed := make(chan struct{})
+	go func() {
+		defer close(failed)
+		for {
+			f, err := readFrame(conn)
+			if err != nil || f.kind != frameKindAck {
+				return
+			}
+			p.ack(f.seq)
+		}
+	}()
+	defer func() {
+		_ = conn.Close()
+		<-failed
+	}()
+
+	var sent uint64
+	for {
+		for _, f := range p.unsent(sent) {
+			if err := writeFrame(conn, f); err != nil {
+				return
+			}
+			sent = f.seq
+		}
+		select {
+		case <-p.notify:
+		case <-failed:
+			return
+		case <-p.t.ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,348 @@
+package transport
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/keygen"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/sign"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  ed25519.PrivateKey
+	pool *x509.CertPool
+}
+
+func newTestCA(t *testing.T) *testCA {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return &testCA{cert: cert, key: key, pool: pool}
+}
+
+func (ca *testCA) issue(t *testing.T, id party.ID) tls.Certificate {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(int64(id) + 1),
+		Subject:      pkix.Name{CommonName: id.String()},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, key.Public(), ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// newNetwork creates a TCP transport for each party, where the certificate of each party is issued by cas[id].
+// All parties trust the first CA.
+func newNetwork(t *testing.T, partyIDs party.IDSlice, cas map[party.ID]*testCA) map[party.ID]*TCP {
+	listeners := make(map[party.ID]net.Listener, len(partyIDs))
+	addresses := make(map[party.ID]string, len(partyIDs))
+	for _, id := range partyIDs {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		listeners[id] = l
+		addresses[id] = l.Addr().String()
+	}
+
+	transports := make(map[party.ID]*TCP, len(partyIDs))
+	for _, id := range partyIDs {
+		peers := make(map[party.ID]string, len(partyIDs)-1)
+		for _, otherID := range partyIDs {
+			if otherID != id {
+				peers[otherID] = addresses[otherID]
+			}
+		}
+		tr, err := NewTCPWithListener(listeners[id], Config{
+			ID:            id,
+			Peers:         peers,
+			Certificate:   cas[id].issue(t, id),
+			CAs:           cas[partyIDs[0]].pool,
+			RetryInterval: 20 * time.Millisecond,
+			LingerTimeout: time.Second,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		transports[id] = tr
+	}
+	return transports
+}
+
+func sameCA(t *testing.T, partyIDs party.IDSlice) map[party.ID]*testCA {
+	ca := newTestCA(t)
+	cas := make(map[party.ID]*testCA, len(partyIDs))
+	for _, id := range partyIDs {
+		cas[id] = ca
+	}
+	return cas
+}
+
+func handleAll(t *testing.T, states map[party.ID]*state.State, transports map[party.ID]*TCP) {
+	var wg sync.WaitGroup
+	wg.Add(len(states))
+	for id, s := range states {
+		go func(s *state.State, tr *TCP) {
+			defer wg.Done()
+			if err := Handle(s, tr); err != nil {
+				t.Error(err)
+			}
+		}(s, transports[id])
+	}
+	wg.Wait()
+}
+
+func TestTCP_KeygenSign(t *testing.T) {
+	N := party.Size(4)
+	T := party.Size(2)
+	partyIDs := helpers.GenerateSet(N)
+
+	transports := newNetwork(t, partyIDs, sameCA(t, partyIDs))
+	defer func() {
+		for _, tr := range transports {
+			_ = tr.Close()
+		}
+	}()
+
+	states := map[party.ID]*state.State{}
+	keygenOutputs := map[party.ID]*keygen.Output{}
+	for _, id := range partyIDs {
+		var err error
+		states[id], keygenOutputs[id], err = frost.NewKeygenState(id, partyIDs, T, 10*time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	handleAll(t, states, transports)
+	if t.Failed() {
+		return
+	}
+
+	public := keygenOutputs[partyIDs[0]].Public
+	for _, id := range partyIDs {
+		if !public.Equal(keygenOutputs[id].Public) {
+			t.Fatal("parties computed different public keys")
+		}
+	}
+
+	message := []byte("hello")
+	signIDs := partyIDs[:T+1]
+	states = map[party.ID]*state.State{}
+	signOutputs := map[party.ID]*sign.Output{}
+	for _, id := range signIDs {
+		var err error
+		states[id], signOutputs[id], err = frost.NewSignState(signIDs, keygenOutputs[id].SecretKey, public, message, 10*time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	handleAll(t, states, transports)
+	if t.Failed() {
+		return
+	}
+
+	pk := public.GroupKey
+	for _, id := range signIDs {
+		if !pk.Verify(message, signOutputs[id].Signature) {
+			t.Error("signature failed to verify")
+		}
+	}
+}
+
+func TestTCP_Reconnect(t *testing.T) {
+	partyIDs := party.IDSlice{1, 2}
+	transports := newNetwork(t, partyIDs, sameCA(t, partyIDs))
+	defer func() {
+		for _, tr := range transports {
+			_ = tr.Close()
+		}
+	}()
+
+	const n = 100
+	go func() {
+		for i := 0; i < n; i++ {
+			if i%10 == 0 {
+				transports[1].closeConns()
+				transports[2].closeConns()
+			}
+			if err := transports[1].Send(messages.NewRepair1(1, 2, party.ID(i+1).Scalar())); err != nil {
+				t.Error(err)
+			}
+		}
+	}()
+	for i := 0; i < n; i++ {
+		select {
+		case msg := <-transports[2].Incoming():
+			if msg.From != 1 || msg.Repair1 == nil {
+				t.Fatal("unexpected message")
+			}
+			if msg.Repair1.Share.Equal(party.ID(i+1).Scalar()) != 1 {
+				t.Fatalf("message %d delivered out of order", i)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatalf("message %d was not delivered", i)
+		}
+	}
+}
+
+func TestTCP_UntrustedCertificate(t *testing.T) {
+	partyIDs := party.IDSlice{1, 2}
+	cas := map[party.ID]*testCA{
+		1: newTestCA(t),
+		2: newTestCA(t),
+	}
+	transports := newNetwork(t, partyIDs, cas)
+	defer func() {
+		for _, tr := range transports {
+			tr.config.LingerTimeout = -1
+			_ = tr.Close()
+		}
+	}()
+
+	if err := transports[2].Send(messages.NewRepair1(2, 1, ristretto.NewScalar())); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-transports[1].Incoming():
+		t.Fatal("message from a party with an untrusted certificate was delivered")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+// restart closes tr and returns a new TCP with the same configuration, listening on address.
+func restart(t *testing.T, tr *TCP, address string) *TCP {
+	_ = tr.Close()
+	l, err := net.Listen("tcp", address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr, err = NewTCPWithListener(l, tr.config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tr
+}
+
+func expectRepair1(t *testing.T, tr *TCP, from party.ID, share *ristretto.Scalar) {
+	select {
+	case msg := <-tr.Incoming():
+		if msg.From != from || msg.Repair1 == nil || msg.Repair1.Share.Equal(share) != 1 {
+			t.Fatal("unexpected message")
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("message was not delivered")
+	}
+}
+
+func TestTCP_Restart(t *testing.T) {
+	partyIDs := party.IDSlice{1, 2}
+	transports := newNetwork(t, partyIDs, sameCA(t, partyIDs))
+	defer func() {
+		for _, tr := range transports {
+			_ = tr.Close()
+		}
+	}()
+
+	send := func(i int) {
+		if err := transports[1].Send(messages.NewRepair1(1, 2, party.ID(i).Scalar())); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 1; i <= 3; i++ {
+		send(i)
+		expectRepair1(t, transports[2], 1, party.ID(i).Scalar())
+	}
+
+	// The sender restarts, and numbers its messages from 1 again
+	transports[1] = restart(t, transports[1], "127.0.0.1:0")
+	send(4)
+	expectRepair1(t, transports[2], 1, party.ID(4).Scalar())
+
+	// The recipient restarts, and receives the following messages
+	send(5)
+	expectRepair1(t, transports[2], 1, party.ID(5).Scalar())
+	transports[2] = restart(t, transports[2], transports[2].Addr().String())
+	send(6)
+	expectRepair1(t, transports[2], 1, party.ID(6).Scalar())
+}
+
+func TestTCP_ExtKeyUsage(t *testing.T) {
+	partyIDs := party.IDSlice{1, 2}
+	cas := sameCA(t, partyIDs)
+	transports := newNetwork(t, partyIDs, cas)
+	defer func() {
+		for _, tr := range transports {
+			tr.config.LingerTimeout = -1
+			_ = tr.Close()
+		}
+	}()
+
+	// A certificate which is only valid for code signing is refused
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: party.ID(2).String()},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, cas[2].cert, key.Public(), cas[2].key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = transports[1].verifyPeer([]*x509.Certificate{cert}); err == nil {
+		t.Fatal("certificate without client and server authentication was accepted")
+	}
+}
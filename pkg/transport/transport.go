@@ -0,0 +1,118 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+// ErrClosed is returned when using a Transport which was closed.
+var ErrClosed = errors.New("transport: closed")
+
+// Transport delivers messages between the parties of a protocol.
+//
+// Implementations must deliver the messages of a given sender in the order in which they were sent,
+// and must guarantee that msg.From is the party which actually sent the message.
+type Transport interface {
+	// Send delivers msg to msg.To, or to all other parties if msg is a broadcast message.
+	// It may return before the message was received by the peer.
+	Send(msg *messages.Message) error
+
+	// Incoming returns the channel on which messages from other parties are delivered.
+	// It is closed after Close was called.
+	Incoming() <-chan *messages.Message
+
+	// Close stops the transport and releases all its resources.
+	Close() error
+}
+
+// Handle drives s with the messages received from t, until the protocol finishes.
+// It is equivalent to Run without a context or progress callback.
+func Handle(s *state.State, t Transport) error {
+	return Run(context.Background(), s, t, nil)
+}
+
+// Run drives s with the messages received from t, until the protocol finishes.
+// Messages produced by s are sent over t.
+// It returns the error with which the protocol aborted, or nil if it finished successfully.
+//
+// If ctx is cancelled or its deadline expires before the protocol finishes, s is aborted with ctx.Err().
+// Whenever Run returns, s is finished, so that the secrets of the round have been erased.
+//
+// If progress is not nil, it is called from the goroutine executing Run every time s advances,
+// that is when a message is accepted or a new round starts, and once more when s is finished.
+//
+// Errors returned by s.HandleMessage for individual messages are ignored,
+// since the State aborts by itself when the protocol can no longer continue.
+func Run(ctx context.Context, s *state.State, t Transport, progress func(state.Progress)) error {
+	var last state.Progress
+	report := func() {
+		if p := s.Progress(); progress != nil && p != last {
+			last = p
+			progress(p)
+		}
+	}
+	defer func() {
+		// Also covers panics, and a ctx which was cancelled at the same time as the protocol finished
+		if !s.IsFinished() {
+			s.Abort(errors.New("transport: execution was interrupted"))
+		}
+		report()
+	}()
+
+	if err := processAll(s, t); err != nil {
+		return abort(s, err)
+	}
+	report()
+
+	for {
+		select {
+		case msg, ok := <-t.Incoming():
+			if !ok {
+				return abort(s, ErrClosed)
+			}
+			_ = s.HandleMessage(msg)
+			if err := processAll(s, t); err != nil {
+				return abort(s, err)
+			}
+			report()
+		case <-s.Proceed():
+			// The round can be completed without the messages which did not arrive in time
+			if err := processAll(s, t); err != nil {
+				return abort(s, err)
+			}
+			report()
+		case <-s.Done():
+			return s.Err()
+		case <-ctx.Done():
+			return abort(s, ctx.Err())
+		}
+	}
+}
+
+// abort stops s with err, and returns the error with which s has finished.
+// This may differ from err if s finished in the meantime.
+func abort(s *state.State, err error) error {
+	s.Abort(err)
+	return s.Err()
+}
+
+// processAll calls s.ProcessAll and sends the resulting messages over t.
+// Messages for the next round may already have been queued by s,
+// so we keep processing for as long as s advances to a new round.
+func processAll(s *state.State, t Transport) error {
+	for {
+		round := s.GetRoundNumber()
+		for _, msg := range s.ProcessAll() {
+			if err := t.Send(msg); err != nil {
+				return fmt.Errorf("transport: failed to send message: %w", err)
+			}
+		}
+		if s.GetRoundNumber() == round {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+// memTransport delivers messages between parties in the same process.
+type memTransport struct {
+	id       party.ID
+	network  map[party.ID]*memTransport
+	incoming chan *messages.Message
+	once     sync.Once
+}
+
+func newMemNetwork(partyIDs party.IDSlice) map[party.ID]*memTransport {
+	network := make(map[party.ID]*memTransport, len(partyIDs))
+	for _, id := range partyIDs {
+		network[id] = &memTransport{
+			id:       id,
+			network:  network,
+			incoming: make(chan *messages.Message, 100),
+		}
+	}
+	return network
+}
+
+func (t *memTransport) Send(msg *messages.Message) error {
+	data, err := msg.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	for id, peer := range t.network {
+		if id == t.id || (!msg.IsBroadcast() && id != msg.To) {
+			continue
+		}
+		var received messages.Message
+		if err = received.UnmarshalBinary(data); err != nil {
+			return err
+		}
+		peer.incoming <- &received
+	}
+	return nil
+}
+
+func (t *memTransport) Incoming() <-chan *messages.Message {
+	return t.incoming
+}
+
+func (t *memTransport) Close() error {
+	t.once.Do(func() { close(t.incoming) })
+	return nil
+}
+
+func TestRun_Progress(t *testing.T) {
+	partyIDs := helpers.GenerateSet(3)
+	network := newMemNetwork(partyIDs)
+
+	states := map[party.ID]*state.State{}
+	progress := map[party.ID][]state.Progress{}
+	for _, id := range partyIDs {
+		var err error
+		if states[id], _, err = frost.NewKeygenState(id, partyIDs, 1, 0); err != nil {
+			t.Fatal(err)
+		}
+		progress[id] = nil
+	}
+
+	var mtx sync.Mutex
+	var wg sync.WaitGroup
+	wg.Add(len(partyIDs))
+	for _, id := range partyIDs {
+		go func(id party.ID) {
+			defer wg.Done()
+			err := Run(context.Background(), states[id], network[id], func(p state.Progress) {
+				mtx.Lock()
+				progress[id] = append(progress[id], p)
+				mtx.Unlock()
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}(id)
+	}
+	wg.Wait()
+
+	for id, reported := range progress {
+		if len(reported) == 0 || !reported[len(reported)-1].Finished {
+			t.Fatalf("party %d: last progress report does not indicate the end of the protocol: %v", id, reported)
+		}
+		for i, p := range reported {
+			if i > 0 && p.Round < reported[i-1].Round {
+				t.Errorf("party %d: round number decreased: %v", id, reported)
+			}
+			if p.Received > p.Expected {
+				t.Errorf("party %d: received more messages than expected: %v", id, p)
+			}
+		}
+	}
+}
+
+func TestRun_Cancel(t *testing.T) {
+	partyIDs := helpers.GenerateSet(3)
+	network := newMemNetwork(partyIDs)
+
+	// The last party never runs, so the protocol cannot finish
+	states := map[party.ID]*state.State{}
+	for _, id := range partyIDs[:2] {
+		var err error
+		if states[id], _, err = frost.NewKeygenState(id, partyIDs, 1, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	var wg sync.WaitGroup
+	wg.Add(len(states))
+	for id, s := range states {
+		go func(s *state.State, tr Transport) {
+			defer wg.Done()
+			if err := Run(ctx, s, tr, nil); !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("expected context.DeadlineExceeded, got %v", err)
+			}
+		}(s, network[id])
+	}
+	wg.Wait()
+
+	for _, s := range states {
+		if !s.IsFinished() {
+			t.Error("state was not finished after cancellation")
+		}
+		if err := s.WaitForError(); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected context.DeadlineExceeded, got %v", err)
+		}
+	}
+}
+
+func TestRun_Resumed(t *testing.T) {
+	partyIDs := helpers.GenerateSet(3)
+	network := newMemNetwork(partyIDs)
+
+	s, _, err := frost.NewKeygenState(1, partyIDs, 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := s.Suspend()
+	if err != nil {
+		t.Fatal(err)
+	}
+	resumed, err := state.Resume(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err = Run(ctx, resumed, network[1], nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+
+	// A State which was resumed after it finished must not block either
+	data, err = resumed.Suspend()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resumed, err = state.Resume(data); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- resumed.WaitForError() }()
+	select {
+	case err = <-done:
+		if err == nil {
+			t.Error("the error of the aborted protocol was lost")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("WaitForError blocked on a finished State")
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"context"
 	"crypto/ed25519"
 	"encoding/base64"
-	"encoding/json"
 	"fmt"
 	"github.com/blocto/solana-go-sdk/client"
 	sdkRpc "github.com/blocto/solana-go-sdk/rpc"
@@ -22,84 +21,29 @@ import (
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/sign"
 	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/keystore"
 	"github.com/taurusgroup/frost-ed25519/pkg/state"
 	"log"
 	"strings"
 )
 
-type Secret struct {
-	ID     int    `json:"id"`
-	Secret string `json:"secret"`
-}
-
-type Shares struct {
-	T        int               `json:"t"`
-	GroupKey string            `json:"groupkey"`
-	Shares   map[string]string `json:"shares"`
-}
-
-type CombinedOutput struct {
-	Secrets map[string]Secret `json:"Secrets"`
-	Shares  Shares            `json:"Shares"`
-}
-
-type FKeyGenOutput struct {
-	Secrets map[party.ID]*eddsa.SecretShare
-	Shares  *eddsa.Public
-}
-
-func mergeJson(slices [][]byte) ([]byte, error) {
-	combinedOutput := CombinedOutput{
-		Secrets: make(map[string]Secret),
-		Shares:  Shares{Shares: make(map[string]string)},
-	}
-
-	var err error
-	for i := 0; i < len(slices); i++ {
-		data := slices[i]
-
-		var output CombinedOutput
-		err = json.Unmarshal(data, &output)
+// parseKeyShares 解析以逗号分隔的 keystore 密钥分片（例如 ed25519.DKGSlice 生成的分片），所有分片必须属于同一个组公钥
+func parseKeyShares(keys string) (map[party.ID]*eddsa.SecretShare, *eddsa.Public, error) {
+	secrets := make(map[party.ID]*eddsa.SecretShare)
+	var public *eddsa.Public
+	for _, key := range strings.Split(keys, ",") {
+		keyShare, err := keystore.ParseKeyShare([]byte(key), 0)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
-
-		// Merge the secrets from each file
-		for key, secret := range output.Secrets {
-			combinedOutput.Secrets[key] = secret
-		}
-
-		// Merge the shares from each file
-		for key, value := range output.Shares.Shares {
-			combinedOutput.Shares.Shares[key] = value
-		}
-
-		// Update other fields if needed
-		if combinedOutput.Shares.T == 0 {
-			combinedOutput.Shares.T = output.Shares.T
-			combinedOutput.Shares.GroupKey = output.Shares.GroupKey
-		}
-	}
-
-	combinedJSON, err := json.MarshalIndent(combinedOutput, "", "  ")
-	if err != nil {
-		return nil, err
-	}
-
-	return combinedJSON, nil
-}
-
-func decode2Bytes(data string) ([][]byte, error) {
-	base64Strings := strings.Split(data, ",")
-	var bytesSlices [][]byte
-	for _, str := range base64Strings {
-		decodedBytes, err := base64.StdEncoding.DecodeString(str)
-		if err != nil {
-			return nil, err // Handle error if decoding fails
+		if public == nil {
+			public = keyShare.Public
+		} else if !public.GroupKey.Equal(keyShare.Public.GroupKey) {
+			return nil, nil, fmt.Errorf("key share of party %d belongs to another group key", keyShare.Secret.ID)
 		}
-		bytesSlices = append(bytesSlices, decodedBytes)
+		secrets[keyShare.Secret.ID] = keyShare.Secret
 	}
-	return bytesSlices, nil
+	return secrets, public, nil
 }
 
 func buildSolanaTransactionMsg(from string, to string, amount uint64) string {
@@ -169,7 +113,7 @@ func buildSolanaTransactionMsg(from string, to string, amount uint64) string {
 func solanaTransactionSignature(keys string, messageStr string, toEd25519 bool) string {
 
 	// MPC 签名
-	slices, err := decode2Bytes(keys)
+	secretShares, publicShares, err := parseKeyShares(keys)
 	if err != nil {
 		fmt.Println(err)
 		return ""
@@ -177,48 +121,21 @@ func solanaTransactionSignature(keys string, messageStr string, toEd25519 bool)
 
 	message := []byte(messageStr)
 
-	if slices == nil {
-		fmt.Println("verify failed slices is nil")
-		return ""
+	// 使用提供的所有分片签名，至少需要 t+1 个分片
+	ids := make([]party.ID, 0, len(secretShares))
+	for id := range secretShares {
+		ids = append(ids, id)
 	}
-
-	mjson, err := mergeJson(slices)
-
-	fmt.Println("msg: ", len(message))
-	fmt.Println("merged: ", string(mjson))
-	fmt.Println("error: ", err)
-
-	var kgOutput FKeyGenOutput
-
-	var jsonData []byte = mjson
-	//jsonData, err = ioutil.ReadFile(filename)
-	if err != nil {
-		fmt.Println(err)
+	partyIDs := party.NewIDSlice(ids)
+	n := partyIDs.N()
+	t := publicShares.Threshold
+	if n <= t {
+		fmt.Printf("need at least %v key shares, got %v\n", t+1, n)
 		return ""
 	}
 
-	fmt.Println("json: --- ", string(mjson), err)
-
-	err = json.Unmarshal(jsonData, &kgOutput)
-	if err != nil {
-		fmt.Println(err)
-		return ""
-	}
-
-	// get n and t from the keygen output
-	var n party.Size
-	var t party.Size
-
-	n = kgOutput.Shares.PartyIDs.N()
-	t = kgOutput.Shares.Threshold
-
 	fmt.Printf("(t, n) = (%v, %v)\n", t, n)
 
-	partyIDs := helpers.GenerateSet(n)
-
-	secretShares := kgOutput.Secrets
-	publicShares := kgOutput.Shares
-
 	// structure holding parties' state and output
 	states := map[party.ID]*state.State{}
 	outputs := map[party.ID]*sign.Output{}
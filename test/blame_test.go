@@ -0,0 +1,160 @@
+package main
+
+import (
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/sign"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+// corruptSign2 replaces the signature share of all Sign2 messages sent by a culprit.
+func corruptSign2(t *testing.T, msgs [][]byte, culprits party.IDSlice) [][]byte {
+	out := make([][]byte, 0, len(msgs))
+	for _, data := range msgs {
+		var msg messages.Message
+		if err := msg.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+		if msg.Type == messages.MessageTypeSign2 && culprits.Contains(msg.From) {
+			random := make([]byte, 64)
+			if _, err := rand.Read(random); err != nil {
+				t.Fatal(err)
+			}
+			_, _ = msg.Sign2.Zi.SetUniformBytes(random)
+			var err error
+			if data, err = msg.MarshalBinary(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		out = append(out, data)
+	}
+	return out
+}
+
+func runSign(t *testing.T, states map[party.ID]*state.State, culprits party.IDSlice) {
+	msgsOut1 := make([][]byte, 0, len(states))
+	msgsOut2 := make([][]byte, 0, len(states))
+	for _, s := range states {
+		msgs1, err := helpers.PartyRoutine(nil, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgsOut1 = append(msgsOut1, msgs1...)
+	}
+	for _, s := range states {
+		msgs2, err := helpers.PartyRoutine(msgsOut1, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgsOut2 = append(msgsOut2, msgs2...)
+	}
+	msgsOut2 = corruptSign2(t, msgsOut2, culprits)
+	for _, s := range states {
+		// errors are checked later with WaitForError
+		_, _ = helpers.PartyRoutine(msgsOut2, s)
+	}
+}
+
+func TestSignIdentifiableAbort(t *testing.T) {
+	N := party.Size(7)
+	T := party.Size(3)
+
+	partyIDs, _, secretShares, publicShares := setupParties(T, N)
+	signSet := partyIDs[:T+3]
+	culprits := party.NewIDSlice([]party.ID{signSet[1], signSet[4]})
+	// The restarted session must keep the options of the aborted one
+	opts := &sign.Options{Signature: &eddsa.Options{Variant: eddsa.VariantCtx, Context: []byte("restart")}}
+
+	states := map[party.ID]*state.State{}
+	for _, id := range signSet {
+		var err error
+		states[id], _, err = frost.NewSignStateWithOptions(signSet, secretShares[id], publicShares, MESSAGE, opts, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		states[id].SetIdentifiableAbort(true)
+	}
+
+	runSign(t, states, culprits)
+
+	// Every honest party must blame exactly the culprits
+	restarted := map[party.ID]*state.State{}
+	outputs := map[party.ID]*sign.Output{}
+	for _, id := range signSet {
+		if culprits.Contains(id) {
+			continue
+		}
+		err := states[id].WaitForError()
+		var blame *state.Blame
+		if !errors.As(err, &blame) {
+			t.Fatalf("party %d: expected a blame report, got %v", id, err)
+		}
+		if !blame.PartyIDs().Equal(culprits) {
+			t.Fatalf("party %d: blamed %v instead of %v", id, blame.PartyIDs(), culprits)
+		}
+		for _, culprit := range blame.Culprits {
+			if !errors.Is(culprit, sign.ErrValidateSigShare) {
+				t.Errorf("party %d: unexpected reason %v", id, culprit)
+			}
+			if len(culprit.Message) == 0 {
+				t.Errorf("party %d: blame does not contain the offending message", id)
+			}
+		}
+
+		// The blame report survives suspending the aborted state
+		data, err := states[id].Suspend()
+		if err != nil {
+			t.Fatal(err)
+		}
+		resumed, err := state.Resume(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = resumed.WaitForError()
+		var resumedBlame *state.Blame
+		if !errors.As(err, &resumedBlame) {
+			t.Fatalf("party %d: expected a blame report after Resume, got %v", id, err)
+		}
+		if !resumedBlame.PartyIDs().Equal(culprits) || resumedBlame.Error() != blame.Error() {
+			t.Fatalf("party %d: blame changed after Resume: %v", id, resumedBlame)
+		}
+		for _, culprit := range resumedBlame.Culprits {
+			if !errors.Is(culprit, sign.ErrValidateSigShare) {
+				t.Errorf("party %d: unexpected reason after Resume %v", id, culprit)
+			}
+		}
+
+		restarted[id], outputs[id], err = frost.RestartSignState(err, signSet, secretShares[id], publicShares, MESSAGE, opts, messages.SessionID{1}, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The session can now complete with the remaining honest signers
+	runSign(t, restarted, nil)
+	pk := publicShares.GroupKey
+	for id, s := range restarted {
+		if err := s.WaitForError(); err != nil {
+			t.Fatal(err)
+		}
+		if !pk.VerifyWithOptions(MESSAGE, outputs[id].Signature, opts.Signature) {
+			t.Error("signature failed to verify")
+		}
+		if pk.Verify(MESSAGE, outputs[id].Signature) {
+			t.Error("signature should not verify without the context")
+		}
+	}
+
+	// A culprit can not restart the session
+	_, _, err := frost.RestartSignState(states[signSet[0]].Err(), signSet, secretShares[culprits[0]], publicShares, MESSAGE, opts, messages.SessionID{1}, 0)
+	if err == nil {
+		t.Error("a blamed party should not be able to restart")
+	}
+}
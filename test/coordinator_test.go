@@ -0,0 +1,221 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"testing"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/sign"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+// runCoordinatedSign runs a signing session in which the signers only communicate with the coordinator.
+// It returns the number of messages sent by each party.
+func runCoordinatedSign(t *testing.T, coordinator *state.State, signers map[party.ID]*state.State, culprits party.IDSlice) map[party.ID]int {
+	sent := map[party.ID]int{}
+	count := func(msgs [][]byte) {
+		for _, data := range msgs {
+			var msg messages.Message
+			if err := msg.UnmarshalBinary(data); err != nil {
+				t.Fatal(err)
+			}
+			sent[msg.From]++
+		}
+	}
+
+	// Sign1 from the signers to the coordinator
+	var msgsSign1 [][]byte
+	for _, s := range signers {
+		msgs, err := helpers.PartyRoutine(nil, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgsSign1 = append(msgsSign1, msgs...)
+	}
+	if _, err := helpers.PartyRoutine(nil, coordinator); err != nil {
+		t.Fatal(err)
+	}
+	count(msgsSign1)
+
+	// Commitment list from the coordinator to the signers
+	msgsCommitments, err := helpers.PartyRoutine(msgsSign1, coordinator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count(msgsCommitments)
+
+	// Sign2 from the signers to the coordinator
+	var msgsSign2 [][]byte
+	for _, s := range signers {
+		msgs, err := helpers.PartyRoutine(msgsCommitments, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgsSign2 = append(msgsSign2, msgs...)
+	}
+	count(msgsSign2)
+
+	// errors are checked later with WaitForError
+	_, _ = helpers.PartyRoutine(corruptSign2(t, msgsSign2, culprits), coordinator)
+	return sent
+}
+
+func TestSignCoordinator(t *testing.T) {
+	N := party.Size(6)
+	T := party.Size(2)
+
+	partyIDs, _, secretShares, publicShares := setupParties(T, N)
+	signSet := partyIDs[:T+2]
+	// The coordinator does not hold a share of the key
+	coordinatorID := partyIDs[N-1] + 1
+
+	for _, opts := range []sign.Options{
+		{Coordinator: coordinatorID},
+		{Coordinator: coordinatorID, Mode: sign.ModeRFC9591, Ciphersuite: eddsa.CiphersuiteRistretto255},
+	} {
+		opts := opts
+		coordinator, output, err := frost.NewCoordinatorState(coordinatorID, signSet, publicShares, MESSAGE, &opts, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signers := map[party.ID]*state.State{}
+		signerOutputs := map[party.ID]*sign.Output{}
+		for _, id := range signSet {
+			if signers[id], signerOutputs[id], err = frost.NewSignStateWithOptions(signSet, secretShares[id], publicShares, MESSAGE, &opts, 0); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		sent := runCoordinatedSign(t, coordinator, signers, nil)
+
+		if err = coordinator.WaitForError(); err != nil {
+			t.Fatal(err)
+		}
+		if !opts.Ciphersuite.Verify(publicShares.GroupKey, MESSAGE, output.Signature) {
+			t.Error("signature failed to verify")
+		}
+		if opts.Mode == sign.ModeLegacy && !ed25519.Verify(publicShares.GroupKey.ToEd25519(), MESSAGE, output.Signature.ToEd25519()) {
+			t.Error("sig ed25519 failed")
+		}
+		for id, s := range signers {
+			if err = s.WaitForError(); err != nil {
+				t.Fatal(err)
+			}
+			if signerOutputs[id].Signature != nil {
+				t.Errorf("party %d: signers should not obtain the signature", id)
+			}
+			if sent[id] != 2 {
+				t.Errorf("party %d: sent %d messages instead of 2", id, sent[id])
+			}
+		}
+		if sent[coordinatorID] != len(signSet) {
+			t.Errorf("coordinator sent %d messages instead of %d", sent[coordinatorID], len(signSet))
+		}
+	}
+
+	// The coordinator must not be a signer
+	opts := &sign.Options{Coordinator: signSet[0]}
+	if _, _, err := frost.NewSignStateWithOptions(signSet, secretShares[signSet[0]], publicShares, MESSAGE, opts, 0); err == nil {
+		t.Error("expected error when the coordinator is a signer")
+	}
+	if _, _, err := frost.NewCoordinatorState(signSet[0], signSet, publicShares, MESSAGE, nil, 0); err == nil {
+		t.Error("expected error when the coordinator is a signer")
+	}
+	// Threshold+1 signers are required
+	if _, _, err := frost.NewCoordinatorState(coordinatorID, signSet[:T], publicShares, MESSAGE, nil, 0); err == nil {
+		t.Error("expected error with too few signers")
+	}
+}
+
+func TestSignCoordinatorBlame(t *testing.T) {
+	N := party.Size(5)
+	T := party.Size(2)
+
+	partyIDs, signSet, secretShares, publicShares := setupParties(T, N)
+	coordinatorID := partyIDs[N-1] + 1
+	culprits := party.IDSlice{signSet[1]}
+	opts := &sign.Options{Coordinator: coordinatorID}
+
+	coordinator, _, err := frost.NewCoordinatorState(coordinatorID, signSet, publicShares, MESSAGE, opts, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	coordinator.SetIdentifiableAbort(true)
+
+	// The coordinator can be suspended and resumed between rounds
+	data, err := coordinator.Suspend()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if coordinator, err = state.Resume(data); err != nil {
+		t.Fatal(err)
+	}
+	coordinator.SetIdentifiableAbort(true)
+
+	signers := map[party.ID]*state.State{}
+	for _, id := range signSet {
+		if signers[id], _, err = frost.NewSignStateWithOptions(signSet, secretShares[id], publicShares, MESSAGE, opts, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	runCoordinatedSign(t, coordinator, signers, culprits)
+
+	var blame *state.Blame
+	if err = coordinator.WaitForError(); !errors.As(err, &blame) {
+		t.Fatalf("expected a blame report, got %v", err)
+	}
+	if !blame.PartyIDs().Equal(culprits) {
+		t.Fatalf("blamed %v instead of %v", blame.PartyIDs(), culprits)
+	}
+	for _, culprit := range blame.Culprits {
+		if !errors.Is(culprit, sign.ErrValidateSigShare) {
+			t.Errorf("unexpected reason %v", culprit)
+		}
+	}
+}
+
+func TestSignCoordinatorCommitmentList(t *testing.T) {
+	N := party.Size(4)
+	T := party.Size(1)
+
+	partyIDs, signSet, secretShares, publicShares := setupParties(T, N)
+	coordinatorID := partyIDs[N-1] + 1
+	opts := &sign.Options{Coordinator: coordinatorID}
+
+	signer, _, err := frost.NewSignStateWithOptions(signSet, secretShares[signSet[0]], publicShares, MESSAGE, opts, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgs, err := helpers.PartyRoutine(nil, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sign1 messages.Message
+	if err = sign1.UnmarshalBinary(msgs[0]); err != nil {
+		t.Fatal(err)
+	}
+	if sign1.To != coordinatorID {
+		t.Fatal("Sign1 was not addressed to the coordinator")
+	}
+
+	// The coordinator swaps our commitments Dᵢ and Eᵢ
+	other := *sign1.Sign1
+	other.Di, other.Ei = sign1.Sign1.Ei, sign1.Sign1.Di
+	list := messages.NewSignCommitments(coordinatorID, signSet[0], map[party.ID]*messages.Sign1{
+		signSet[0]: &other,
+		signSet[1]: sign1.Sign1,
+	})
+	data, err := list.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = helpers.PartyRoutine([][]byte{data}, signer); !errors.Is(err, sign.ErrCommitmentList) {
+		t.Errorf("expected sign.ErrCommitmentList, got %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/keygen"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/sign"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+func TestSignDerived(t *testing.T) {
+	N := party.Size(5)
+	T := party.Size(2)
+
+	partyIDs := helpers.GenerateSet(N)
+
+	states := map[party.ID]*state.State{}
+	outputs := map[party.ID]*keygen.Output{}
+	for _, id := range partyIDs {
+		var err error
+		states[id], outputs[id], err = frost.NewKeygenState(id, partyIDs, T, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var msgsIn [][]byte
+	for round := 0; round < 3; round++ {
+		msgsOut := make([][]byte, 0, N*N)
+		for _, s := range states {
+			msgs, err := helpers.PartyRoutine(msgsIn, s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			msgsOut = append(msgsOut, msgs...)
+		}
+		msgsIn = msgsOut
+	}
+
+	// All parties agree on the same chain code
+	public := outputs[partyIDs[0]].Public
+	if len(public.ChainCode) != eddsa.ChainCodeSize {
+		t.Fatal("keygen did not produce a chain code")
+	}
+	for _, id := range partyIDs {
+		if err := states[id].WaitForError(); err != nil {
+			t.Fatal(err)
+		}
+		if !outputs[id].Public.Equal(public) {
+			t.Fatal("parties disagree on the public data")
+		}
+	}
+
+	path := eddsa.DerivationPath{44, 501, 0}
+	child, _, err := public.Derive(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if child.GroupKey.Equal(public.GroupKey) {
+		t.Fatal("child key should differ from the parent")
+	}
+
+	signSet := partyIDs[1 : T+2]
+	signStates := map[party.ID]*state.State{}
+	signOutputs := map[party.ID]*sign.Output{}
+	for _, id := range signSet {
+		signStates[id], signOutputs[id], err = frost.NewSignStateWithOptions(signSet, outputs[id].SecretKey, public, MESSAGE, &sign.Options{Path: path}, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	runSign(t, signStates, nil)
+
+	for id, s := range signStates {
+		if err := s.WaitForError(); err != nil {
+			t.Fatal(err)
+		}
+		sig := signOutputs[id].Signature
+		if !ed25519.Verify(child.GroupKey.ToEd25519(), MESSAGE, sig.ToEd25519()) {
+			t.Error("signature does not verify under the child key")
+		}
+		if ed25519.Verify(public.GroupKey.ToEd25519(), MESSAGE, sig.ToEd25519()) {
+			t.Error("signature should not verify under the parent key")
+		}
+	}
+}
@@ -44,6 +44,8 @@ func (h *Handler) HandleMessage() {
 				//fmt.Println("handle message", err)
 			}
 			h.ProcessAll()
+		case <-h.State.Proceed():
+			h.ProcessAll()
 		case <-h.State.Done():
 			err := h.State.Err()
 			if err != nil {
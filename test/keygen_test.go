@@ -3,12 +3,14 @@ package main
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
 	"github.com/taurusgroup/frost-ed25519/pkg/frost"
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/keygen"
 	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
 	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
 	"github.com/taurusgroup/frost-ed25519/pkg/ristretto"
 	"github.com/taurusgroup/frost-ed25519/pkg/state"
 )
@@ -115,6 +117,495 @@ func CompareOutput(groupKey1, groupKey2 *eddsa.PublicKey, publicShares1, publicS
 	return nil
 }
 
+func TestKeygenCorruptedShare(t *testing.T) {
+	N := party.Size(5)
+	T := party.Size(2)
+
+	partyIDs := helpers.GenerateSet(N)
+	culprit, victim := partyIDs[1], partyIDs[3]
+
+	states := map[party.ID]*state.State{}
+	for _, id := range partyIDs {
+		var err error
+		states[id], _, err = frost.NewKeygenState(id, partyIDs, T, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	msgsOut1 := make([][]byte, 0, N)
+	for _, s := range states {
+		msgs1, err := helpers.PartyRoutine(nil, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgsOut1 = append(msgsOut1, msgs1...)
+	}
+	msgsOut2 := make([][]byte, 0, N*(N-1))
+	for _, s := range states {
+		msgs2, err := helpers.PartyRoutine(msgsOut1, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgsOut2 = append(msgsOut2, msgs2...)
+	}
+
+	// Tamper with the encrypted share sent by the culprit to the victim
+	for i, data := range msgsOut2 {
+		var msg messages.Message
+		if err := msg.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+		if msg.From == culprit && msg.To == victim {
+			msg.KeyGen2.Ciphertext[0] ^= 1
+			var err error
+			if msgsOut2[i], err = msg.MarshalBinary(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	for _, s := range states {
+		_, _ = helpers.PartyRoutine(msgsOut2, s)
+	}
+
+	err := states[victim].WaitForError()
+	var stateErr *state.Error
+	if !errors.As(err, &stateErr) {
+		t.Fatalf("expected a state.Error, got %v", err)
+	}
+	if stateErr.PartyID != culprit || !errors.Is(err, keygen.ErrDecryptShare) {
+		t.Errorf("expected decryption error attributed to %d, got %v", culprit, err)
+	}
+	for _, id := range partyIDs {
+		if id == victim {
+			continue
+		}
+		if err = states[id].WaitForError(); err != nil {
+			t.Errorf("party %d: %v", id, err)
+		}
+	}
+}
+
+func TestKeygenEquivocation(t *testing.T) {
+	N := party.Size(5)
+	T := party.Size(2)
+
+	partyIDs := helpers.GenerateSet(N)
+	culprit, victim := partyIDs[2], partyIDs[0]
+
+	states := map[party.ID]*state.State{}
+	for _, id := range partyIDs {
+		var err error
+		states[id], _, err = frost.NewKeygenState(id, partyIDs, T, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	msgsOut1 := make([][]byte, 0, N)
+	for _, s := range states {
+		msgs1, err := helpers.PartyRoutine(nil, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgsOut1 = append(msgsOut1, msgs1...)
+	}
+
+	// The culprit sends a different, but valid, KeyGen1 message to the victim
+	other, _, err := frost.NewKeygenState(culprit, partyIDs, T, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherMsgs, err := helpers.PartyRoutine(nil, other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	victimMsgs1 := make([][]byte, 0, N)
+	for _, data := range msgsOut1 {
+		var msg messages.Message
+		if err = msg.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+		if msg.From == culprit {
+			data = otherMsgs[0]
+		}
+		victimMsgs1 = append(victimMsgs1, data)
+	}
+
+	msgsOut2 := make([][]byte, 0, N*(N-1))
+	for id, s := range states {
+		in := msgsOut1
+		if id == victim {
+			in = victimMsgs1
+		}
+		msgs2, err := helpers.PartyRoutine(in, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgsOut2 = append(msgsOut2, msgs2...)
+	}
+	for _, s := range states {
+		_, _ = helpers.PartyRoutine(msgsOut2, s)
+	}
+
+	// Every honest party detects the inconsistency and blames the culprit
+	for _, id := range partyIDs {
+		if id == culprit {
+			continue
+		}
+		err = states[id].WaitForError()
+		var stateErr *state.Error
+		if !errors.As(err, &stateErr) {
+			t.Fatalf("party %d: expected a state.Error, got %v", id, err)
+		}
+		if stateErr.PartyID != culprit {
+			t.Errorf("party %d: expected %d to be blamed, got %v", id, culprit, err)
+		}
+		if id != victim && !errors.Is(err, keygen.ErrEquivocation) {
+			t.Errorf("party %d: expected an equivocation error, got %v", id, err)
+		}
+	}
+}
+
+// runKeygenWithComplaints runs keygen with the complaint phase enabled.
+// tamper is called with the messages produced in each round for every party, and returns the messages delivered to it.
+func runKeygenWithComplaints(t *testing.T, partyIDs party.IDSlice, T party.Size, timeout time.Duration, tamper func(to party.ID, msgs [][]byte) [][]byte) (map[party.ID]*state.State, map[party.ID]*keygen.Output) {
+	states := map[party.ID]*state.State{}
+	outputs := map[party.ID]*keygen.Output{}
+	for _, id := range partyIDs {
+		var err error
+		states[id], outputs[id], err = frost.NewKeygenStateWithOptions(id, partyIDs, T, &keygen.Options{Complaints: true}, timeout)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	msgsIn := map[party.ID][][]byte{}
+	for round := 0; round < 6; round++ {
+		msgsOut := make([][]byte, 0, len(partyIDs)*len(partyIDs))
+		for id, s := range states {
+			msgsOut = append(msgsOut, deliver(t, s, msgsIn[id])...)
+		}
+		for _, id := range partyIDs {
+			msgsIn[id] = tamper(id, append([][]byte{}, msgsOut...))
+		}
+	}
+	return states, outputs
+}
+
+// deliver hands msgs to s and returns the messages it produces.
+// Like a transport, it ignores the messages which s refuses, such as those of a disqualified party.
+func deliver(t *testing.T, s *state.State, msgs [][]byte) [][]byte {
+	for _, data := range msgs {
+		var msg messages.Message
+		if err := msg.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+		_ = s.HandleMessage(&msg)
+	}
+	out := make([][]byte, 0, len(msgs))
+	for _, msg := range s.ProcessAll() {
+		data, err := msg.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, data)
+	}
+	return out
+}
+
+// tamperWith applies f to every message from the culprit of the given type.
+func tamperWith(t *testing.T, msgs [][]byte, culprit party.ID, msgType messages.MessageType, f func(msg *messages.Message)) [][]byte {
+	for i, data := range msgs {
+		var msg messages.Message
+		if err := msg.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+		if msg.From == culprit && msg.Type == msgType {
+			f(&msg)
+			var err error
+			if msgs[i], err = msg.MarshalBinary(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return msgs
+}
+
+func TestKeygenComplaints(t *testing.T) {
+	N := party.Size(5)
+	T := party.Size(2)
+	partyIDs := helpers.GenerateSet(N)
+
+	states, outputs := runKeygenWithComplaints(t, partyIDs, T, 0, func(_ party.ID, msgs [][]byte) [][]byte { return msgs })
+
+	secrets := map[party.ID]*eddsa.SecretShare{}
+	public := outputs[partyIDs[0]].Public
+	for _, id := range partyIDs {
+		if err := states[id].WaitForError(); err != nil {
+			t.Fatalf("party %d: %v", id, err)
+		}
+		if err := CompareOutput(public.GroupKey, outputs[id].Public.GroupKey, public, outputs[id].Public); err != nil {
+			t.Error(err)
+		}
+		if len(outputs[id].Disqualified) != 0 {
+			t.Errorf("party %d disqualified %v", id, outputs[id].Disqualified)
+		}
+		secrets[id] = outputs[id].SecretKey
+	}
+	if err := ValidateSecrets(secrets, public.GroupKey, public); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestKeygenComplaintJustified(t *testing.T) {
+	N := party.Size(5)
+	T := party.Size(2)
+	partyIDs := helpers.GenerateSet(N)
+	culprit, victim := partyIDs[1], partyIDs[3]
+
+	states, outputs := runKeygenWithComplaints(t, partyIDs, T, 0, func(_ party.ID, msgs [][]byte) [][]byte {
+		return tamperWith(t, msgs, culprit, messages.MessageTypeKeyGen2, func(msg *messages.Message) {
+			if msg.To == victim {
+				msg.KeyGen2.Ciphertext[0] ^= 1
+			}
+		})
+	})
+
+	// The culprit reveals the correct share, so the victim can use it instead
+	secrets := map[party.ID]*eddsa.SecretShare{}
+	public := outputs[partyIDs[0]].Public
+	for _, id := range partyIDs {
+		if err := states[id].WaitForError(); err != nil {
+			t.Fatalf("party %d: %v", id, err)
+		}
+		if err := CompareOutput(public.GroupKey, outputs[id].Public.GroupKey, public, outputs[id].Public); err != nil {
+			t.Error(err)
+		}
+		if len(outputs[id].Disqualified) != 0 {
+			t.Errorf("party %d disqualified %v", id, outputs[id].Disqualified)
+		}
+		secrets[id] = outputs[id].SecretKey
+	}
+	if err := ValidateSecrets(secrets, public.GroupKey, public); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestKeygenComplaintDisqualified(t *testing.T) {
+	N := party.Size(5)
+	T := party.Size(2)
+	partyIDs := helpers.GenerateSet(N)
+	culprit, victim := partyIDs[1], partyIDs[3]
+
+	states, outputs := runKeygenWithComplaints(t, partyIDs, T, 0, func(_ party.ID, msgs [][]byte) [][]byte {
+		msgs = tamperWith(t, msgs, culprit, messages.MessageTypeKeyGen2, func(msg *messages.Message) {
+			if msg.To == victim {
+				msg.KeyGen2.Ciphertext[0] ^= 1
+			}
+		})
+		return tamperWith(t, msgs, culprit, messages.MessageTypeKeyGen4, func(msg *messages.Message) {
+			msg.KeyGen4.Shares[victim] = party.ID(42).Scalar()
+		})
+	})
+
+	// The culprit fails to justify itself, and the honest parties continue without its contribution
+	honest := make(party.IDSlice, 0, N-1)
+	for _, id := range partyIDs {
+		if id != culprit {
+			honest = append(honest, id)
+		}
+	}
+	secrets := map[party.ID]*eddsa.SecretShare{}
+	public := outputs[honest[0]].Public
+	for _, id := range honest {
+		if err := states[id].WaitForError(); err != nil {
+			t.Fatalf("party %d: %v", id, err)
+		}
+		if err := CompareOutput(public.GroupKey, outputs[id].Public.GroupKey, public, outputs[id].Public); err != nil {
+			t.Error(err)
+		}
+		if !outputs[id].Disqualified.Equal(party.IDSlice{culprit}) {
+			t.Errorf("party %d: expected %d to be disqualified, got %v", id, culprit, outputs[id].Disqualified)
+		}
+		secrets[id] = outputs[id].SecretKey
+	}
+
+	// The shares of the honest parties are enough to recover the group key
+	honestPublic := *public
+	honestPublic.PartyIDs = honest
+	if err := ValidateSecrets(secrets, public.GroupKey, &honestPublic); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestKeygenComplaintDisqualifiedAcceptedShare(t *testing.T) {
+	N := party.Size(6)
+	T := party.Size(2)
+	partyIDs := helpers.GenerateSet(N)
+	culprit := partyIDs[2]
+	victims := party.IDSlice{partyIDs[0], partyIDs[4]}
+
+	// Only the victims receive an invalid share, so the other honest parties accept the culprit's share
+	// and must remove it from their secret once the culprit is disqualified.
+	states, outputs := runKeygenWithComplaints(t, partyIDs, T, 0, func(_ party.ID, msgs [][]byte) [][]byte {
+		msgs = tamperWith(t, msgs, culprit, messages.MessageTypeKeyGen2, func(msg *messages.Message) {
+			if victims.Contains(msg.To) {
+				msg.KeyGen2.Ciphertext[0] ^= 1
+			}
+		})
+		return tamperWith(t, msgs, culprit, messages.MessageTypeKeyGen4, func(msg *messages.Message) {
+			for _, victim := range victims {
+				msg.KeyGen4.Shares[victim] = party.ID(42).Scalar()
+			}
+		})
+	})
+
+	for _, id := range partyIDs {
+		if id == culprit {
+			continue
+		}
+		if err := states[id].WaitForError(); err != nil {
+			t.Fatalf("party %d: %v", id, err)
+		}
+		if !outputs[id].Disqualified.Equal(party.IDSlice{culprit}) {
+			t.Errorf("party %d: expected %d to be disqualified, got %v", id, culprit, outputs[id].Disqualified)
+		}
+		public := new(ristretto.Element).ScalarBaseMult(&outputs[id].SecretKey.Secret)
+		if public.Equal(outputs[id].Public.Shares[id]) != 1 {
+			t.Errorf("party %d: secret does not match the public share", id)
+		}
+	}
+}
+
+func TestKeygenComplaintTimeout(t *testing.T) {
+	N := party.Size(5)
+	T := party.Size(2)
+	partyIDs := helpers.GenerateSet(N)
+	culprit, victim := partyIDs[1], partyIDs[3]
+
+	// The culprit sends an invalid share to the victim, and never justifies itself
+	states, outputs := runKeygenWithComplaints(t, partyIDs, T, 500*time.Millisecond, func(_ party.ID, msgs [][]byte) [][]byte {
+		msgs = tamperWith(t, msgs, culprit, messages.MessageTypeKeyGen2, func(msg *messages.Message) {
+			if msg.To == victim {
+				msg.KeyGen2.Ciphertext[0] ^= 1
+			}
+		})
+		delivered := make([][]byte, 0, len(msgs))
+		for _, data := range msgs {
+			var msg messages.Message
+			if err := msg.UnmarshalBinary(data); err != nil {
+				t.Fatal(err)
+			}
+			if msg.From != culprit || msg.Type != messages.MessageTypeKeyGen4 {
+				delivered = append(delivered, data)
+			}
+		}
+		return delivered
+	})
+
+	// Once the timeout expires, the honest parties disqualify the culprit instead of aborting
+	honest := make(party.IDSlice, 0, N-1)
+	for _, id := range partyIDs {
+		if id != culprit {
+			honest = append(honest, id)
+		}
+	}
+	var msgsOut [][]byte
+	for _, id := range honest {
+		select {
+		case <-states[id].Proceed():
+		case <-states[id].Done():
+			t.Fatalf("party %d: %v", id, states[id].Err())
+		case <-time.After(5 * time.Second):
+			t.Fatalf("party %d: the timeout did not expire", id)
+		}
+		msgsOut = append(msgsOut, deliver(t, states[id], nil)...)
+	}
+	for _, id := range honest {
+		deliver(t, states[id], msgsOut)
+	}
+
+	secrets := map[party.ID]*eddsa.SecretShare{}
+	public := outputs[honest[0]].Public
+	for _, id := range honest {
+		if err := states[id].WaitForError(); err != nil {
+			t.Fatalf("party %d: %v", id, err)
+		}
+		if err := CompareOutput(public.GroupKey, outputs[id].Public.GroupKey, public, outputs[id].Public); err != nil {
+			t.Error(err)
+		}
+		if !outputs[id].Disqualified.Equal(party.IDSlice{culprit}) {
+			t.Errorf("party %d: expected %d to be disqualified, got %v", id, culprit, outputs[id].Disqualified)
+		}
+		secrets[id] = outputs[id].SecretKey
+	}
+	honestPublic := *public
+	honestPublic.PartyIDs = honest
+	if err := ValidateSecrets(secrets, public.GroupKey, &honestPublic); err != nil {
+		t.Error(err)
+	}
+}
+
+// expectComplaintEquivocation checks that every party except the culprit aborted, blaming the culprit for equivocating.
+func expectComplaintEquivocation(t *testing.T, states map[party.ID]*state.State, culprit party.ID) {
+	for id, s := range states {
+		if id == culprit {
+			continue
+		}
+		err := s.WaitForError()
+		var stateErr *state.Error
+		if !errors.As(err, &stateErr) {
+			t.Fatalf("party %d: expected a state.Error, got %v", id, err)
+		}
+		if stateErr.PartyID != culprit || !errors.Is(err, keygen.ErrComplaintEquivocation) {
+			t.Errorf("party %d: expected %d to be blamed for equivocation, got %v", id, culprit, err)
+		}
+	}
+}
+
+func TestKeygenComplaintEquivocation(t *testing.T) {
+	N := party.Size(5)
+	T := party.Size(2)
+	partyIDs := helpers.GenerateSet(N)
+	culprit, accused := partyIDs[0], partyIDs[1]
+
+	// The culprit only sends its complaint to some of the parties, which would then wait for a justification
+	states, _ := runKeygenWithComplaints(t, partyIDs, T, 0, func(to party.ID, msgs [][]byte) [][]byte {
+		if to != partyIDs[3] && to != partyIDs[4] {
+			return msgs
+		}
+		return tamperWith(t, msgs, culprit, messages.MessageTypeKeyGen3, func(msg *messages.Message) {
+			msg.KeyGen3.Accused = party.IDSlice{accused}
+		})
+	})
+	expectComplaintEquivocation(t, states, culprit)
+}
+
+func TestKeygenJustificationEquivocation(t *testing.T) {
+	N := party.Size(5)
+	T := party.Size(2)
+	partyIDs := helpers.GenerateSet(N)
+	culprit, victim := partyIDs[1], partyIDs[3]
+
+	// The culprit justifies itself towards some of the parties only,
+	// so that the others would disqualify it and compute a different key.
+	states, _ := runKeygenWithComplaints(t, partyIDs, T, 0, func(to party.ID, msgs [][]byte) [][]byte {
+		msgs = tamperWith(t, msgs, culprit, messages.MessageTypeKeyGen2, func(msg *messages.Message) {
+			if msg.To == victim {
+				msg.KeyGen2.Ciphertext[0] ^= 1
+			}
+		})
+		if to == partyIDs[0] || to == victim {
+			return msgs
+		}
+		return tamperWith(t, msgs, culprit, messages.MessageTypeKeyGen4, func(msg *messages.Message) {
+			msg.KeyGen4.Shares[victim] = party.ID(42).Scalar()
+		})
+	})
+	expectComplaintEquivocation(t, states, culprit)
+}
+
 func ValidateSecrets(secrets map[party.ID]*eddsa.SecretShare, groupKey *eddsa.PublicKey, shares *eddsa.Public) error {
 	fullSecret := ristretto.NewScalar()
 
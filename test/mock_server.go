@@ -377,10 +377,6 @@ func testMockServer() {
 	}
 }
 
-func main() {
-	testMockServer()
-}
-
 /**
 curl -X POST -H "Content-Type: application/json" -d '{"round":0, "message1":"789djkshkdj", "session_id":"shjakhdsakdsa"}' http://10.152.30.148:8000/slice
 
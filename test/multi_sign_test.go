@@ -0,0 +1,200 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/sign"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+func multiMessages(n int) [][]byte {
+	msgs := make([][]byte, n)
+	for j := range msgs {
+		msgs[j] = []byte(fmt.Sprintf("payout %d", j))
+	}
+	return msgs
+}
+
+func TestSignMultiple(t *testing.T) {
+	N := party.Size(5)
+	T := party.Size(2)
+
+	_, signSet, secretShares, publicShares := setupParties(T, N)
+	pk := publicShares.GroupKey
+	msgs := multiMessages(4)
+
+	for _, opts := range []*sign.Options{
+		nil,
+		{Mode: sign.ModeRFC9591, Ciphersuite: eddsa.CiphersuiteRistretto255},
+	} {
+		states := map[party.ID]*state.State{}
+		outputs := map[party.ID]*sign.MultiOutput{}
+		for _, id := range signSet {
+			var err error
+			if states[id], outputs[id], err = frost.NewMultiSignState(signSet, secretShares[id], publicShares, msgs, opts, 0); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// Each signer broadcasts a single message per round
+		msgsOut1 := make([][]byte, 0, len(states))
+		for _, s := range states {
+			out, err := helpers.PartyRoutine(nil, s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(out) != 1 {
+				t.Fatalf("expected a single message, got %d", len(out))
+			}
+			msgsOut1 = append(msgsOut1, out...)
+		}
+
+		// The session can be suspended between rounds
+		for id, s := range states {
+			data, err := s.Suspend()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if states[id], err = state.Resume(data); err != nil {
+				t.Fatal(err)
+			}
+			outputs[id] = states[id].GetRound().GetOutput().(*sign.MultiOutput)
+		}
+
+		msgsOut2 := make([][]byte, 0, len(states))
+		for _, s := range states {
+			out, err := helpers.PartyRoutine(msgsOut1, s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			msgsOut2 = append(msgsOut2, out...)
+		}
+		for _, s := range states {
+			if _, err := helpers.PartyRoutine(msgsOut2, s); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		var cs eddsa.Ciphersuite
+		if opts != nil {
+			cs = opts.Ciphersuite
+		}
+		for id, s := range states {
+			if err := s.WaitForError(); err != nil {
+				t.Fatal(err)
+			}
+			sigs := outputs[id].Signatures
+			if len(sigs) != len(msgs) {
+				t.Fatalf("party %d: got %d signatures instead of %d", id, len(sigs), len(msgs))
+			}
+			for j, sig := range sigs {
+				if !cs.Verify(pk, msgs[j], sig) {
+					t.Errorf("party %d: signature %d failed to verify", id, j)
+				}
+				if cs == eddsa.CiphersuiteEd25519 && !ed25519.Verify(pk.ToEd25519(), msgs[j], sig.ToEd25519()) {
+					t.Errorf("party %d: signature %d failed to verify with ed25519", id, j)
+				}
+			}
+		}
+	}
+
+	if _, _, err := frost.NewMultiSignState(signSet, secretShares[signSet[0]], publicShares, nil, nil, 0); err == nil {
+		t.Error("expected error without messages")
+	}
+}
+
+// corruptMultiSign2 replaces the share of the j-th message in all MultiSign2 messages sent by a culprit.
+func corruptMultiSign2(t *testing.T, msgs [][]byte, culprits party.IDSlice, j int) [][]byte {
+	out := make([][]byte, 0, len(msgs))
+	for _, data := range msgs {
+		var msg messages.Message
+		if err := msg.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+		if msg.Type == messages.MessageTypeMultiSign2 && culprits.Contains(msg.From) {
+			random := make([]byte, 64)
+			if _, err := rand.Read(random); err != nil {
+				t.Fatal(err)
+			}
+			_, _ = msg.MultiSign2.Zi[j].SetUniformBytes(random)
+			var err error
+			if data, err = msg.MarshalBinary(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		out = append(out, data)
+	}
+	return out
+}
+
+func TestSignMultipleIdentifiableAbort(t *testing.T) {
+	N := party.Size(6)
+	T := party.Size(2)
+
+	partyIDs, _, secretShares, publicShares := setupParties(T, N)
+	signSet := partyIDs[:T+3]
+	culprits := party.NewIDSlice([]party.ID{signSet[0], signSet[3]})
+	msgs := multiMessages(3)
+
+	states := map[party.ID]*state.State{}
+	for _, id := range signSet {
+		var err error
+		if states[id], _, err = frost.NewMultiSignState(signSet, secretShares[id], publicShares, msgs, nil, 0); err != nil {
+			t.Fatal(err)
+		}
+		states[id].SetIdentifiableAbort(true)
+	}
+
+	msgsOut1 := make([][]byte, 0, len(states))
+	for _, s := range states {
+		out, err := helpers.PartyRoutine(nil, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgsOut1 = append(msgsOut1, out...)
+	}
+	msgsOut2 := make([][]byte, 0, len(states))
+	for _, s := range states {
+		out, err := helpers.PartyRoutine(msgsOut1, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgsOut2 = append(msgsOut2, out...)
+	}
+	msgsOut2 = corruptMultiSign2(t, msgsOut2, culprits, 2)
+	for _, s := range states {
+		// errors are checked later with WaitForError
+		_, _ = helpers.PartyRoutine(msgsOut2, s)
+	}
+
+	for _, id := range signSet {
+		if culprits.Contains(id) {
+			continue
+		}
+		var blame *state.Blame
+		if err := states[id].WaitForError(); !errors.As(err, &blame) {
+			t.Fatalf("party %d: expected a blame report, got %v", id, err)
+		}
+		if !blame.PartyIDs().Equal(culprits) {
+			t.Fatalf("party %d: blamed %v instead of %v", id, blame.PartyIDs(), culprits)
+		}
+		for _, culprit := range blame.Culprits {
+			if !errors.Is(culprit, sign.ErrValidateSigShare) || !strings.Contains(culprit.Error(), "message 2") {
+				t.Errorf("party %d: unexpected reason %v", id, culprit)
+			}
+			if len(culprit.Message) == 0 {
+				t.Errorf("party %d: blame does not contain the offending message", id)
+			}
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/sign"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+func TestNonceStoreReplay(t *testing.T) {
+	N := party.Size(5)
+	T := party.Size(2)
+	_, signIDs, secretShares, publicShares := setupParties(T, N)
+
+	dir, err := ioutil.TempDir("", "nonces")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	stores := map[party.ID]*sign.FileNonceStore{}
+	states := map[party.ID]*state.State{}
+	outputs := map[party.ID]*sign.Output{}
+	for _, id := range signIDs {
+		if stores[id], err = sign.OpenFileNonceStore(filepath.Join(dir, id.String())); err != nil {
+			t.Fatal(err)
+		}
+		opts := &sign.Options{NonceStore: stores[id]}
+		if states[id], outputs[id], err = frost.NewSignStateWithOptions(signIDs, secretShares[id], publicShares, MESSAGE, opts, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	msgsOut1 := make([][]byte, 0, N)
+	for _, s := range states {
+		msgs1, err := helpers.PartyRoutine(nil, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgsOut1 = append(msgsOut1, msgs1...)
+	}
+
+	// The victim's State is saved after its nonces were committed to
+	victim := signIDs[0]
+	snapshot, err := states[victim].Suspend()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msgsOut2 := make([][]byte, 0, N)
+	for _, s := range states {
+		msgs2, err := helpers.PartyRoutine(msgsOut1, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgsOut2 = append(msgsOut2, msgs2...)
+	}
+	for _, s := range states {
+		if _, err = helpers.PartyRoutine(msgsOut2, s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, id := range signIDs {
+		if !publicShares.GroupKey.Verify(MESSAGE, outputs[id].Signature) {
+			t.Errorf("party %d: signature failed to verify", id)
+		}
+	}
+	for _, store := range stores {
+		if err = store.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Restoring the snapshot, even after a restart, must not produce a second share with the same nonces
+	store, err := sign.OpenFileNonceStore(filepath.Join(dir, victim.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	resumed, _, err := frost.ResumeSignState(snapshot, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = helpers.PartyRoutine(msgsOut1, resumed); !errors.Is(err, sign.ErrNonceReused) {
+		t.Errorf("expected ErrNonceReused, got %v", err)
+	}
+
+	// Resuming without a store is refused
+	resumed, err = state.Resume(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = helpers.PartyRoutine(msgsOut1, resumed); !errors.Is(err, sign.ErrNonceStoreMissing) {
+		t.Errorf("expected ErrNonceStoreMissing, got %v", err)
+	}
+}
+
+func TestFileNonceStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nonces")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "nonces")
+
+	store, err := sign.OpenFileNonceStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = store.Consume([]byte{1, 2, 3}); err != nil {
+		t.Fatal(err)
+	}
+	if err = store.Consume([]byte{1, 2, 3}); !errors.Is(err, sign.ErrNonceReused) {
+		t.Errorf("expected ErrNonceReused, got %v", err)
+	}
+	if err = store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a write interrupted by a crash
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("0405")
+	_ = f.Close()
+
+	store, err = sign.OpenFileNonceStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if err = store.Consume([]byte{1, 2, 3}); !errors.Is(err, sign.ErrNonceReused) {
+		t.Errorf("expected ErrNonceReused after reopening, got %v", err)
+	}
+	if err = store.Consume([]byte{4, 5, 6}); err != nil {
+		t.Errorf("the incomplete line was not discarded: %v", err)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "010203\n040506\n" {
+		t.Errorf("unexpected file content %q", data)
+	}
+}
+
+func TestFileNonceStoreShared(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nonces")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "nonces")
+
+	// Each store stands for another process signing with the same file
+	stores := make([]*sign.FileNonceStore, 8)
+	for i := range stores {
+		if stores[i], err = sign.OpenFileNonceStore(path); err != nil {
+			t.Fatal(err)
+		}
+		defer stores[i].Close()
+	}
+
+	// A commitment consumed by one store is seen by a store opened before
+	if err = stores[0].Consume([]byte{1, 2, 3}); err != nil {
+		t.Fatal(err)
+	}
+	if err = stores[1].Consume([]byte{1, 2, 3}); !errors.Is(err, sign.ErrNonceReused) {
+		t.Errorf("expected ErrNonceReused from another store, got %v", err)
+	}
+
+	// Only one of the concurrent stores can consume the commitment
+	errs := make(chan error, len(stores))
+	for _, store := range stores {
+		go func(store *sign.FileNonceStore) {
+			errs <- store.Consume([]byte{4, 5, 6})
+		}(store)
+	}
+	consumed := 0
+	for range stores {
+		err := <-errs
+		switch {
+		case err == nil:
+			consumed++
+		case !errors.Is(err, sign.ErrNonceReused):
+			t.Error(err)
+		}
+	}
+	if consumed != 1 {
+		t.Errorf("commitment was consumed %d times", consumed)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "010203\n040506\n" {
+		t.Errorf("unexpected file content %q", data)
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/sha512"
+	"testing"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/sign"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+func TestSignVariants(t *testing.T) {
+	N := party.Size(5)
+	T := party.Size(2)
+
+	_, signSet, secretShares, publicShares := setupParties(T, N)
+	pk := publicShares.GroupKey
+
+	digest := sha512.Sum512(MESSAGE)
+	context := "FROST test"
+
+	tests := []struct {
+		name    string
+		opts    *eddsa.Options
+		message []byte
+	}{
+		{"ctx", &eddsa.Options{Variant: eddsa.VariantCtx, Context: []byte(context)}, MESSAGE},
+		{"ph", &eddsa.Options{Variant: eddsa.VariantPh}, digest[:]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			states := map[party.ID]*state.State{}
+			outputs := map[party.ID]*sign.Output{}
+			for _, id := range signSet {
+				var err error
+				states[id], outputs[id], err = frost.NewSignStateWithOptions(signSet, secretShares[id], publicShares, tt.message, &sign.Options{Signature: tt.opts}, 0)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			runSign(t, states, nil)
+
+			for id, s := range states {
+				if err := s.WaitForError(); err != nil {
+					t.Fatal(err)
+				}
+				sig := outputs[id].Signature
+				if !pk.VerifyWithOptions(tt.message, sig, tt.opts) {
+					t.Error("signature failed to verify")
+				}
+				if ed25519.Verify(pk.ToEd25519(), tt.message, sig.ToEd25519()) {
+					t.Error("signature should not be valid for PureEdDSA")
+				}
+			}
+		})
+	}
+
+	// Ed25519ph requires a digest
+	_, _, err := frost.NewSignStateWithOptions(signSet, secretShares[signSet[0]], publicShares, MESSAGE, &sign.Options{Signature: &eddsa.Options{Variant: eddsa.VariantPh}}, 0)
+	if err == nil {
+		t.Error("expected error for Ed25519ph with a message which is not a digest")
+	}
+}
+
+func TestSignRFC9591(t *testing.T) {
+	N := party.Size(5)
+	T := party.Size(2)
+
+	_, signSet, secretShares, publicShares := setupParties(T, N)
+	pk := publicShares.GroupKey
+
+	for _, cs := range []eddsa.Ciphersuite{eddsa.CiphersuiteEd25519, eddsa.CiphersuiteRistretto255} {
+		t.Run(cs.ContextString(), func(t *testing.T) {
+			opts := &sign.Options{Mode: sign.ModeRFC9591, Ciphersuite: cs}
+			states := map[party.ID]*state.State{}
+			outputs := map[party.ID]*sign.Output{}
+			for _, id := range signSet {
+				var err error
+				states[id], outputs[id], err = frost.NewSignStateWithOptions(signSet, secretShares[id], publicShares, MESSAGE, opts, 0)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			runSign(t, states, nil)
+			for id, s := range states {
+				if err := s.WaitForError(); err != nil {
+					t.Fatal(err)
+				}
+				sig := outputs[id].Signature
+				if !cs.Verify(pk, MESSAGE, sig) {
+					t.Error("signature failed to verify")
+				}
+				validEd25519 := ed25519.Verify(pk.ToEd25519(), MESSAGE, sig.ToEd25519())
+				if validEd25519 != (cs == eddsa.CiphersuiteEd25519) {
+					t.Errorf("ed25519.Verify returned %v", validEd25519)
+				}
+			}
+
+			// The mode and ciphersuite are restored with the State
+			s, _, err := frost.NewSignStateWithOptions(signSet, secretShares[signSet[0]], publicShares, MESSAGE, opts, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := s.Suspend()
+			if err != nil {
+				t.Fatal(err)
+			}
+			resumed, err := state.Resume(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if restored := resumed.GetRound().(*sign.Round0).Options; restored.Mode != sign.ModeRFC9591 || restored.Ciphersuite != cs {
+				t.Error("options were not restored")
+			}
+		})
+	}
+
+	// The ciphersuites only define PureEdDSA
+	opts := &sign.Options{
+		Mode:      sign.ModeRFC9591,
+		Signature: &eddsa.Options{Variant: eddsa.VariantCtx, Context: []byte("FROST test")},
+	}
+	if _, _, err := frost.NewSignStateWithOptions(signSet, secretShares[signSet[0]], publicShares, MESSAGE, opts, 0); err == nil {
+		t.Error("expected error for Ed25519ctx in RFC 9591 mode")
+	}
+
+	// ristretto255 is not supported by the legacy mode
+	opts = &sign.Options{Ciphersuite: eddsa.CiphersuiteRistretto255}
+	if _, _, err := frost.NewSignStateWithOptions(signSet, secretShares[signSet[0]], publicShares, MESSAGE, opts, 0); err == nil {
+		t.Error("expected error for ristretto255 in legacy mode")
+	}
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"testing"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/sign"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+// preprocess lets every signer publish a batch of commitments, which are stored by all parties in holders.
+func preprocess(t *testing.T, signSet, holders party.IDSlice, batchSize int) (map[party.ID]*sign.Nonces, map[party.ID]*sign.Commitments) {
+	nonces := map[party.ID]*sign.Nonces{}
+	commitments := map[party.ID]*sign.Commitments{}
+	published := make([]*messages.Message, 0, len(signSet))
+	for _, id := range signSet {
+		nonces[id] = sign.NewNonces(id)
+		msg, err := nonces[id].Generate(batchSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+		published = append(published, msg)
+	}
+	for _, id := range holders {
+		commitments[id] = sign.NewCommitments()
+		for _, msg := range published {
+			if err := commitments[id].Add(msg); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return nonces, commitments
+}
+
+func TestSignPreprocessed(t *testing.T) {
+	N := party.Size(10)
+	T := N / 2
+	batchSize := 3
+
+	_, signSet, secretShares, publicShares := setupParties(T, N)
+
+	// Preprocessing: every signer publishes a batch of commitments
+	nonces, commitments := preprocess(t, signSet, signSet, batchSize)
+
+	pk := publicShares.GroupKey
+	for session := 0; session < batchSize; session++ {
+		states := map[party.ID]*state.State{}
+		outputs := map[party.ID]*sign.Output{}
+		for _, id := range signSet {
+			indices, err := commitments[id].NextIndices(signSet)
+			if err != nil {
+				t.Fatal(err)
+			}
+			states[id], outputs[id], err = frost.NewPreprocessedSignState(signSet, secretShares[id], publicShares, MESSAGE, nil, nonces[id], commitments[id], indices, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		msgsOut := make([][]byte, 0, len(signSet))
+		for _, s := range states {
+			msgs, err := helpers.PartyRoutine(nil, s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			msgsOut = append(msgsOut, msgs...)
+		}
+		for _, s := range states {
+			if _, err := helpers.PartyRoutine(msgsOut, s); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		for id, s := range states {
+			if err := s.WaitForError(); err != nil {
+				t.Fatal(err)
+			}
+			sig := outputs[id].Signature
+			if !ed25519.Verify(pk.ToEd25519(), MESSAGE, sig.ToEd25519()) {
+				t.Error("sig ed25519 failed")
+			}
+		}
+	}
+
+	// All commitments have been consumed
+	for _, id := range signSet {
+		if nonces[id].Remaining() != 0 {
+			t.Error("nonces were not consumed")
+		}
+		if _, err := commitments[id].NextIndices(signSet); err == nil {
+			t.Error("commitments were not consumed")
+		}
+	}
+
+	// A consumed commitment can not be used again
+	id := signSet[0]
+	indices := make(map[party.ID]uint32, len(signSet))
+	for _, otherID := range signSet {
+		indices[otherID] = 0
+	}
+	if _, _, err := frost.NewPreprocessedSignState(signSet, secretShares[id], publicShares, MESSAGE, nil, nonces[id], commitments[id], indices, 0); err == nil {
+		t.Error("reusing a commitment should fail")
+	}
+}
+
+// recordingNonceStore is a sign.NonceStore which records all consumed commitments.
+type recordingNonceStore struct {
+	consumed [][]byte
+}
+
+func (s *recordingNonceStore) Consume(commitment []byte) error {
+	s.consumed = append(s.consumed, commitment)
+	return nil
+}
+
+func TestSignPreprocessedOptions(t *testing.T) {
+	N := party.Size(5)
+	T := party.Size(2)
+
+	_, signSet, secretShares, publicShares := setupParties(T, N)
+	pk := publicShares.GroupKey
+
+	for _, opts := range []sign.Options{
+		{Signature: &eddsa.Options{Variant: eddsa.VariantCtx, Context: []byte("preprocessed")}},
+		{Mode: sign.ModeRFC9591, Ciphersuite: eddsa.CiphersuiteRistretto255},
+	} {
+		nonces, commitments := preprocess(t, signSet, signSet, 1)
+		states := map[party.ID]*state.State{}
+		outputs := map[party.ID]*sign.Output{}
+		stores := map[party.ID]*recordingNonceStore{}
+		for _, id := range signSet {
+			indices, err := commitments[id].NextIndices(signSet)
+			if err != nil {
+				t.Fatal(err)
+			}
+			opts := opts
+			stores[id] = &recordingNonceStore{}
+			opts.NonceStore = stores[id]
+			if states[id], outputs[id], err = frost.NewPreprocessedSignState(signSet, secretShares[id], publicShares, MESSAGE, &opts, nonces[id], commitments[id], indices, 0); err != nil {
+				t.Fatal(err)
+			}
+		}
+		runSign(t, states, nil)
+
+		for id, s := range states {
+			if err := s.WaitForError(); err != nil {
+				t.Fatal(err)
+			}
+			sig := outputs[id].Signature
+			if opts.Mode == sign.ModeRFC9591 {
+				if !opts.Ciphersuite.Verify(pk, MESSAGE, sig) {
+					t.Errorf("party %d: signature failed to verify", id)
+				}
+			} else if !pk.VerifyWithOptions(MESSAGE, sig, opts.Signature) {
+				t.Errorf("party %d: signature failed to verify with the context", id)
+			}
+			if len(stores[id].consumed) != 1 {
+				t.Errorf("party %d: %d nonces recorded in the NonceStore instead of 1", id, len(stores[id].consumed))
+			}
+		}
+	}
+}
+
+func TestSignPreprocessedCoordinator(t *testing.T) {
+	N := party.Size(6)
+	T := party.Size(2)
+
+	partyIDs, _, secretShares, publicShares := setupParties(T, N)
+	signSet := partyIDs[:T+2]
+	coordinatorID := partyIDs[N-1] + 1
+	opts := &sign.Options{Coordinator: coordinatorID}
+
+	holders := append(signSet.Copy(), coordinatorID)
+	nonces, commitments := preprocess(t, signSet, holders, 1)
+	indices, err := commitments[coordinatorID].NextIndices(signSet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	coordinator, output, err := frost.NewPreprocessedCoordinatorState(coordinatorID, signSet, publicShares, MESSAGE, opts, commitments[coordinatorID], indices, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signers := map[party.ID]*state.State{}
+	signerOutputs := map[party.ID]*sign.Output{}
+	for _, id := range signSet {
+		if signers[id], signerOutputs[id], err = frost.NewPreprocessedSignState(signSet, secretShares[id], publicShares, MESSAGE, opts, nonces[id], commitments[id], indices, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The coordinator does not send anything, and the signers only send their share to the coordinator
+	msgs, err := helpers.PartyRoutine(nil, coordinator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 0 {
+		t.Errorf("coordinator sent %d messages", len(msgs))
+	}
+	var msgsSign2 [][]byte
+	for _, s := range signers {
+		msgs, err := helpers.PartyRoutine(nil, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgsSign2 = append(msgsSign2, msgs...)
+	}
+	for _, data := range msgsSign2 {
+		var msg messages.Message
+		if err = msg.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+		if msg.To != coordinatorID {
+			t.Errorf("party %d sent its share to %d instead of the coordinator", msg.From, msg.To)
+		}
+	}
+	if _, err = helpers.PartyRoutine(msgsSign2, coordinator); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = coordinator.WaitForError(); err != nil {
+		t.Fatal(err)
+	}
+	if !ed25519.Verify(publicShares.GroupKey.ToEd25519(), MESSAGE, output.Signature.ToEd25519()) {
+		t.Error("sig ed25519 failed")
+	}
+	for id, s := range signers {
+		if err = s.WaitForError(); err != nil {
+			t.Fatal(err)
+		}
+		if signerOutputs[id].Signature != nil {
+			t.Errorf("party %d: signers should not obtain the signature", id)
+		}
+	}
+}
+
+func TestSignPreprocessedUnknownNonce(t *testing.T) {
+	N := party.Size(5)
+	T := party.Size(2)
+
+	_, signSet, secretShares, publicShares := setupParties(T, N)
+	_, commitments := preprocess(t, signSet, signSet, 1)
+
+	// Our own nonces are unknown, for instance because they were lost after preprocessing
+	id := signSet[0]
+	indices, err := commitments[id].NextIndices(signSet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = frost.NewPreprocessedSignState(signSet, secretShares[id], publicShares, MESSAGE, nil, sign.NewNonces(id), commitments[id], indices, 0); !errors.Is(err, sign.ErrNonceUnknown) {
+		t.Fatalf("expected ErrNonceUnknown, got %v", err)
+	}
+
+	// The commitments of the other signers were not consumed
+	for _, otherID := range signSet {
+		if remaining := commitments[id].Remaining(otherID); remaining != 1 {
+			t.Errorf("party %d: %d commitments remaining instead of 1", otherID, remaining)
+		}
+	}
+}
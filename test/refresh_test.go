@@ -0,0 +1,85 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/refresh"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/sign"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+func TestRefresh(t *testing.T) {
+	N := party.Size(7)
+	T := party.Size(3)
+
+	partyIDs, signSet, secretShares, publicShares := setupParties(T, N)
+
+	states := map[party.ID]*state.State{}
+	outputs := map[party.ID]*refresh.Output{}
+	for _, id := range partyIDs {
+		var err error
+		states[id], outputs[id], err = frost.NewRefreshState(secretShares[id], publicShares, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var msgsIn [][]byte
+	for round := 0; round < 3; round++ {
+		msgsOut := make([][]byte, 0, N*N)
+		for _, s := range states {
+			msgs, err := helpers.PartyRoutine(msgsIn, s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			msgsOut = append(msgsOut, msgs...)
+		}
+		msgsIn = msgsOut
+	}
+
+	newSecrets := map[party.ID]*eddsa.SecretShare{}
+	var newPublic *eddsa.Public
+	for id, s := range states {
+		if err := s.WaitForError(); err != nil {
+			t.Fatal(err)
+		}
+		out := outputs[id]
+		if !out.Public.GroupKey.Equal(publicShares.GroupKey) {
+			t.Fatal("group key changed")
+		}
+		if out.SecretKey.Secret.Equal(&secretShares[id].Secret) == 1 {
+			t.Error("secret share was not refreshed")
+		}
+		if newPublic == nil {
+			newPublic = out.Public
+		} else if !newPublic.Equal(out.Public) {
+			t.Fatal("parties disagree on the new public shares")
+		}
+		newSecrets[id] = out.SecretKey
+	}
+
+	// The refreshed shares can be used to sign under the same group key
+	signStates := map[party.ID]*state.State{}
+	signOutputs := map[party.ID]*sign.Output{}
+	for _, id := range signSet {
+		var err error
+		signStates[id], signOutputs[id], err = frost.NewSignState(signSet, newSecrets[id], newPublic, MESSAGE, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	runSign(t, signStates, nil)
+	for id, s := range signStates {
+		if err := s.WaitForError(); err != nil {
+			t.Fatal(err)
+		}
+		if !ed25519.Verify(publicShares.GroupKey.ToEd25519(), MESSAGE, signOutputs[id].Signature.ToEd25519()) {
+			t.Error("sig ed25519 failed")
+		}
+	}
+}
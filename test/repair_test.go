@@ -0,0 +1,69 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/repair"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+func TestRepair(t *testing.T) {
+	N := party.Size(5)
+	T := party.Size(2)
+
+	partyIDs, _, secretShares, publicShares := setupParties(T, N)
+	lost := partyIDs[1]
+	helperIDs := party.NewIDSlice([]party.ID{partyIDs[0], partyIDs[3], partyIDs[4]})
+
+	states := map[party.ID]*state.State{}
+	outputs := map[party.ID]*repair.Output{}
+	for _, id := range append(helperIDs.Copy(), lost) {
+		var secret *eddsa.SecretShare
+		if id != lost {
+			secret = secretShares[id]
+		}
+		var err error
+		states[id], outputs[id], err = frost.NewRepairState(id, secret, publicShares, helperIDs, lost, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var msgsIn [][]byte
+	for round := 0; round < 3; round++ {
+		msgsOut := make([][]byte, 0, len(states)*len(states))
+		for _, s := range states {
+			msgs, err := helpers.PartyRoutine(msgsIn, s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			msgsOut = append(msgsOut, msgs...)
+		}
+		msgsIn = msgsOut
+	}
+
+	for id, s := range states {
+		if err := s.WaitForError(); err != nil {
+			t.Fatal(err)
+		}
+		if id != lost && outputs[id].SecretKey != nil {
+			t.Errorf("helper %d should not learn the repaired share", id)
+		}
+	}
+	repaired := outputs[lost].SecretKey
+	if repaired == nil {
+		t.Fatal("lost share was not repaired")
+	}
+	if !repaired.Equal(secretShares[lost]) {
+		t.Error("repaired share differs from the original")
+	}
+
+	// The lost party can not help repair itself
+	if _, _, err := frost.NewRepairState(lost, secretShares[lost], publicShares, append(helperIDs.Copy(), lost), lost, 0); err == nil {
+		t.Error("lost party should not be accepted as a helper")
+	}
+}
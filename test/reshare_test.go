@@ -0,0 +1,105 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/reshare"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/sign"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+func TestReshare(t *testing.T) {
+	// Move from a 2-of-3 to a 3-of-5, where one old party leaves
+	N := party.Size(3)
+	T := party.Size(1)
+	newT := party.Size(2)
+
+	partyIDs, _, secretShares, publicShares := setupParties(T, N)
+	dealers := party.NewIDSlice([]party.ID{partyIDs[0], partyIDs[2]})
+	newPartyIDs := party.NewIDSlice([]party.ID{partyIDs[1], partyIDs[2], 10, 11, 12})
+
+	allIDs := append(partyIDs.Copy(), 10, 11, 12)
+	states := map[party.ID]*state.State{}
+	outputs := map[party.ID]*reshare.Output{}
+	for _, id := range allIDs {
+		if !dealers.Contains(id) && !newPartyIDs.Contains(id) {
+			continue
+		}
+		var secret *eddsa.SecretShare
+		if dealers.Contains(id) {
+			secret = secretShares[id]
+		}
+		var err error
+		states[id], outputs[id], err = frost.NewReshareState(id, secret, publicShares, dealers, newPartyIDs, newT, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var msgsIn [][]byte
+	for round := 0; round < 3; round++ {
+		msgsOut := make([][]byte, 0, len(states)*len(states))
+		for _, s := range states {
+			msgs, err := helpers.PartyRoutine(msgsIn, s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			msgsOut = append(msgsOut, msgs...)
+		}
+		msgsIn = msgsOut
+	}
+
+	newSecrets := map[party.ID]*eddsa.SecretShare{}
+	var newPublic *eddsa.Public
+	for id, s := range states {
+		if err := s.WaitForError(); err != nil {
+			t.Fatal(err)
+		}
+		out := outputs[id]
+		if !out.Public.GroupKey.Equal(publicShares.GroupKey) {
+			t.Fatal("group key changed")
+		}
+		if out.Public.Threshold != newT || !out.Public.PartyIDs.Equal(newPartyIDs) {
+			t.Fatal("wrong parameters for the new shares")
+		}
+		if newPublic == nil {
+			newPublic = out.Public
+		} else if !newPublic.Equal(out.Public) {
+			t.Fatal("parties disagree on the new public shares")
+		}
+		if newPartyIDs.Contains(id) {
+			if out.SecretKey == nil {
+				t.Fatalf("party %d did not receive a share", id)
+			}
+			newSecrets[id] = out.SecretKey
+		} else if out.SecretKey != nil {
+			t.Errorf("party %d should not receive a share", id)
+		}
+	}
+
+	// Any newT+1 of the new parties can sign under the same group key
+	signSet := party.NewIDSlice([]party.ID{partyIDs[1], 11, 12})
+	signStates := map[party.ID]*state.State{}
+	signOutputs := map[party.ID]*sign.Output{}
+	for _, id := range signSet {
+		var err error
+		signStates[id], signOutputs[id], err = frost.NewSignState(signSet, newSecrets[id], newPublic, MESSAGE, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	runSign(t, signStates, nil)
+	for id, s := range signStates {
+		if err := s.WaitForError(); err != nil {
+			t.Fatal(err)
+		}
+		if !ed25519.Verify(publicShares.GroupKey.ToEd25519(), MESSAGE, signOutputs[id].Signature.ToEd25519()) {
+			t.Error("sig ed25519 failed")
+		}
+	}
+}
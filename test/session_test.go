@@ -0,0 +1,182 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/frost"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/keygen"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/sign"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/messages"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+func TestSignSessionID(t *testing.T) {
+	N := party.Size(5)
+	T := party.Size(2)
+	_, signIDs, secretShares, publicShares := setupParties(T, N)
+
+	newStates := func(sessionID messages.SessionID) (map[party.ID]*state.State, map[party.ID]*sign.Output) {
+		states := map[party.ID]*state.State{}
+		outputs := map[party.ID]*sign.Output{}
+		for _, id := range signIDs {
+			var err error
+			states[id], outputs[id], err = frost.NewSignState(signIDs, secretShares[id], publicShares, MESSAGE, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			states[id].SetSessionID(sessionID)
+		}
+		return states, outputs
+	}
+
+	states, outputs := newStates(messages.SessionID{1})
+	msgsOut1 := make([][]byte, 0, N)
+	for _, s := range states {
+		msgs1, err := helpers.PartyRoutine(nil, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgsOut1 = append(msgsOut1, msgs1...)
+	}
+	msgsOut2 := make([][]byte, 0, N)
+	for _, s := range states {
+		msgs2, err := helpers.PartyRoutine(msgsOut1, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgsOut2 = append(msgsOut2, msgs2...)
+	}
+	for _, s := range states {
+		if _, err := helpers.PartyRoutine(msgsOut2, s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, id := range signIDs {
+		if err := states[id].WaitForError(); err != nil {
+			t.Fatal(err)
+		}
+		if !publicShares.GroupKey.Verify(MESSAGE, outputs[id].Signature) {
+			t.Errorf("party %d: signature failed to verify", id)
+		}
+	}
+
+	// Messages from the first session are rejected in another one
+	states, _ = newStates(messages.SessionID{2})
+	for _, id := range signIDs[1:] {
+		if _, err := helpers.PartyRoutine(msgsOut1, states[id]); err == nil {
+			t.Errorf("party %d accepted a message from another session", id)
+		}
+	}
+}
+
+func TestKeygenSessionID(t *testing.T) {
+	N := party.Size(5)
+	T := party.Size(2)
+	partyIDs := helpers.GenerateSet(N)
+
+	states := map[party.ID]*state.State{}
+	outputs := map[party.ID]*keygen.Output{}
+	for _, id := range partyIDs {
+		var err error
+		states[id], outputs[id], err = frost.NewKeygenState(id, partyIDs, T, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		states[id].SetSessionID(messages.SessionID{1})
+	}
+
+	var msgsIn [][]byte
+	for round := 0; round < 3; round++ {
+		msgsOut := make([][]byte, 0, N*N)
+		for _, s := range states {
+			msgs, err := helpers.PartyRoutine(msgsIn, s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			msgsOut = append(msgsOut, msgs...)
+		}
+		msgsIn = msgsOut
+	}
+
+	// The echoed digests of our own KeyGen1 message must include the session ID
+	public := outputs[partyIDs[0]].Public
+	for _, id := range partyIDs {
+		if err := states[id].WaitForError(); err != nil {
+			t.Fatalf("party %d: %v", id, err)
+		}
+		if !public.GroupKey.Equal(outputs[id].Public.GroupKey) {
+			t.Errorf("party %d computed a different group key", id)
+		}
+	}
+}
+
+func TestKeygenSessionReplay(t *testing.T) {
+	N := party.Size(3)
+	T := party.Size(1)
+	partyIDs := helpers.GenerateSet(N)
+	culprit := partyIDs[0]
+
+	newStates := func(sessionID messages.SessionID) map[party.ID]*state.State {
+		states := map[party.ID]*state.State{}
+		for _, id := range partyIDs {
+			var err error
+			states[id], _, err = frost.NewKeygenState(id, partyIDs, T, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			states[id].SetSessionID(sessionID)
+		}
+		return states
+	}
+	round1 := func(states map[party.ID]*state.State) [][]byte {
+		msgsOut1 := make([][]byte, 0, N)
+		for _, s := range states {
+			msgs1, err := helpers.PartyRoutine(nil, s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			msgsOut1 = append(msgsOut1, msgs1...)
+		}
+		return msgsOut1
+	}
+
+	old := round1(newStates(messages.SessionID{1}))
+	states := newStates(messages.SessionID{2})
+	msgsOut1 := round1(states)
+
+	// The culprit replays its KeyGen1 message from the first session, relabeled with the current session ID
+	for i, data := range msgsOut1 {
+		var msg messages.Message
+		if err := msg.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+		if msg.From != culprit {
+			continue
+		}
+		for _, oldData := range old {
+			var oldMsg messages.Message
+			if err := oldMsg.UnmarshalBinary(oldData); err != nil {
+				t.Fatal(err)
+			}
+			if oldMsg.From == culprit {
+				oldMsg.SessionID = msg.SessionID
+				var err error
+				if msgsOut1[i], err = oldMsg.MarshalBinary(); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+	}
+
+	for _, id := range partyIDs[1:] {
+		_, _ = helpers.PartyRoutine(msgsOut1, states[id])
+		err := states[id].WaitForError()
+		var stateErr *state.Error
+		if !errors.As(err, &stateErr) || stateErr.PartyID != culprit {
+			t.Errorf("party %d: expected %d to be blamed, got %v", id, culprit, err)
+		}
+	}
+}
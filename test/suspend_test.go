@@ -0,0 +1,138 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/taurusgroup/frost-ed25519/pkg/eddsa"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/keygen"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/party"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/refresh"
+	"github.com/taurusgroup/frost-ed25519/pkg/frost/sign"
+	"github.com/taurusgroup/frost-ed25519/pkg/helpers"
+	"github.com/taurusgroup/frost-ed25519/pkg/state"
+)
+
+// runSuspended runs the protocol, suspending and resuming every State before each round.
+// Since the outputs returned when creating the States are not shared with the resumed ones,
+// it returns the outputs of the last resumed rounds.
+func runSuspended(t *testing.T, states map[party.ID]*state.State) map[party.ID]interface{} {
+	outputs := map[party.ID]interface{}{}
+	var msgsIn [][]byte
+	for round := 0; round < 5; round++ {
+		msgsOut := make([][]byte, 0, len(states)*len(states))
+		for id, s := range states {
+			if s.IsFinished() {
+				continue
+			}
+			data, err := s.Suspend()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if states[id], err = state.Resume(data); err != nil {
+				t.Fatal(err)
+			}
+			outputs[id] = states[id].GetRound().GetOutput()
+			msgs, err := helpers.PartyRoutine(msgsIn, states[id])
+			if err != nil {
+				t.Fatal(err)
+			}
+			msgsOut = append(msgsOut, msgs...)
+		}
+		msgsIn = msgsOut
+	}
+	for id, s := range states {
+		if err := s.WaitForError(); err != nil {
+			t.Fatalf("party %d: %v", id, err)
+		}
+	}
+	return outputs
+}
+
+func TestSuspendKeygenSign(t *testing.T) {
+	N := party.Size(5)
+	T := party.Size(2)
+	partyIDs := helpers.GenerateSet(N)
+
+	states := map[party.ID]*state.State{}
+	for _, id := range partyIDs {
+		var err error
+		states[id], _, err = frost.NewKeygenState(id, partyIDs, T, time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	outputs := runSuspended(t, states)
+
+	secrets := map[party.ID]*eddsa.SecretShare{}
+	public := outputs[partyIDs[0]].(*keygen.Output).Public
+	for _, id := range partyIDs {
+		output := outputs[id].(*keygen.Output)
+		if err := CompareOutput(public.GroupKey, output.Public.GroupKey, public, output.Public); err != nil {
+			t.Fatal(err)
+		}
+		secrets[id] = output.SecretKey
+	}
+	if err := ValidateSecrets(secrets, public.GroupKey, public); err != nil {
+		t.Fatal(err)
+	}
+
+	signIDs := partyIDs[:T+1]
+	states = map[party.ID]*state.State{}
+	for _, id := range signIDs {
+		var err error
+		states[id], _, err = frost.NewSignState(signIDs, secrets[id], public, MESSAGE, time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	outputs = runSuspended(t, states)
+	for _, id := range signIDs {
+		sig := outputs[id].(*sign.Output).Signature
+		if sig == nil || !public.GroupKey.Verify(MESSAGE, sig) {
+			t.Errorf("party %d: signature failed to verify", id)
+		}
+	}
+}
+
+func TestSuspendRefresh(t *testing.T) {
+	N := party.Size(4)
+	T := party.Size(2)
+	partyIDs, _, secretShares, publicShares := setupParties(T, N)
+
+	states := map[party.ID]*state.State{}
+	for _, id := range partyIDs {
+		var err error
+		states[id], _, err = frost.NewRefreshState(secretShares[id], publicShares, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	outputs := runSuspended(t, states)
+
+	var newPublic *eddsa.Public
+	for _, id := range partyIDs {
+		output := outputs[id].(*refresh.Output)
+		if !output.Public.GroupKey.Equal(publicShares.GroupKey) {
+			t.Fatal("group key changed")
+		}
+		if newPublic == nil {
+			newPublic = output.Public
+		} else if !newPublic.Equal(output.Public) {
+			t.Fatal("parties disagree on the new public shares")
+		}
+	}
+}
+
+func TestResumeInvalid(t *testing.T) {
+	if _, err := state.Resume([]byte(`{"version":1,"roundType":"unknown.Round0"}`)); err == nil {
+		t.Error("resumed a state with an unknown round type")
+	}
+	if _, err := state.Resume([]byte(`{"version":2}`)); err == nil {
+		t.Error("resumed a state with an unsupported version")
+	}
+	if _, err := state.Resume([]byte(`{"roundNumber":1}`)); err == nil {
+		t.Error("resumed a state without round type")
+	}
+}